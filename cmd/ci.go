@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/mih-kopylov/bulker/cmd/ci"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateCiCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:   "ci",
+		Short: "Tracks CI builds of the repositories",
+		Long: `Tracks CI builds of the repositories with GitHub checks, GitLab pipelines or Bitbucket pipelines.
+The provider is detected by the repository URL unless it's configured with --hosting-provider parameter`,
+	}
+
+	result.AddCommand(ci.CreateStatusCommand(sh))
+
+	return result
+}
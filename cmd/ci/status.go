@@ -0,0 +1,82 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/hosting"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+func CreateStatusCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		ref string
+	}
+
+	var result = &cobra.Command{
+		Use:   "status",
+		Short: "Prints the status of the latest build",
+		Long: `Prints the state, the page and the duration of the latest build of a ref.
+The state is 'none' if there are no builds for the ref`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				type result struct {
+					State    hosting.BuildState
+					Ref      string
+					Url      string
+					Duration string
+				}
+
+				client, err := hosting.NewClient(runContext.Config, runContext.Repo.Url)
+				if err != nil {
+					return nil, err
+				}
+
+				ref := flags.ref
+				if ref == "" {
+					gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+					repoStatus, err := gitService.Status(runContext.Repo)
+					if err != nil {
+						return nil, fmt.Errorf("failed to get status: %w", err)
+					}
+					ref = repoStatus.Ref
+
+					// providers find builds of a commit only by its full hash
+					if repoStatus.Detached {
+						commits, err := gitService.GetCommits(
+							runContext.Repo, gitops.LogOptions{Ref: gitops.Head, MaxCount: 1},
+						)
+						if err != nil {
+							return nil, err
+						}
+						ref = commits[0].Id
+					}
+				}
+
+				build, err := client.GetBuild(ctx, ref)
+				if err != nil {
+					return nil, err
+				}
+
+				var duration string
+				if build.Duration > 0 {
+					duration = build.Duration.Round(time.Second).String()
+				}
+
+				return result{build.State, ref, build.Url, duration}, nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "", "Branch, tag or full commit hash to get the build of. The current branch is used if not set",
+	)
+
+	return result
+}
@@ -0,0 +1,112 @@
+package ci
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testResult struct {
+	Repo     string `json:"repo"`
+	State    string `json:"state,omitempty"`
+	Ref      string `json:"ref,omitempty"`
+	Url      string `json:"url"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+func TestStatus(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "bitbucket", Url: "git@bitbucket.org:workspace/bitbucket.git"},
+		{Name: "github", Url: "git@github.com:owner/github.git"},
+		{Name: "gitlab", Url: "git@gitlab.com:group/gitlab.git"},
+		{Name: "none", Url: "git@gitlab.com:group/none.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+	tests.PrepareHosting(
+		t, map[string]string{
+			"GET /repositories/workspace/bitbucket/pipelines": `{"values": [{"build_number": 12,
+				"state": {"name": "COMPLETED", "result": {"name": "FAILED"}}, "duration_in_seconds": 75}]}`,
+			"GET /repos/owner/github/commits/main/check-runs": `{"check_runs": [
+				{"status": "completed", "conclusion": "success", "html_url": "https://github.com/owner/github/runs/1",
+					"started_at": "2024-01-01T10:00:00Z", "completed_at": "2024-01-01T10:01:00Z"},
+				{"status": "completed", "conclusion": "skipped", "html_url": "https://github.com/owner/github/runs/2",
+					"started_at": "2024-01-01T10:00:30Z", "completed_at": "2024-01-01T10:02:00Z"}
+			]}`,
+			"GET /repos/owner/github/commits/main/status": `{"statuses": []}`,
+			"GET /projects/group%2Fgitlab/pipelines/latest": `{"status": "success", "duration": 3600,
+				"web_url": "https://gitlab.com/group/gitlab/-/pipelines/5"}`,
+		},
+	)
+
+	command := CreateStatusCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "--ref main")
+	assert.NoError(t, err)
+	assert.Equal(t, "status", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{
+					Repo: "bitbucket", State: "failed", Ref: "main",
+					Url: "https://bitbucket.org/workspace/bitbucket/pipelines/results/12", Duration: "1m15s",
+				},
+				{
+					Repo: "github", State: "success", Ref: "main", Url: "https://github.com/owner/github/runs/1",
+					Duration: "2m0s",
+				},
+				{
+					Repo: "gitlab", State: "success", Ref: "main", Url: "https://gitlab.com/group/gitlab/-/pipelines/5",
+					Duration: "1h0m0s",
+				},
+				{Repo: "none", State: "none", Ref: "main"},
+			},
+		), output,
+	)
+}
+
+func TestStatus_DetachedHead(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	repos := []settings.Repo{
+		{Name: "repo", Url: "git@gitlab.com:group/repo.git"},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git status --porcelain=v2 --branch": {Output: "# branch.oid " + hash + "\n# branch.head (detached)"},
+			"git --no-pager log --format=%H%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%s%x1f%b%x1e --max-count=1 HEAD": {
+				Output: hash + "\x1fAlice\x1falice@example.com\x1f2024-01-01T10:00:00Z\x1f" +
+					"Alice\x1falice@example.com\x1f2024-01-01T10:00:00Z\x1fsubject\x1f\x1e",
+			},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+	tests.PrepareHosting(
+		t, map[string]string{
+			"GET /projects/group%2Frepo/pipelines": `[{"id": 7}]`,
+			"GET /projects/group%2Frepo/pipelines/7": `{"status": "success", "duration": 60,
+				"web_url": "https://gitlab.com/group/repo/-/pipelines/7"}`,
+		},
+	)
+
+	command := CreateStatusCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{
+					Repo: "repo", State: "success", Ref: hash, Url: "https://gitlab.com/group/repo/-/pipelines/7",
+					Duration: "1m0s",
+				},
+			},
+		), output,
+	)
+}
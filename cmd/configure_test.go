@@ -6,6 +6,7 @@ import (
 	"github.com/mih-kopylov/bulker/internal/tests"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -56,3 +57,18 @@ func TestConfigure(t *testing.T) {
 		assert.Equal(t, 13, config.ReadConfig().MaxWorkers)
 	}
 }
+
+func TestConfigure_HostingTokenNotSaved(t *testing.T) {
+	testBulkerConfigFile := filepath.Join(t.TempDir(), "test_bulker.yaml")
+	conf := &config.Config{HostingToken: "secret"}
+	err := config.WriteConfig(conf, testBulkerConfigFile)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(testBulkerConfigFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "secret")
+
+	info, err := os.Stat(testBulkerConfigFile)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
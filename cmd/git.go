@@ -17,7 +17,15 @@ func CreateGitCommand(sh shell.Shell) *cobra.Command {
 	result.AddCommand(git.CreatePullCommand(sh))
 	result.AddCommand(git.CreatePushCommand(sh))
 	result.AddCommand(git.CreateBranchesCommand(sh))
+	result.AddCommand(git.CreateTagsCommand(sh))
 	result.AddCommand(git.CreateCommitCommand(sh))
+	result.AddCommand(git.CreateLogCommand(sh))
+	result.AddCommand(git.CreateRebaseCommand(sh))
+	result.AddCommand(git.CreateMergeCommand(sh))
+	result.AddCommand(git.CreateReleaseCommand(sh))
+	result.AddCommand(git.CreateStashCommand(sh))
+	result.AddCommand(git.CreateWorktreeCommand(sh))
+	result.AddCommand(git.CreateSubmodulesCommand(sh))
 
 	return result
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/mih-kopylov/bulker/internal/gitops"
 	"github.com/mih-kopylov/bulker/internal/runner"
 	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/templates"
 	"github.com/mih-kopylov/bulker/internal/utils"
 	"github.com/spf13/cobra"
 )
@@ -15,8 +16,9 @@ import (
 func CreateCheckoutCommand(sh shell.Shell) *cobra.Command {
 	var filter = runner.Filter{}
 	var flags struct {
-		name    string
-		discard bool
+		name      string
+		discard   bool
+		autostash bool
 	}
 
 	var result = &cobra.Command{
@@ -29,45 +31,84 @@ func CreateCheckoutCommand(sh shell.Shell) *cobra.Command {
 					Checkout gitops.CheckoutResult
 					Ref      string
 				}
+				type autostashResult struct {
+					Status   gitops.StatusResult
+					Checkout gitops.CheckoutResult
+					Ref      string
+					Stash    gitops.StashResult
+				}
+				newResult := func(
+					status gitops.StatusResult, checkoutResult gitops.CheckoutResult, ref string,
+					stashResult gitops.StashResult,
+				) interface{} {
+					if flags.autostash {
+						return autostashResult{status, checkoutResult, ref, stashResult}
+					}
+					return result{status, checkoutResult, ref}
+				}
+
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 
-				gitService := gitops.NewGitService(sh)
+				name, err := templates.Render(&gitService, runContext.Repo, flags.name)
+				if err != nil {
+					return nil, err
+				}
 
 				if flags.discard {
 					err := gitService.Discard(runContext.Repo)
 					if errors.Is(err, fileops.ErrRepositoryNotCloned) {
-						return result{gitops.StatusMissing, "", ""}, nil
+						return newResult(gitops.StatusMissing, "", "", ""), nil
 					}
 					if err != nil {
 						return nil, fmt.Errorf("failed to discard: %w", err)
 					}
 				}
 
-				checkoutResult, err := gitService.Checkout(runContext.Repo, flags.name)
+				var checkoutResult gitops.CheckoutResult
+				checkout := func() error {
+					var err error
+					checkoutResult, err = gitService.Checkout(runContext.Repo, name)
+					return err
+				}
+
+				var stashResult gitops.StashResult
+				if flags.autostash {
+					stashResult, err = gitService.WithAutostash(runContext.Repo, checkout)
+				} else {
+					err = checkout()
+				}
 				if err != nil {
 					if errors.Is(err, fileops.ErrRepositoryNotCloned) {
-						return result{gitops.StatusMissing, "", ""}, nil
+						return newResult(gitops.StatusMissing, "", "", ""), nil
 					}
 					return nil, fmt.Errorf("failed to checkout: %w", err)
 				}
 
-				statusResult, ref, err := gitService.Status(runContext.Repo)
+				repoStatus, err := gitService.Status(runContext.Repo)
 				if err != nil {
 					return nil, fmt.Errorf("failed to get status: %w", err)
 				}
 
-				return result{statusResult, checkoutResult, ref}, nil
+				return newResult(repoStatus.Status, checkoutResult, repoStatus.Ref, stashResult), nil
 			},
 		),
 	}
 
 	filter.AddCommandFlags(result)
 
-	result.Flags().StringVarP(&flags.name, "branch", "b", "", "Name of the branch to checkout")
+	result.Flags().StringVarP(
+		&flags.name, "branch", "b", "", "Name of the branch to checkout, rendered as a template for every repository",
+	)
 	utils.MarkFlagRequiredOrFail(result.Flags(), "branch")
 
 	result.Flags().BoolVarP(
 		&flags.discard, "discard", "d", false, "Discards all local changes in the repository before checkout",
 	)
+	result.Flags().BoolVar(
+		&flags.autostash, "autostash", false,
+		"Stashes local changes in the repository before checkout and restores them after",
+	)
+	result.MarkFlagsMutuallyExclusive("discard", "autostash")
 
 	return result
 }
@@ -25,9 +25,9 @@ func TestCheckout(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/heads/br"},
-			"git checkout br":                   {Output: "Switched to branch 'br'"},
-			"git status":                        {Output: "On branch br\nnothing to commit, working tree clean"},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/heads/br"},
+			"git checkout br":                    {Output: "Switched to branch 'br'"},
+			"git status --porcelain=v2 --branch": {Output: "# branch.oid 0123456789abcdef\n# branch.head br"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -61,11 +61,11 @@ func TestCheckout_FromDetachedHead(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {
 				Output: "(HEAD detached at 0123456)\nrefs/heads/master\nrefs/heads/br",
 			},
-			"git checkout br": {Output: "Switched to branch 'br'"},
-			"git status":      {Output: "On branch br\nnothing to commit, working tree clean"},
+			"git checkout br":                    {Output: "Switched to branch 'br'"},
+			"git status --porcelain=v2 --branch": {Output: "# branch.oid 0123456789abcdef\n# branch.head br"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -99,10 +99,10 @@ func TestCheckout_Discard(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git reset --hard HEAD":             {Output: "OK"},
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/heads/br"},
-			"git checkout br":                   {Output: "Switched to branch 'br'"},
-			"git status":                        {Output: "On branch br\nnothing to commit, working tree clean"},
+			"git reset --hard HEAD": {Output: "OK"},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/heads/br"},
+			"git checkout br":                    {Output: "Switched to branch 'br'"},
+			"git status --porcelain=v2 --branch": {Output: "# branch.oid 0123456789abcdef\n# branch.head br"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -162,3 +162,63 @@ func TestCheckout_RequiredFlags(t *testing.T) {
 		)
 	}
 }
+
+type testAutostashResult struct {
+	Repo     string `json:"repo"`
+	Error    string `json:"error,omitempty"`
+	Status   string `json:"status"`
+	Checkout string `json:"checkout"`
+	Ref      string `json:"ref"`
+	Stash    string `json:"stash"`
+}
+
+func TestCheckout_Autostash(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/heads/br"},
+			"git status --porcelain=v2 --branch": {
+				Output: "# branch.oid 0123456789abcdef\n# branch.head br\n1 .M N... 100644 100644 100644 0123 4567 file.txt",
+			},
+			"git stash push --message bulker autostash": {Output: "Saved working directory"},
+			"git checkout br": {Output: "Switched to branch 'br'"},
+			"git stash pop":   {Output: "Dropped refs/stash@{0}"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateCheckoutCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo -b br --autostash")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testAutostashResult{
+				{
+					Repo:     "repo",
+					Status:   "dirty",
+					Ref:      "br",
+					Checkout: "success",
+					Stash:    "restored",
+				},
+			},
+		), output,
+	)
+}
+
+func TestCheckout_AutostashWithDiscard(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, nil)
+
+	command := CreateCheckoutCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-b br --autostash --discard")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "[autostash discard] were all set")
+	}
+}
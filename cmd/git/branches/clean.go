@@ -23,7 +23,7 @@ First, it defines the default branch of the remote.
 Then, it loops over the branches and removes the ones that don't have differences with the default one`,
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 				cleanResult, err := gitService.CleanBranches(runContext.Repo, flags.mode)
 				if err != nil {
 					return nil, err
@@ -2,29 +2,97 @@ package branches
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/mih-kopylov/bulker/internal/config"
 	"github.com/mih-kopylov/bulker/internal/gitops"
 	"github.com/mih-kopylov/bulker/internal/runner"
 	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
 	"github.com/spf13/cobra"
-	"strings"
 )
 
+type branchSort string
+
+const (
+	branchSortName   branchSort = "name"
+	branchSortDate   branchSort = "date"
+	branchSortAuthor branchSort = "author"
+	branchSortAhead  branchSort = "ahead"
+	branchSortBehind branchSort = "behind"
+)
+
+func (s *branchSort) String() string {
+	return string(*s)
+}
+
+func (s *branchSort) Set(v string) error {
+	switch v {
+	case string(branchSortName), string(branchSortDate), string(branchSortAuthor), string(branchSortAhead),
+		string(branchSortBehind):
+		*s = branchSort(v)
+		return nil
+	default:
+		return fmt.Errorf(
+			"must be one of '%s' '%s' '%s' '%s' '%s'", branchSortName, branchSortDate, branchSortAuthor,
+			branchSortAhead, branchSortBehind,
+		)
+	}
+}
+
+func (s *branchSort) Type() string {
+	return "BranchSort"
+}
+
+// compare orders branches by the sort field. Dates and commit counts are ordered from the greatest
+func (s *branchSort) compare(a gitops.BranchInfo, b gitops.BranchInfo) int {
+	switch *s {
+	case branchSortDate:
+		return b.LastCommit.CommitDate.Compare(a.LastCommit.CommitDate)
+	case branchSortAuthor:
+		return strings.Compare(a.LastCommit.Author.String(), b.LastCommit.Author.String())
+	case branchSortAhead:
+		return b.Ahead - a.Ahead
+	case branchSortBehind:
+		return b.Behind - a.Behind
+	default:
+		return strings.Compare(a.Branch.Short(), b.Branch.Short())
+	}
+}
+
 func CreateListCommand(sh shell.Shell) *cobra.Command {
 	var filter = runner.Filter{}
 	var flags struct {
 		mode    config.GitMode
 		pattern string
+		ref     string
+		age     string
+		sort    branchSort
 	}
+	flags.sort = branchSortName
 
 	var result = &cobra.Command{
 		Use:   "list",
 		Short: "Prints a list of repository branches",
-		Long: `Prints a list of repository branches.
+		Long: `Prints a list of repository branches with the date and the author of their last commit,
+the upstream branch, the number of commits ahead and behind the reference branch, and whether they are merged to it.
 If a repository doesn't have any branch matching pattern, the repository will be omitted in the result`,
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
+				type branchResult struct {
+					Branch   string
+					Date     string
+					Author   string
+					Upstream string
+					Ahead    int
+					Behind   int
+					Merged   bool
+				}
+
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 				branches, err := gitService.GetBranches(runContext.Repo, flags.mode, flags.pattern)
 				if err != nil {
 					return nil, err
@@ -34,12 +102,56 @@ If a repository doesn't have any branch matching pattern, the repository will be
 					return nil, nil
 				}
 
-				builder := strings.Builder{}
-				for _, branch := range branches {
-					builder.WriteString(branch.Short() + "\n")
+				ref := flags.ref
+				if ref == "" {
+					defaultBranch, err := gitService.GetDefaultBranch(runContext.Repo)
+					if err != nil {
+						return nil, err
+					}
+
+					ref = defaultBranch.Short()
+				}
+
+				infos, err := gitService.GetBranchesInfo(runContext.Repo, branches, ref)
+				if err != nil {
+					return nil, err
+				}
+
+				if flags.age != "" {
+					latestCommitTime, err := utils.AgeToTime(&utils.RealClock{}, flags.age)
+					if err != nil {
+						return nil, err
+					}
+
+					infos = slices.DeleteFunc(
+						infos, func(info gitops.BranchInfo) bool {
+							return !latestCommitTime.After(info.LastCommit.CommitDate)
+						},
+					)
+				}
+
+				if len(infos) == 0 {
+					return nil, nil
+				}
+
+				slices.SortStableFunc(infos, flags.sort.compare)
+
+				var branchResults []branchResult
+				for _, info := range infos {
+					branchResults = append(
+						branchResults, branchResult{
+							Branch:   info.Branch.Short(),
+							Date:     info.LastCommit.CommitDate.In(time.Local).Format(time.RFC3339),
+							Author:   info.LastCommit.Author.String(),
+							Upstream: info.Upstream,
+							Ahead:    info.Ahead,
+							Behind:   info.Behind,
+							Merged:   info.Merged(),
+						},
+					)
 				}
 
-				return strings.TrimSpace(builder.String()), nil
+				return branchResults, nil
 			},
 		),
 	}
@@ -48,6 +160,20 @@ If a repository doesn't have any branch matching pattern, the repository will be
 
 	config.AddGitModeFlag(&flags.mode, result.Flags())
 	result.Flags().StringVarP(&flags.pattern, "pattern", "p", ".*", "Regexp pattern of the branches to show")
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "",
+		`Git reference to compare branches with. If not set, the default repository branch is set`,
+	)
+	result.Flags().StringVarP(
+		&flags.age, "age", "a", "", `Minimal age of the last commit in branches to show them, like '2w'`,
+	)
+	result.Flags().Var(
+		&flags.sort, "sort", fmt.Sprintf(
+			"How to sort the branches. Available fields are: %s, %s, %s, %s, %s. "+
+				"Dates and commit counts are sorted from the greatest",
+			branchSortName, branchSortDate, branchSortAuthor, branchSortAhead, branchSortBehind,
+		),
+	)
 
 	return result
 }
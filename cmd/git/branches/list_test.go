@@ -0,0 +1,152 @@
+package branches
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testListResult struct {
+	Repo     string `json:"repo"`
+	Error    string `json:"error,omitempty"`
+	Branch   string `json:"branch"`
+	Date     string `json:"date"`
+	Author   string `json:"author"`
+	Upstream string `json:"upstream"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Merged   bool   `json:"merged"`
+}
+
+const testListBranchCommits = "git for-each-ref --format=%(refname)%1f%(objectname)%1f%(authorname)%1f" +
+	"%(authoremail:trim)%1f%(authordate:iso-strict)%1f%(committername)%1f%(committeremail:trim)%1f" +
+	"%(committerdate:iso-strict)%1f%(subject)%1f%(body)%1e refs/heads/ refs/remotes/"
+
+func testListCommit(refName string, author string, date string) string {
+	return strings.Join(
+		[]string{
+			refName, "0123456789", author, author + "@example.com", date, author, author + "@example.com", date,
+			"subject", "",
+		},
+		"\x1f",
+	) + "\x1e\n"
+}
+
+func testListDate(date string) string {
+	parsed, _ := time.Parse(time.RFC3339, date)
+	return parsed.In(time.Local).Format(time.RFC3339)
+}
+
+func prepareList(t *testing.T) shell.Shell {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com"},
+	}
+	responses := map[string]string{
+		"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/":       "refs/heads/main\nrefs/heads/feature\nrefs/remotes/origin/main",
+		"git for-each-ref --format=%(refname)%1f%(upstream:short) refs/heads/": "refs/heads/main\x1forigin/main\nrefs/heads/feature\x1f\n",
+		testListBranchCommits: testListCommit("refs/heads/main", "alice", "2024-03-01T10:00:00Z") +
+			testListCommit("refs/heads/feature", "bob", "2024-02-01T10:00:00Z") +
+			testListCommit("refs/remotes/origin/main", "alice", "2024-01-01T10:00:00Z"),
+		"git rev-list --left-right --count feature...main":     "2\t1\n",
+		"git rev-list --left-right --count main...main":        "0\t0\n",
+		"git rev-list --left-right --count origin/main...main": "0\t1\n",
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			commandLine := tests.ShellCommandToString(command, arguments)
+			if output, found := responses[commandLine]; found {
+				return output, nil
+			}
+			return "", fmt.Errorf("shell not mocked: %v %v", repoName, commandLine)
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	return sh
+}
+
+var (
+	testListMain = testListResult{
+		Repo: "repo", Branch: "main", Date: testListDate("2024-03-01T10:00:00Z"),
+		Author: "alice <alice@example.com>", Upstream: "origin/main", Merged: true,
+	}
+	testListFeature = testListResult{
+		Repo: "repo", Branch: "feature", Date: testListDate("2024-02-01T10:00:00Z"),
+		Author: "bob <bob@example.com>", Ahead: 2, Behind: 1,
+	}
+	testListOriginMain = testListResult{
+		Repo: "repo", Branch: "origin/main", Date: testListDate("2024-01-01T10:00:00Z"),
+		Author: "alice <alice@example.com>", Behind: 1, Merged: true,
+	}
+)
+
+func TestList(t *testing.T) {
+	sh := prepareList(t)
+
+	command := CreateListCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-r main -m all")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "list", c.Name())
+		assert.JSONEq(
+			t, tests.ToJsonString([]testListResult{testListFeature, testListMain, testListOriginMain}), output,
+		)
+	}
+}
+
+func TestList_Sort(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     string
+		expected []testListResult
+	}{
+		{name: "date", args: "--sort date", expected: []testListResult{testListMain, testListFeature, testListOriginMain}},
+		{name: "author", args: "--sort author", expected: []testListResult{testListMain, testListOriginMain, testListFeature}},
+		{name: "ahead", args: "--sort ahead", expected: []testListResult{testListFeature, testListMain, testListOriginMain}},
+		{name: "behind", args: "--sort behind", expected: []testListResult{testListFeature, testListOriginMain, testListMain}},
+	}
+
+	for _, tt := range cases {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				sh := prepareList(t)
+
+				command := CreateListCommand(sh)
+				_, output, err := tests.ExecuteCommand(command, "-r main -m all "+tt.args)
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, tests.ToJsonString(tt.expected), output)
+				}
+			},
+		)
+	}
+}
+
+func TestList_Age(t *testing.T) {
+	sh := prepareList(t)
+
+	command := CreateListCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-r main -m local --age 1d --sort date")
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, tests.ToJsonString([]testListResult{testListMain, testListFeature}), output)
+	}
+}
+
+func TestList_UnsupportedSort(t *testing.T) {
+	sh := prepareList(t)
+
+	command := CreateListCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--sort size")
+	if assert.Error(t, err) {
+		assert.Equal(
+			t, `invalid argument "size" for "--sort" flag: must be one of 'name' 'date' 'author' 'ahead' 'behind'`,
+			err.Error(),
+		)
+	}
+}
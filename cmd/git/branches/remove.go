@@ -24,7 +24,7 @@ func CreateRemoveCommand(sh shell.Shell) *cobra.Command {
 		Short: "Remove a branch",
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 				branches, err := gitService.GetBranches(runContext.Repo, flags.mode, flags.name)
 				if err != nil {
 					return nil, err
@@ -24,8 +24,8 @@ func TestRemove_Local(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/heads/br"},
-			"git branch -D br":                  {Output: "OK"},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/heads/br"},
+			"git branch -D br": {Output: "OK"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -57,8 +57,8 @@ func TestRemove_Remote(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/remotes/origin/br"},
-			"git push origin --delete br":       {Output: "OK"},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/remotes/origin/br"},
+			"git push origin --delete br":                                    {Output: "OK"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -90,7 +90,7 @@ func TestRemove_RemoteWhenOnlyLocalFound(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/heads/br"},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/heads/br"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -117,7 +117,7 @@ func TestRemove_LocalWhenOnlyRemoteFound(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/remotes/origin/br"},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/remotes/origin/br"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -144,8 +144,8 @@ func TestRemove_NotFound(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/heads/br1"},
-			"git branch -D br":                  {Output: "OK"},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/heads/br1"},
+			"git branch -D br": {Output: "OK"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -172,9 +172,9 @@ func TestRemove_FailedToRemove(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git branch -a --format=%(refname)": {Output: "refs/heads/master\nrefs/heads/br\nrefs/remotes/origin/br"},
-			"git branch -D br":                  {Output: "fail output", Error: fmt.Errorf("fail reason")},
-			"git push origin --delete br":       {Output: "fail output", Error: fmt.Errorf("fail reason")},
+			"git for-each-ref --format=%(refname) refs/heads/ refs/remotes/": {Output: "refs/heads/master\nrefs/heads/br\nrefs/remotes/origin/br"},
+			"git branch -D br":            {Output: "fail output", Error: fmt.Errorf("fail reason")},
+			"git push origin --delete br": {Output: "fail output", Error: fmt.Errorf("fail reason")},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -38,7 +38,7 @@ than specified age.`,
 					LastCommit string
 				}
 
-				gitService := gitops.NewGitService(sh)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 
 				latestCommitTime, err := utils.AgeToTime(&utils.RealClock{}, flags.age)
 				if err != nil {
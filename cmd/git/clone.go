@@ -20,7 +20,7 @@ func CreateCloneCommand(sh shell.Shell) *cobra.Command {
 		Short: "Clones the configured repositories out if they have not been yet",
 		RunE: runner.NewCommandRunner(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 				cloneResult, err := gitService.CloneRepo(runContext.Repo, flags.recreate)
 				if err != nil {
 					return nil, fmt.Errorf("failed to clone: %w", err)
@@ -31,7 +31,7 @@ func CreateCloneCommand(sh shell.Shell) *cobra.Command {
 		),
 	}
 
-	filter.AddCommandFlags(result)
+	filter.AddRepoFlags(result)
 
 	result.Flags().BoolVar(
 		&flags.recreate, "recreate", false,
@@ -24,8 +24,8 @@ func TestClone(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git status":                      {Output: "OK"},
-			"git clone https://example.com .": {Output: "OK"},
+			"git status --porcelain=v2 --branch":                   {Output: "OK"},
+			"git clone --recurse-submodules https://example.com .": {Output: "OK"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -55,8 +55,8 @@ func TestClone_EmptyDirectoryExists(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git status":                      {Output: "OK"},
-			"git clone https://example.com .": {Output: "OK"},
+			"git status --porcelain=v2 --branch":                   {Output: "OK"},
+			"git clone --recurse-submodules https://example.com .": {Output: "OK"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -88,7 +88,7 @@ func TestClone_NotEmptyDirectoryExists(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git status": {Output: "err", Error: fmt.Errorf("not a repository")},
+			"git status --porcelain=v2 --branch": {Output: "err", Error: fmt.Errorf("not a repository")},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -122,10 +122,10 @@ func TestClone_Recreate(t *testing.T) {
 	}
 	sh := tests.MockShellMap(
 		map[string]tests.MockResult{
-			"git status": {
-				Output: "On branch custom\nYour branch is up to date with 'origin/custom'.",
+			"git status --porcelain=v2 --branch": {
+				Output: "# branch.oid 0123456789abcdef\n# branch.head custom\n# branch.upstream origin/custom\n# branch.ab +0 -0",
 			},
-			"git clone https://example.com .": {Output: "OK"},
+			"git clone --recurse-submodules https://example.com .": {Output: "OK"},
 		},
 	)
 	tests.PrepareBulker(t, sh, repos)
@@ -2,47 +2,128 @@ package git
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/mih-kopylov/bulker/internal/gitops"
 	"github.com/mih-kopylov/bulker/internal/runner"
 	"github.com/mih-kopylov/bulker/internal/shell"
-	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/mih-kopylov/bulker/internal/templates"
 	"github.com/spf13/cobra"
+	"io"
+	"os"
 )
 
 func CreateCommitCommand(sh shell.Shell) *cobra.Command {
 	var filter = runner.Filter{}
 
 	var flags struct {
-		message string
-		pattern string
+		options     gitops.CommitOptions
+		messageFile string
+		template    bool
 	}
 
 	var result = &cobra.Command{
 		Use:   "commit",
 		Short: "Commit changes",
+		Long: `Commit changes.
+Repositories without changes are reported with 'nothing to commit' result.
+A multi-line message can be read from a file, lines starting with '#' are skipped as in git commit templates.
+
+The message is committed as is. With --template parameter it's rendered for every repository.
+` + templates.Help + `
+
+Example:
+
+    bulker git commit --template -m 'chore({{.Name}}): bump parent to {{.Prop "pom.xml" "$.parent.version"}}'
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if flags.messageFile == "" {
+				if flags.options.Message == "" && !flags.options.Amend {
+					return errors.New("either 'message' or 'message-file' flags should be set unless 'amend' is set")
+				}
+				return nil
+			}
+
+			message, err := readMessageFile(cmd, flags.messageFile)
+			if err != nil {
+				return err
+			}
+
+			flags.options.Message = message
+			flags.options.StripComments = true
+			return nil
+		},
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
-				err := gitService.Commit(runContext.Repo, flags.pattern, flags.message)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				options := flags.options
+				if flags.template {
+					message, err := templates.Render(&gitService, runContext.Repo, options.Message)
+					if err != nil {
+						return nil, err
+					}
+					options.Message = message
+				}
+
+				commitResult, err := gitService.Commit(runContext.Repo, options)
 				if err != nil {
 					return nil, err
 				}
 
-				return "committed", nil
+				return commitResult, nil
 			},
 		),
 	}
 
 	filter.AddCommandFlags(result)
 
-	result.Flags().StringVarP(&flags.message, "message", "m", "", "Commit message")
-	utils.MarkFlagRequiredOrFail(result.Flags(), "message")
+	result.Flags().StringVarP(
+		&flags.options.Message, "message", "m", "", "Commit message",
+	)
+	result.Flags().StringVarP(
+		&flags.messageFile, "message-file", "F", "", "File to read the commit message from. Use '-' for stdin",
+	)
+	result.MarkFlagsMutuallyExclusive("message", "message-file")
+	result.Flags().BoolVar(
+		&flags.template, "template", false,
+		"Render the commit message as a template. The message is committed as is otherwise, even if it contains '{{'",
+	)
 
 	result.Flags().StringVarP(
-		&flags.pattern, "pattern", "p", "**", `File pattern to commit.
+		&flags.options.Pattern, "pattern", "p", "**", `File pattern to commit.
 See https://git-scm.com/docs/gitglossary#Documentation/gitglossary.txt-glob for documentation.
 If missed, all added/changed/removed files will be committed.'`,
 	)
+	result.Flags().StringVar(
+		&flags.options.Author, "author", "", `Author of the commit, like "Bot <bot@example.com>"`,
+	)
+	result.Flags().StringSliceVar(
+		&flags.options.CoAuthors, "co-author", []string{}, `Co-author of the commit, like "Name <name@example.com>"`,
+	)
+	result.Flags().BoolVarP(
+		&flags.options.SignOff, "signoff", "s", false, "Add Signed-off-by trailer of the committer",
+	)
+	result.Flags().BoolVar(
+		&flags.options.Amend, "amend", false,
+		"Replace the last commit. Its message is kept unless a new one is provided",
+	)
 
 	return result
 }
+
+func readMessageFile(cmd *cobra.Command, fileName string) (string, error) {
+	if fileName == "-" {
+		content, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", fmt.Errorf("failed to read commit message from stdin: %w", err)
+		}
+		return string(content), nil
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit message: %w", err)
+	}
+
+	return string(content), nil
+}
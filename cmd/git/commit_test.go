@@ -73,3 +73,151 @@ func TestCommit_Pattern(t *testing.T) {
 		), output,
 	)
 }
+
+func TestCommit_NothingToCommit(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git add **":                {Output: "OK"},
+			"git commit -m message":     {Output: "nothing to commit", Error: tests.MockExitError(1)},
+			"git diff --cached --quiet": {Output: ""},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateCommitCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo -m message")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Result: "nothing to commit"}}), output)
+}
+
+func TestCommit_MessageFile(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			switch tests.ShellCommandToString(command, arguments) {
+			case "git add **":
+				return "OK", nil
+			case "git commit -m Subject\n\n# comment\nBody\n --cleanup=strip --author=Bot <bot@example.com> " +
+				"--trailer Co-authored-by: Alice <alice@example.com> --signoff --amend":
+				return "OK", nil
+			}
+			return "", assert.AnError
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+	messageFileName := tests.Path("message.txt")
+	err = os.WriteFile(messageFileName, []byte("Subject\n\n# comment\nBody\n"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateCommitCommand(sh)
+	err = command.Flags().Set("author", "Bot <bot@example.com>")
+	assert.NoError(t, err)
+	err = command.Flags().Set("co-author", "Alice <alice@example.com>")
+	assert.NoError(t, err)
+	_, output, err := tests.ExecuteCommand(command, "-n repo -F "+messageFileName+" -s --amend")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Result: "amended"}}), output)
+}
+
+func TestCommit_NoMessage(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateCommitCommand(sh)
+	_, _, err = tests.ExecuteCommand(command, "-n repo")
+	assert.EqualError(t, err, "either 'message' or 'message-file' flags should be set unless 'amend' is set")
+}
+
+func TestCommit_AmendWithoutMessage(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git add **":                   {Output: "OK"},
+			"git commit --no-edit --amend": {Output: "OK"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateCommitCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo --amend")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Result: "amended"}}), output)
+}
+
+func TestCommit_Template(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "first", Url: "https://example.com/first"},
+		{Name: "second", Url: "https://example.com/second"},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git add **":                   {Output: "OK"},
+			"git commit -m chore(first):":  {Output: "OK"},
+			"git commit -m chore(second):": {Output: "OK"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	command := CreateCommitCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "--template -m chore({{.Name}}):")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{{Repo: "first", Result: "committed"}, {Repo: "second", Result: "committed"}},
+		), output,
+	)
+}
+
+func TestCommit_MessageWithoutTemplate(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com"},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git add **":                 {Output: "OK"},
+			"git commit -m docs:{{.Name": {Output: "OK"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateCommitCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-m docs:{{.Name")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Result: "committed"}}), output)
+}
@@ -16,7 +16,7 @@ func CreateFetchCommand(sh shell.Shell) *cobra.Command {
 		Short: "Fetch changes from remote",
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 				err := gitService.Fetch(runContext.Repo)
 				if err != nil {
 					return nil, err
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"fmt"
 	"github.com/mih-kopylov/bulker/internal/settings"
 	"github.com/mih-kopylov/bulker/internal/tests"
 	"github.com/stretchr/testify/assert"
@@ -39,3 +40,46 @@ func TestFetch(t *testing.T) {
 		), output,
 	)
 }
+
+func TestFetch_Worktree(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			if repoName == "review" && tests.ShellCommandToString(command, arguments) == "git fetch --prune" {
+				return "OK", nil
+			}
+			return "", fmt.Errorf("shell not mocked: %v %v", repoName, tests.ShellCommandToString(command, arguments))
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.MkdirAll(tests.Path(".worktrees", "repo", "review"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateFetchCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo --worktree review")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Result: "fetched"}}), output)
+}
+
+func TestFetch_WorktreeNotFound(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateFetchCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo --worktree review")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Error: "worktree not found"}}), output)
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+type integrateFunc func(gitService *gitops.GitService, repo *model.Repo, ref string) (
+	gitops.IntegrateResult, []string, error,
+)
+
+// createIntegrateCommand creates a command that updates the current branch with changes of another ref
+func createIntegrateCommand(sh shell.Shell, result *cobra.Command, integrate integrateFunc) *cobra.Command {
+	var filter = runner.Filter{}
+
+	var flags struct {
+		ref string
+	}
+
+	result.RunE = runner.NewCommandRunnerForExistingRepos(
+		&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+			type integrateResult struct {
+				Result    gitops.IntegrateResult
+				Ref       string
+				Conflicts string
+			}
+
+			gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+
+			ref := flags.ref
+			if ref == "" {
+				defaultBranch, err := gitService.GetDefaultBranch(runContext.Repo)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get default branch: %w", err)
+				}
+				ref = defaultBranch.Short()
+			}
+
+			integrationResult, conflicts, err := integrate(&gitService, runContext.Repo, ref)
+			if err != nil {
+				return nil, err
+			}
+
+			return integrateResult{integrationResult, ref, strings.Join(conflicts, ", ")}, nil
+		},
+	)
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "", `Ref to take the changes from.
+Defaults to the remote default branch, so run "bulker git fetch" first to get the latest changes`,
+	)
+
+	return result
+}
@@ -0,0 +1,206 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type logGroup string
+
+const (
+	logGroupRepo   logGroup = "repo"
+	logGroupAuthor logGroup = "author"
+)
+
+func (g *logGroup) String() string {
+	return string(*g)
+}
+
+func (g *logGroup) Set(v string) error {
+	switch v {
+	case string(logGroupRepo), string(logGroupAuthor):
+		*g = logGroup(v)
+		return nil
+	default:
+		return fmt.Errorf("must be one of '%s' '%s'", logGroupRepo, logGroupAuthor)
+	}
+}
+
+func (g *logGroup) Type() string {
+	return "LogGroup"
+}
+
+func CreateLogCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		ref     string
+		since   string
+		until   string
+		authors []string
+		paths   []string
+		groupBy logGroup
+		// options are parsed from the flags once for all repositories
+		options gitops.LogOptions
+	}
+	flags.groupBy = logGroupRepo
+
+	var result = &cobra.Command{
+		Use:   "log",
+		Short: "Prints commits of the repositories",
+		Long: `Prints commits of the repositories, the most recently committed first.
+The commits can be grouped by repository or by author to get a changelog.
+If a repository doesn't have any matching commit, the repository will be omitted in the result`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			options := gitops.LogOptions{Authors: flags.authors, Paths: flags.paths}
+			var err error
+			options.Ref, options.Exclude, err = gitops.ParseRefRange(flags.ref)
+			if err != nil {
+				return err
+			}
+
+			if flags.since != "" {
+				options.Since, err = utils.AgeToTime(&utils.RealClock{}, flags.since)
+				if err != nil {
+					return err
+				}
+			}
+			if flags.until != "" {
+				options.Until, err = utils.AgeToTime(&utils.RealClock{}, flags.until)
+				if err != nil {
+					return err
+				}
+			}
+
+			flags.options = options
+			return nil
+		},
+		RunE: runner.NewGroupingCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				commits, err := gitService.GetCommits(runContext.Repo, flags.options)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(commits) == 0 {
+					return nil, nil
+				}
+
+				return commits, nil
+			},
+			func(reposResult map[string]runner.ProcessResult) (string, map[string]runner.ProcessResult) {
+				if flags.groupBy == logGroupAuthor {
+					return string(logGroupAuthor), groupCommitsByAuthor(reposResult)
+				}
+				return string(logGroupRepo), formatRepoCommits(reposResult)
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "",
+		"Git reference or a two-dot range like 'v1.0.0..main' to print commits of. HEAD is used by default",
+	)
+	result.Flags().StringVar(&flags.since, "since", "", "Age of the oldest commits to print, like '1w'")
+	result.Flags().StringVar(&flags.until, "until", "", "Age of the newest commits to print, like '1d'")
+	result.Flags().StringSliceVarP(
+		&flags.authors, "author", "a", []string{}, `Regexp pattern of the commit author "Name <email>"`,
+	)
+	result.Flags().StringSliceVarP(
+		&flags.paths, "path", "p", []string{}, "Path in the repository that commits have to change",
+	)
+	result.Flags().Var(
+		&flags.groupBy, "group-by",
+		fmt.Sprintf("How to group the commits. Available types are: %s, %s", logGroupRepo, logGroupAuthor),
+	)
+
+	return result
+}
+
+// formatRepoCommits prints commits of every repository line by line
+func formatRepoCommits(reposResult map[string]runner.ProcessResult) map[string]runner.ProcessResult {
+	result := map[string]runner.ProcessResult{}
+	for repoName, repoResult := range reposResult {
+		commits, ok := repoResult.Result.([]gitops.Commit)
+		if !ok {
+			result[repoName] = repoResult
+			continue
+		}
+
+		var lines []string
+		for _, commit := range commits {
+			lines = append(
+				lines, fmt.Sprintf(
+					"%v %v (%v, %v)", commit.ShortId(), commit.Subject, commit.Author.Name, formatCommitDate(commit),
+				),
+			)
+		}
+		result[repoName] = runner.ProcessResult{Result: strings.Join(lines, "\n")}
+	}
+
+	return result
+}
+
+// groupCommitsByAuthor prints commits of every author from all repositories, the most recently committed first.
+// Repositories that failed are kept with "repo:" prefix
+func groupCommitsByAuthor(reposResult map[string]runner.ProcessResult) map[string]runner.ProcessResult {
+	type repoCommit struct {
+		repo   string
+		commit gitops.Commit
+	}
+
+	result := map[string]runner.ProcessResult{}
+	authorCommits := map[string][]repoCommit{}
+	for _, repoName := range slices.Sorted(maps.Keys(reposResult)) {
+		repoResult := reposResult[repoName]
+		commits, ok := repoResult.Result.([]gitops.Commit)
+		if !ok {
+			if repoResult.Error != nil {
+				result["repo:"+repoName] = repoResult
+			}
+			continue
+		}
+
+		for _, commit := range commits {
+			author := commit.Author.String()
+			authorCommits[author] = append(authorCommits[author], repoCommit{repoName, commit})
+		}
+	}
+
+	for author, commits := range authorCommits {
+		slices.SortStableFunc(
+			commits, func(a repoCommit, b repoCommit) int {
+				return b.commit.CommitDate.Compare(a.commit.CommitDate)
+			},
+		)
+
+		var lines []string
+		for _, commit := range commits {
+			lines = append(
+				lines, fmt.Sprintf(
+					"%v: %v %v (%v)", commit.repo, commit.commit.ShortId(), commit.commit.Subject,
+					formatCommitDate(commit.commit),
+				),
+			)
+		}
+		result[author] = runner.ProcessResult{Result: strings.Join(lines, "\n")}
+	}
+
+	return result
+}
+
+func formatCommitDate(commit gitops.Commit) string {
+	return commit.AuthorDate.In(time.Local).Format(time.DateOnly)
+}
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+type testAuthorResult struct {
+	Author string `json:"author"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+const testLogCommand = "git --no-pager log " +
+	"--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%s%x1f%b%x1e --extended-regexp --author=example.com " +
+	"main --not v1.0.0 -- src"
+
+func testLogCommit(id string, author string, date string, subject string) string {
+	return strings.Join(
+		[]string{id, author, author + "@example.com", date, author, author + "@example.com", date, subject, ""},
+		"\x1f",
+	) + "\x1e\n"
+}
+
+func prepareLog(t *testing.T) shell.Shell {
+	repos := []settings.Repo{
+		{Name: "first", Url: "https://example.com/first"},
+		{Name: "second", Url: "https://example.com/second"},
+		{Name: "failed", Url: "https://example.com/failed"},
+		{Name: "empty", Url: "https://example.com/empty"},
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			if tests.ShellCommandToString(command, arguments) != testLogCommand {
+				return "", assert.AnError
+			}
+
+			switch repoName {
+			case "first":
+				return testLogCommit("1111111111", "alice", "2024-01-03T12:00:00Z", "Fix first") +
+					testLogCommit("2222222222", "bob", "2024-01-01T12:00:00Z", "Add first"), nil
+			case "second":
+				return testLogCommit("3333333333", "alice", "2024-01-02T12:00:00Z", "Add second"), nil
+			case "empty":
+				return "", nil
+			}
+			return "fatal: bad revision", assert.AnError
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	return sh
+}
+
+func TestLog(t *testing.T) {
+	sh := prepareLog(t)
+
+	command := CreateLogCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-r v1.0.0..main -a example.com -p src")
+	assert.NoError(t, err)
+	assert.Equal(t, "log", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{
+					Repo:  "failed",
+					Error: "failed to get commits: fatal: bad revision, " + assert.AnError.Error(),
+				},
+				{
+					Repo:   "first",
+					Result: "1111111 Fix first (alice, 2024-01-03)\n2222222 Add first (bob, 2024-01-01)",
+				},
+				{
+					Repo:   "second",
+					Result: "3333333 Add second (alice, 2024-01-02)",
+				},
+			},
+		), output,
+	)
+}
+
+func TestLog_GroupByAuthor(t *testing.T) {
+	sh := prepareLog(t)
+
+	command := CreateLogCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-r v1.0.0..main -a example.com -p src --group-by author")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testAuthorResult{
+				{
+					Author: "alice <alice@example.com>",
+					Result: "first: 1111111 Fix first (2024-01-03)\nsecond: 3333333 Add second (2024-01-02)",
+				},
+				{
+					Author: "bob <bob@example.com>",
+					Result: "first: 2222222 Add first (2024-01-01)",
+				},
+				{
+					Author: "repo:failed",
+					Error:  "failed to get commits: fatal: bad revision, " + assert.AnError.Error(),
+				},
+			},
+		), output,
+	)
+}
+
+func TestLog_UnsupportedGroup(t *testing.T) {
+	sh := prepareLog(t)
+
+	command := CreateLogCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--group-by date")
+	if assert.Error(t, err) {
+		assert.Equal(t, `invalid argument "date" for "--group-by" flag: must be one of 'repo' 'author'`, err.Error())
+	}
+}
+
+func TestLog_ThreeDotRange(t *testing.T) {
+	sh := prepareLog(t)
+
+	command := CreateLogCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--ref main...feature")
+	assert.EqualError(t, err, "three-dot range main...feature is not supported, use two-dot range instead")
+}
+
+func TestLog_InvalidAge(t *testing.T) {
+	sh := prepareLog(t)
+
+	command := CreateLogCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--since yesterday")
+	assert.Error(t, err)
+}
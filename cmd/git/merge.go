@@ -0,0 +1,25 @@
+package git
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateMergeCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:   "merge",
+		Short: "Merge another ref into the current branch",
+		Long: fmt.Sprintf(
+			`Merge another ref into the current branch. The result can be one of:
+* %v - the branch already contains the ref
+* %v - the branch doesn't have own commits and is moved to the ref
+* %v - a merge commit is created
+* %v - there are conflicting files, the merge is aborted and the branch is left as it was`,
+			gitops.IntegrateUpToDate, gitops.IntegrateFastForwarded, gitops.IntegrateMerged, gitops.IntegrateConflict,
+		),
+	}
+
+	return createIntegrateCommand(sh, result, (*gitops.GitService).Merge)
+}
@@ -0,0 +1,57 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "merged", Url: "https://example.com/merged"},
+		{Name: "up-to-date", Url: "https://example.com/up-to-date"},
+		{Name: "fast-forwarded", Url: "https://example.com/fast-forwarded"},
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			commandLine := tests.ShellCommandToString(command, arguments)
+			switch {
+			case commandLine == "git status --porcelain=v2 --branch":
+				return testCleanStatus, nil
+			case commandLine == "git merge-base --is-ancestor main HEAD" && repoName == "up-to-date":
+				return "", nil
+			case commandLine == "git merge-base --is-ancestor HEAD main" && repoName == "fast-forwarded":
+				return "", nil
+			case commandLine == "git merge-base --is-ancestor main HEAD",
+				commandLine == "git merge-base --is-ancestor HEAD main":
+				return "", tests.MockExitError(1)
+			case commandLine == "git merge --ff-only main" && repoName == "fast-forwarded":
+				return "", nil
+			case commandLine == "git merge --no-edit main" && repoName == "merged":
+				return "", nil
+			}
+			return "", assert.AnError
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	command := CreateMergeCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-r main")
+	assert.NoError(t, err)
+	assert.Equal(t, "merge", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testIntegrateResult{
+				{Repo: "fast-forwarded", Result: "fast-forwarded", Ref: "main"},
+				{Repo: "merged", Result: "merged", Ref: "main"},
+				{Repo: "up-to-date", Result: "up-to-date", Ref: "main"},
+			},
+		), output,
+	)
+}
@@ -10,24 +10,49 @@ import (
 
 func CreatePullCommand(sh shell.Shell) *cobra.Command {
 	var filter = runner.Filter{}
+	var flags struct {
+		autostash bool
+	}
 
 	var result = &cobra.Command{
 		Use:   "pull",
 		Short: "Pull changes from remote",
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
-				err := gitService.Pull(runContext.Repo)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				if !flags.autostash {
+					err := gitService.Pull(runContext.Repo)
+					if err != nil {
+						return nil, err
+					}
+
+					return "pulled", nil
+				}
+
+				type result struct {
+					Result string
+					Stash  gitops.StashResult
+				}
+
+				stashResult, err := gitService.WithAutostash(
+					runContext.Repo, func() error {
+						return gitService.Pull(runContext.Repo)
+					},
+				)
 				if err != nil {
 					return nil, err
 				}
 
-				return "pulled", nil
+				return result{"pulled", stashResult}, nil
 			},
 		),
 	}
 
 	filter.AddCommandFlags(result)
 
+	result.Flags().BoolVar(
+		&flags.autostash, "autostash", false, "Stashes local changes before pull and restores them after",
+	)
+
 	return result
 }
@@ -39,3 +39,56 @@ func TestPull(t *testing.T) {
 		), output,
 	)
 }
+
+type testPullResult struct {
+	Repo   string `json:"repo"`
+	Result string `json:"result,omitempty"`
+	Stash  string `json:"stash"`
+	Error  string `json:"error,omitempty"`
+}
+
+func TestPull_Autostash(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "clean", Url: "https://example.com/clean"},
+		{Name: "dirty", Url: "https://example.com/dirty"},
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			switch tests.ShellCommandToString(command, arguments) {
+			case "git status --porcelain=v2 --branch":
+				if repoName == "dirty" {
+					return "# branch.oid 0123456789abcdef\n# branch.head main\n1 M. N... 100644 100644 100644 0123 4567 a", nil
+				}
+				return "# branch.oid 0123456789abcdef\n# branch.head main", nil
+			case "git stash push --message bulker autostash":
+				if repoName == "dirty" {
+					return "Saved working directory", nil
+				}
+			case "git pull --prune":
+				return "OK", nil
+			case "git stash pop":
+				if repoName == "dirty" {
+					return "Dropped refs/stash@{0}", nil
+				}
+			}
+			return "", assert.AnError
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	command := CreatePullCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "--autostash")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testPullResult{
+				{Repo: "clean", Result: "pulled", Stash: "no changes"},
+				{Repo: "dirty", Result: "pulled", Stash: "restored"},
+			},
+		), output,
+	)
+}
@@ -34,7 +34,7 @@ If -b <branchName> is defined, pushes the only branch. Otherwise pushes all bran
 		},
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
 				err := gitService.Push(runContext.Repo, flags.branch, flags.allBranches, flags.force)
 				if err != nil {
 					return nil, err
@@ -0,0 +1,25 @@
+package git
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateRebaseCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:   "rebase",
+		Short: "Rebase the current branch onto another ref",
+		Long: fmt.Sprintf(
+			`Rebase the current branch onto another ref. The result can be one of:
+* %v - the branch already contains the ref
+* %v - the branch doesn't have own commits and is moved to the ref
+* %v - own commits of the branch are rebased onto the ref
+* %v - there are conflicting files, the rebase is aborted and the branch is left as it was`,
+			gitops.IntegrateUpToDate, gitops.IntegrateFastForwarded, gitops.IntegrateRebased, gitops.IntegrateConflict,
+		),
+	}
+
+	return createIntegrateCommand(sh, result, (*gitops.GitService).Rebase)
+}
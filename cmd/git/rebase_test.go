@@ -0,0 +1,91 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testIntegrateResult struct {
+	Repo      string `json:"repo"`
+	Result    string `json:"result"`
+	Ref       string `json:"ref"`
+	Conflicts string `json:"conflicts"`
+	Error     string `json:"error,omitempty"`
+}
+
+const testCleanStatus = "# branch.oid 0123456789abcdef\n# branch.head feature"
+
+func TestRebase_DefaultBranch(t *testing.T) {
+	repos := []settings.Repo{{Name: "repo", Url: "https://example.com"}}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git remote":                                    {Output: "origin"},
+			"git ls-remote --symref origin HEAD":            {Output: "ref: refs/heads/main\tHEAD\n0123\tHEAD"},
+			"git status --porcelain=v2 --branch":            {Output: testCleanStatus},
+			"git merge-base --is-ancestor origin/main HEAD": {Error: tests.MockExitError(1)},
+			"git merge-base --is-ancestor HEAD origin/main": {Error: tests.MockExitError(1)},
+			"git rebase origin/main":                        {Output: "OK"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRebaseCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-n repo")
+	assert.NoError(t, err)
+	assert.Equal(t, "rebase", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString([]testIntegrateResult{{Repo: "repo", Result: "rebased", Ref: "origin/main"}}), output,
+	)
+}
+
+func TestRebase_Conflict(t *testing.T) {
+	repos := []settings.Repo{{Name: "repo", Url: "https://example.com"}}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git status --porcelain=v2 --branch":     {Output: testCleanStatus},
+			"git merge-base --is-ancestor main HEAD": {Error: tests.MockExitError(1)},
+			"git merge-base --is-ancestor HEAD main": {Error: tests.MockExitError(1)},
+			"git rebase main":                        {Output: "CONFLICT", Error: tests.MockExitError(1)},
+			"git diff --name-only --diff-filter=U":   {Output: "a.txt\nb.txt\n"},
+			"git rebase --abort":                     {Output: ""},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRebaseCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo -r main")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testIntegrateResult{{Repo: "repo", Result: "conflict", Ref: "main", Conflicts: "a.txt, b.txt"}},
+		), output,
+	)
+}
+
+func TestRebase_UncommittedChanges(t *testing.T) {
+	repos := []settings.Repo{{Name: "repo", Url: "https://example.com"}}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git status --porcelain=v2 --branch": {
+				Output: testCleanStatus + "\n1 .M N... 100644 100644 100644 0123 4567 file.txt",
+			},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRebaseCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo -r main")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString([]testResult{{Repo: "repo", Error: "the repository has uncommitted changes"}}), output,
+	)
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateReleaseCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		options gitops.ReleaseOptions
+	}
+
+	var result = &cobra.Command{
+		Use:   "release",
+		Short: "Tag a new version based on Conventional Commits",
+		Long: `Tag a new version based on Conventional Commits.
+
+The commits since the latest version tag define the next version:
+* commits with '!' after the type or with 'BREAKING CHANGE:' in the body bump the major version
+* 'feat' commits bump the minor version
+* 'fix' and 'perf' commits bump the patch version
+If there are no such commits, no version is released.
+
+The new version can be written to a file with a property path, the same way as 'props get' reads it.
+The file is committed before the tag is created, and the commit is reset if the tag can't be created.
+The tag is not pushed, use 'git tags push' for that.
+
+Example:
+
+    bulker git release -f package.json -p $.version
+`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				releaseResult, err := gitService.Release(runContext.Repo, flags.options)
+				if err != nil {
+					return nil, err
+				}
+
+				return releaseResult, nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVar(&flags.options.TagPrefix, "prefix", "v", "Prefix of the version tags")
+	result.Flags().StringVarP(
+		&flags.options.VersionFile, "file", "f", "", "File in the repository to write the new version to",
+	)
+	result.Flags().StringVarP(
+		&flags.options.VersionPath, "path", "p", "", `Property path of the version in the file, like "$.version"`,
+	)
+	result.MarkFlagsRequiredTogether("file", "path")
+
+	return result
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testReleaseResult struct {
+	Repo        string `json:"repo"`
+	OldVersion  string `json:"oldVersion"`
+	NewVersion  string `json:"newVersion"`
+	Reason      string `json:"reason"`
+	VersionFile string `json:"versionFile"`
+}
+
+func TestRelease(t *testing.T) {
+	repos := []settings.Repo{{Name: "repo", Url: "https://example.com"}}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git for-each-ref --format=%(refname) refs/tags/": {
+				Output: "refs/tags/release\nrefs/tags/v1.0.0\nrefs/tags/v1.2.0\nrefs/tags/v2.0.0",
+			},
+			"git merge-base --is-ancestor refs/tags/v2.0.0 HEAD": {Error: tests.MockExitError(1)},
+			"git merge-base --is-ancestor refs/tags/v1.2.0 HEAD": {Output: ""},
+			"git --no-pager log --format=%H%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%s%x1f%b%x1e HEAD " +
+				"--not refs/tags/v1.2.0": {
+				Output: testLogCommit("1111111111", "alice", "2024-01-03T12:00:00Z", "feat: new endpoint") +
+					testLogCommit("2222222222", "bob", "2024-01-01T12:00:00Z", "fix: typo"),
+			},
+			"git tag --annotate --message Release v1.3.0 v1.3.0": {Output: ""},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateReleaseCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-n repo")
+	assert.NoError(t, err)
+	assert.Equal(t, "release", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testReleaseResult{
+				{Repo: "repo", OldVersion: "1.2.0", NewVersion: "1.3.0", Reason: "minor: 1 feature, 1 fix"},
+			},
+		), output,
+	)
+}
+
+func TestRelease_FileWithoutPath(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, nil)
+
+	command := CreateReleaseCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-f package.json")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "[file path] are set they must all be set; missing [path]")
+	}
+}
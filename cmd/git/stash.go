@@ -0,0 +1,21 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/cmd/git/stash"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateStashCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:   "stash",
+		Short: "Manages uncommitted changes kept in git stash",
+	}
+
+	result.AddCommand(stash.CreatePushCommand(sh))
+	result.AddCommand(stash.CreatePopCommand(sh))
+	result.AddCommand(stash.CreateListCommand(sh))
+	result.AddCommand(stash.CreateDropCommand(sh))
+
+	return result
+}
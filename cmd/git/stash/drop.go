@@ -0,0 +1,42 @@
+package stash
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateDropCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		ref string
+	}
+
+	var result = &cobra.Command{
+		Use:   "drop",
+		Short: "Remove a stash entry without restoring it",
+		Long: `Remove a stash entry without restoring it.
+Repositories without the stash entry are reported with 'not found' result`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				stashResult, err := gitService.StashDrop(runContext.Repo, flags.ref)
+				if err != nil {
+					return nil, err
+				}
+
+				return stashResult, nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "", "Stash entry to remove, like 'stash@{1}'. The latest one is used by default",
+	)
+
+	return result
+}
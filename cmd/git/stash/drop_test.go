@@ -0,0 +1,34 @@
+package stash
+
+import (
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDrop(t *testing.T) {
+	sh := prepareStash(
+		t, map[string]map[string]string{
+			"found": {
+				testListCommand:            testStashList,
+				"git stash drop stash@{1}": "Dropped stash@{1}",
+			},
+			"missing": {
+				testListCommand: "",
+			},
+		},
+	)
+
+	command := CreateDropCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-r stash@{1}")
+	assert.NoError(t, err)
+	assert.Equal(t, "drop", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "found", Result: "dropped"},
+				{Repo: "missing", Result: "not found"},
+			},
+		), output,
+	)
+}
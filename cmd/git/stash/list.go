@@ -0,0 +1,45 @@
+package stash
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+func CreateListCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+
+	var result = &cobra.Command{
+		Use:   "list",
+		Short: "Prints a list of stash entries",
+		Long: `Prints a list of stash entries.
+If a repository doesn't have any stash entry, the repository will be omitted in the result`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				stashes, err := gitService.StashList(runContext.Repo)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(stashes) == 0 {
+					return nil, nil
+				}
+
+				builder := strings.Builder{}
+				for _, stash := range stashes {
+					builder.WriteString(stash.String() + "\n")
+				}
+
+				return strings.TrimSpace(builder.String()), nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	return result
+}
@@ -0,0 +1,32 @@
+package stash
+
+import (
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	sh := prepareStash(
+		t, map[string]map[string]string{
+			"stashed": {
+				testListCommand: testStashList,
+			},
+			"empty": {
+				testListCommand: "",
+			},
+		},
+	)
+
+	command := CreateListCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "list", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "stashed", Result: "stash@{0}: On main: second\nstash@{1}: On main: first"},
+			},
+		), output,
+	)
+}
@@ -0,0 +1,44 @@
+package stash
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreatePopCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		ref string
+	}
+
+	var result = &cobra.Command{
+		Use:   "pop",
+		Short: "Restore stashed changes and remove them from the stash",
+		Long: `Restore stashed changes and remove them from the stash.
+If the changes can't be applied, the stash entry is kept.
+If they conflict with the working tree, the conflicts are left in it and reported with 'conflict' result.
+Repositories without the stash entry are reported with 'not found' result`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				stashResult, err := gitService.StashPop(runContext.Repo, flags.ref)
+				if err != nil && stashResult != gitops.StashConflict {
+					return nil, err
+				}
+
+				return stashResult, err
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "", "Stash entry to restore, like 'stash@{1}'. The latest one is used by default",
+	)
+
+	return result
+}
@@ -0,0 +1,73 @@
+package stash
+
+import (
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPop(t *testing.T) {
+	sh := prepareStash(
+		t, map[string]map[string]string{
+			"latest": {
+				testListCommand: testStashList,
+				"git stash pop": "Dropped refs/stash@{0}",
+			},
+			"empty": {
+				testListCommand: "",
+			},
+			"failed": {
+				testListCommand: testStashList,
+			},
+			"conflict": {
+				testListCommand:                        testStashList,
+				"git diff --name-only --diff-filter=U": "file.txt\n",
+			},
+		},
+	)
+
+	command := CreatePopCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "pop", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{
+					Repo: "conflict", Result: "conflict",
+					Error: "restored stash conflicts in file.txt, resolve the conflicts and drop the stash entry: " +
+						assert.AnError.Error(),
+				},
+				{Repo: "empty", Result: "not found"},
+				{Repo: "failed", Error: "failed to pop stash: , " + assert.AnError.Error()},
+				{Repo: "latest", Result: "restored"},
+			},
+		), output,
+	)
+}
+
+func TestPop_Ref(t *testing.T) {
+	sh := prepareStash(
+		t, map[string]map[string]string{
+			"found": {
+				testListCommand:           testStashList,
+				"git stash pop stash@{1}": "Dropped stash@{1}",
+			},
+			"missing": {
+				testListCommand: "stash@{0}\x1fOn main: first\x1f2024-01-01T10:00:00Z\x1e\n",
+			},
+		},
+	)
+
+	command := CreatePopCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-r stash@{1}")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "found", Result: "restored"},
+				{Repo: "missing", Result: "not found"},
+			},
+		), output,
+	)
+}
@@ -0,0 +1,44 @@
+package stash
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreatePushCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		message          string
+		includeUntracked bool
+	}
+
+	var result = &cobra.Command{
+		Use:   "push",
+		Short: "Stash uncommitted changes",
+		Long: `Stash uncommitted changes.
+Repositories without changes are reported with 'no changes' result`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				stashResult, err := gitService.StashPush(runContext.Repo, flags.message, flags.includeUntracked)
+				if err != nil {
+					return nil, err
+				}
+
+				return stashResult, nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(&flags.message, "message", "m", "", "Message of the stash entry")
+	result.Flags().BoolVarP(
+		&flags.includeUntracked, "include-untracked", "u", false, "Stash untracked files as well",
+	)
+
+	return result
+}
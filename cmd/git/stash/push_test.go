@@ -0,0 +1,92 @@
+package stash
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+const (
+	testStatusCommand = "git status --porcelain=v2 --branch"
+	testListCommand   = "git stash list --format=%gd%x1f%gs%x1f%cI%x1e"
+	testStashList     = "stash@{0}\x1fOn main: second\x1f2024-02-01T10:00:00Z\x1e\n" +
+		"stash@{1}\x1fOn main: first\x1f2024-01-01T10:00:00Z\x1e\n"
+)
+
+type testResult struct {
+	Repo   string `json:"repo"`
+	Error  string `json:"error,omitempty"`
+	Result string `json:"result,omitempty"`
+}
+
+// prepareStash creates a cloned repository for every key of the responses,
+// that maps commands run in the repository to their output
+func prepareStash(t *testing.T, responses map[string]map[string]string) shell.Shell {
+	var repos []settings.Repo
+	for repoName := range responses {
+		repos = append(repos, settings.Repo{Name: repoName, Url: "https://example.com/" + repoName})
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			commandLine := tests.ShellCommandToString(command, arguments)
+			output, found := responses[repoName][commandLine]
+			if !found {
+				return "", assert.AnError
+			}
+			return output, nil
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		assert.NoError(t, os.Mkdir(tests.Path(repo.Name), os.ModePerm))
+	}
+
+	return sh
+}
+
+func TestPush(t *testing.T) {
+	sh := prepareStash(
+		t, map[string]map[string]string{
+			"changed": {
+				testStatusCommand: "# branch.oid 0123456789abcdef\n# branch.head main\n" +
+					"1 .M N... 100644 100644 100644 0123 4567 file.txt",
+				"git stash push --message wip": "Saved working directory and index state On main: wip",
+			},
+			"clean": {
+				testStatusCommand: "# branch.oid 0123456789abcdef\n# branch.head main\n? untracked.txt",
+			},
+		},
+	)
+
+	command := CreatePushCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-m wip")
+	assert.NoError(t, err)
+	assert.Equal(t, "push", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "changed", Result: "created"},
+				{Repo: "clean", Result: "no changes"},
+			},
+		), output,
+	)
+}
+
+func TestPush_IncludeUntracked(t *testing.T) {
+	sh := prepareStash(
+		t, map[string]map[string]string{
+			"repo": {
+				testStatusCommand:                    "# branch.oid 0123456789abcdef\n# branch.head main\n? untracked.txt",
+				"git stash push --include-untracked": "Saved working directory and index state WIP on main",
+			},
+		},
+	)
+
+	command := CreatePushCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-u")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Result: "created"}}), output)
+}
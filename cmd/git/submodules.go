@@ -0,0 +1,19 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/cmd/git/submodules"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateSubmodulesCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:     "submodules",
+		Short:   "Manages git submodules",
+		Aliases: []string{"submodule"},
+	}
+
+	result.AddCommand(submodules.CreateSyncCommand(sh))
+
+	return result
+}
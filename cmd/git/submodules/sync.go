@@ -0,0 +1,45 @@
+package submodules
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateSyncCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		remote bool
+	}
+
+	var result = &cobra.Command{
+		Use:   "sync",
+		Short: "Synchronize submodules with the recorded commits",
+		Long: `Synchronize submodules with the recorded commits.
+Submodule URLs are updated from the repository configuration, missing submodules are initialized
+and all of them are checked out at the commits recorded in the repository, recursively.
+Repositories without submodules are reported with 'no submodules' result`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				submoduleResult, err := gitService.SyncSubmodules(runContext.Repo, flags.remote)
+				if err != nil {
+					return nil, err
+				}
+
+				return submoduleResult, nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().BoolVar(
+		&flags.remote, "remote", false,
+		"Check submodules out at the latest commits of their remote branches instead of the recorded ones",
+	)
+
+	return result
+}
@@ -0,0 +1,48 @@
+package submodules
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testResult struct {
+	Repo   string `json:"repo"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func TestSync(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "plain", Url: "https://example.com/plain"},
+		{Name: "nested", Url: "https://example.com/nested"},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git submodule sync --recursive":                   {Output: ""},
+			"git submodule update --init --recursive --remote": {Output: ""},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+	err := os.WriteFile(tests.Path("nested", ".gitmodules"), []byte(""), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateSyncCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "--remote")
+	assert.NoError(t, err)
+	assert.Equal(t, "sync", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "nested", Result: "synced"},
+				{Repo: "plain", Result: "no submodules"},
+			},
+		), output,
+	)
+}
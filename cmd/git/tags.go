@@ -0,0 +1,22 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/cmd/git/tags"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateTagsCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:     "tags",
+		Short:   "Manages git tags",
+		Aliases: []string{"tag"},
+	}
+
+	result.AddCommand(tags.CreateListCommand(sh))
+	result.AddCommand(tags.CreateCreateCommand(sh))
+	result.AddCommand(tags.CreatePushCommand(sh))
+	result.AddCommand(tags.CreateRemoveCommand(sh))
+
+	return result
+}
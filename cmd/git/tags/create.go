@@ -0,0 +1,49 @@
+package tags
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func CreateCreateCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		name    string
+		ref     string
+		message string
+	}
+
+	var result = &cobra.Command{
+		Use:   "create",
+		Short: "Create a new tag",
+		Long: `Create a new tag.
+The tag is annotated if a message is provided, otherwise it's lightweight`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				createResult, err := gitService.CreateTag(runContext.Repo, flags.name, flags.ref, flags.message)
+				if err != nil {
+					return nil, err
+				}
+
+				return createResult, nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVar(&flags.name, "git-tag", "", "Name of the tag to create")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "git-tag")
+
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "", "Branch or commit to create the tag at. The current commit is used by default",
+	)
+	result.Flags().StringVarP(&flags.message, "message", "m", "", "Message of the annotated tag")
+
+	return result
+}
@@ -0,0 +1,33 @@
+package tags
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestCreate(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git for-each-ref --format=%(refname) refs/tags/":         {Output: "refs/tags/v1.0.0"},
+			"git tag --annotate --message Release v2.0.0 origin/main": {Output: ""},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateCreateCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-n repo --git-tag v2.0.0 -r origin/main -m Release")
+	assert.NoError(t, err)
+	assert.Equal(t, "create", c.Name())
+	assert.JSONEq(t, tests.ToJsonString([]testResult{{Repo: "repo", Result: "created"}}), output)
+}
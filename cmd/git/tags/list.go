@@ -0,0 +1,54 @@
+package tags
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+func CreateListCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		mode    config.GitMode
+		pattern string
+	}
+
+	var result = &cobra.Command{
+		Use:   "list",
+		Short: "Prints a list of repository tags",
+		Long: `Prints a list of repository tags.
+Remote tags are requested from the remote repository.
+If a repository doesn't have any tag matching pattern, the repository will be omitted in the result`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				tags, err := gitService.GetTags(runContext.Repo, flags.mode, flags.pattern)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(tags) == 0 {
+					return nil, nil
+				}
+
+				builder := strings.Builder{}
+				for _, tag := range tags {
+					builder.WriteString(tag.Short() + "\n")
+				}
+
+				return strings.TrimSpace(builder.String()), nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	config.AddGitModeFlag(&flags.mode, result.Flags())
+	result.Flags().StringVarP(&flags.pattern, "pattern", "p", ".*", "Regexp pattern of the tags to show")
+
+	return result
+}
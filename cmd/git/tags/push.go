@@ -0,0 +1,57 @@
+package tags
+
+import (
+	"context"
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+func CreatePushCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		name string
+	}
+
+	var result = &cobra.Command{
+		Use:   "push",
+		Short: "Push local tags to remote",
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				tags, err := gitService.GetTags(runContext.Repo, config.GitModeLocal, flags.name)
+				if err != nil {
+					return nil, err
+				}
+
+				var pushResult []string
+				for _, tag := range tags {
+					tagResult, err := gitService.PushTag(runContext.Repo, tag)
+					if err != nil {
+						pushResult = append(pushResult, fmt.Sprintf("%v: %v", tag.Short(), err.Error()))
+					} else {
+						pushResult = append(pushResult, fmt.Sprintf("%v: %v", tag.Short(), tagResult))
+					}
+				}
+
+				if len(pushResult) == 0 {
+					return nil, nil
+				}
+
+				return strings.Join(pushResult, "\n"), nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVar(&flags.name, "git-tag", "", "Name or regexp pattern of the tags to push")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "git-tag")
+
+	return result
+}
@@ -0,0 +1,60 @@
+package tags
+
+import (
+	"context"
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+func CreateRemoveCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		name string
+		mode config.GitMode
+	}
+
+	var result = &cobra.Command{
+		Use:     "remove",
+		Short:   "Remove a tag",
+		Aliases: []string{"delete"},
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				tags, err := gitService.GetTags(runContext.Repo, flags.mode, flags.name)
+				if err != nil {
+					return nil, err
+				}
+
+				var removeResult []string
+				for _, tag := range tags {
+					tagResult, err := gitService.RemoveTag(runContext.Repo, tag)
+					if err != nil {
+						removeResult = append(removeResult, fmt.Sprintf("%v: %v", tag.Short(), err.Error()))
+					} else {
+						removeResult = append(removeResult, fmt.Sprintf("%v: %v", tag.Short(), tagResult))
+					}
+				}
+
+				if len(removeResult) == 0 {
+					return nil, nil
+				}
+
+				return strings.Join(removeResult, "\n"), nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVar(&flags.name, "git-tag", "", "Name or regexp pattern of the tags to remove")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "git-tag")
+
+	config.AddGitModeFlag(&flags.mode, result.Flags())
+	return result
+}
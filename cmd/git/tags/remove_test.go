@@ -0,0 +1,53 @@
+package tags
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testResult struct {
+	Repo   string `json:"repo"`
+	Error  string `json:"error,omitempty"`
+	Result string `json:"result,omitempty"`
+}
+
+func TestRemove(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git for-each-ref --format=%(refname) refs/tags/": {Output: "refs/tags/v1.0.0\nrefs/tags/v2.0.0"},
+			"git remote":                                {Output: "origin"},
+			"git ls-remote --tags --refs origin":        {Output: "0123\trefs/tags/v1.0.0\n4567\trefs/tags/v1.1.0"},
+			"git tag --delete v1.0.0":                   {Output: "Deleted tag 'v1.0.0'"},
+			"git push origin --delete refs/tags/v1.0.0": {Output: "OK"},
+			"git push origin --delete refs/tags/v1.1.0": {Output: "rejected", Error: assert.AnError},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRemoveCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-n repo --git-tag v1.* -m all")
+	assert.NoError(t, err)
+	assert.Equal(t, "remove", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{
+					Repo: "repo",
+					Result: "v1.0.0: removed\norigin/v1.0.0: removed\n" +
+						"origin/v1.1.0: failed to remove remote tag: rejected, " + assert.AnError.Error(),
+				},
+			},
+		), output,
+	)
+}
@@ -0,0 +1,24 @@
+package git
+
+import (
+	"github.com/mih-kopylov/bulker/cmd/git/worktree"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateWorktreeCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:   "worktree",
+		Short: "Manages git worktrees to work with several branches of a repository at once",
+		Long: `Manages git worktrees to work with several branches of a repository at once.
+Worktrees are placed according to the worktree layout configuration.
+Other commands run in a worktree instead of the repository directory with --worktree parameter`,
+		Aliases: []string{"worktrees"},
+	}
+
+	result.AddCommand(worktree.CreateAddCommand(sh))
+	result.AddCommand(worktree.CreateListCommand(sh))
+	result.AddCommand(worktree.CreateRemoveCommand(sh))
+
+	return result
+}
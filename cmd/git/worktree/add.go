@@ -0,0 +1,70 @@
+package worktree
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/templates"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func CreateAddCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		name   string
+		branch string
+		create bool
+		ref    string
+	}
+
+	var result = &cobra.Command{
+		Use:   "add",
+		Short: "Add a worktree with a branch checked out",
+		Long: `Add a worktree with a branch checked out.
+A branch that exists only in the remote repository is checked out as a new tracking branch`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				type result struct {
+					Result gitops.WorktreeResult
+					Path   string
+				}
+
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				branch := flags.name
+				if flags.branch != "" {
+					branch = flags.branch
+				}
+				branch, err := templates.Render(&gitService, runContext.Repo, branch)
+				if err != nil {
+					return nil, err
+				}
+
+				path := runContext.Config.WorktreePath(runContext.Repo.Name, flags.name)
+				worktreeResult, err := gitService.AddWorktree(runContext.Repo, path, branch, flags.create, flags.ref)
+				if err != nil {
+					return nil, err
+				}
+
+				return result{worktreeResult, path}, nil
+			},
+		),
+	}
+
+	filter.AddRepoFlags(result)
+
+	result.Flags().StringVarP(&flags.name, "worktree", "w", "", "Name of the worktree to add")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "worktree")
+
+	result.Flags().StringVarP(
+		&flags.branch, "branch", "b", "",
+		"Name of the branch to check out, rendered as a template for every repository. Defaults to the worktree name",
+	)
+	result.Flags().BoolVarP(&flags.create, "create", "c", false, "Create a new branch")
+	result.Flags().StringVarP(
+		&flags.ref, "ref", "r", "", "Ref to create the new branch from. Defaults to the current commit",
+	)
+
+	return result
+}
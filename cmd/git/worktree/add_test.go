@@ -0,0 +1,75 @@
+package worktree
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testAddResult struct {
+	Repo   string `json:"repo"`
+	Result string `json:"result"`
+	Path   string `json:"path"`
+	Error  string `json:"error,omitempty"`
+}
+
+func TestAdd(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	tests.PrepareBulker(t, tests.MockShellEmpty(), repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+	worktreePath := tests.Path(".worktrees", "repo", "review")
+
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git worktree list --porcelain": {
+				Output: fmt.Sprintf("worktree %v\nHEAD 1234567\nbranch refs/heads/main\n", tests.Path("repo")),
+			},
+			"git worktree add -b review-repo " + worktreePath + " origin/main": {Output: ""},
+		},
+	)
+
+	command := CreateAddCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-n repo -w review -b review-{{.Name}} -c -r origin/main")
+	assert.NoError(t, err)
+	assert.Equal(t, "add", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString([]testAddResult{{Repo: "repo", Result: "added", Path: worktreePath}}), output,
+	)
+}
+
+func TestAdd_Exists(t *testing.T) {
+	repos := []settings.Repo{
+		{
+			Name: "repo",
+			Url:  "https://example.com",
+		},
+	}
+	tests.PrepareBulker(t, tests.MockShellEmpty(), repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+	worktreePath := tests.Path(".worktrees", "repo", "review")
+
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git worktree list --porcelain": {
+				Output: fmt.Sprintf("worktree %v\n\nworktree %v\nbranch refs/heads/review\n", tests.Path("repo"), worktreePath),
+			},
+		},
+	)
+
+	command := CreateAddCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo -w review")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString([]testAddResult{{Repo: "repo", Result: "already exists", Path: worktreePath}}), output,
+	)
+}
@@ -0,0 +1,49 @@
+package worktree
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+func CreateListCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+
+	var result = &cobra.Command{
+		Use:   "list",
+		Short: "Prints a list of repository worktrees",
+		Long: `Prints a list of repository worktrees with their checked out branches.
+The repository directory itself is not listed.
+If a repository doesn't have any worktree, the repository will be omitted in the result`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				worktrees, err := gitService.GetWorktrees(runContext.Repo)
+				if err != nil {
+					return nil, err
+				}
+
+				var lines []string
+				for _, worktree := range worktrees {
+					if worktree.Main {
+						continue
+					}
+					lines = append(lines, worktree.String())
+				}
+
+				if len(lines) == 0 {
+					return nil, nil
+				}
+
+				return strings.Join(lines, "\n"), nil
+			},
+		),
+	}
+
+	filter.AddRepoFlags(result)
+
+	return result
+}
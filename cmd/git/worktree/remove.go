@@ -0,0 +1,48 @@
+package worktree
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func CreateRemoveCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		name  string
+		force bool
+	}
+
+	var result = &cobra.Command{
+		Use:     "remove",
+		Short:   "Remove a worktree",
+		Long:    "Remove a worktree. The branch checked out in the worktree is kept",
+		Aliases: []string{"delete"},
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				path := runContext.Config.WorktreePath(runContext.Repo.Name, flags.name)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				worktreeResult, err := gitService.RemoveWorktree(runContext.Repo, path, flags.force)
+				if err != nil {
+					return nil, err
+				}
+
+				return worktreeResult, nil
+			},
+		),
+	}
+
+	filter.AddRepoFlags(result)
+
+	result.Flags().StringVarP(&flags.name, "worktree", "w", "", "Name of the worktree to remove")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "worktree")
+
+	result.Flags().BoolVarP(
+		&flags.force, "force", "f", false, "Remove the worktree even if it has uncommitted changes or is locked",
+	)
+
+	return result
+}
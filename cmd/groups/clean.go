@@ -15,19 +15,18 @@ func CreateCleanCommand(sh shell.Shell) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			settingsManager := settings.NewManager(config.ReadConfig(), sh)
 
-			sets, err := settingsManager.Read()
-			if err != nil {
-				return err
-			}
-
 			entityInfoMap := map[string]output.EntityInfo{}
-			for _, group := range sets.Groups {
-				entityInfoMap[group.Name] = output.EntityInfo{Result: "removed", Error: nil}
-			}
 
-			sets.Groups = []settings.Group{}
+			err := settingsManager.Update(
+				func(sets *settings.Settings) error {
+					for _, group := range sets.Groups {
+						entityInfoMap[group.Name] = output.EntityInfo{Result: "removed", Error: nil}
+					}
 
-			err = settingsManager.Write(sets)
+					sets.Groups = []settings.Group{}
+					return nil
+				},
+			)
 			if err != nil {
 				return err
 			}
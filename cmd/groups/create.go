@@ -25,45 +25,44 @@ func CreateCreateCommand(sh shell.Shell) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			settingsManager := settings.NewManager(config.ReadConfig(), sh)
 
-			sets, err := settingsManager.Read()
-			if err != nil {
-				return err
-			}
-
-			group, err := sets.AddGroup(flags.group)
-			if err != nil {
-				if !errors.Is(err, settings.ErrGroupAlreadyExists) || !flags.force {
-					return fmt.Errorf("group already exists, use --force to recreate")
-				}
-
-				err = sets.RemoveGroup(flags.group)
-				if err != nil {
-					return err
-				}
-
-				group, err = sets.AddGroup(flags.group)
-				if err != nil {
-					return err
-				}
-			}
-
-			entityInfoMap := map[string]output.EntityInfo{}
-
 			repos, err := utils.GetReposFromStdInOrDefault(flags.repos)
 			if err != nil {
 				return err
 			}
 
-			for _, repoName := range repos {
-				err := sets.AddRepoToGroup(group, repoName)
-				if err != nil {
-					entityInfoMap[repoName] = output.EntityInfo{Result: nil, Error: err}
-				} else {
-					entityInfoMap[repoName] = output.EntityInfo{Result: "created", Error: nil}
-				}
-			}
+			entityInfoMap := map[string]output.EntityInfo{}
 
-			err = settingsManager.Write(sets)
+			err = settingsManager.Update(
+				func(sets *settings.Settings) error {
+					group, err := sets.AddGroup(flags.group)
+					if err != nil {
+						if !errors.Is(err, settings.ErrGroupAlreadyExists) || !flags.force {
+							return fmt.Errorf("group already exists, use --force to recreate")
+						}
+
+						err = sets.RemoveGroup(flags.group)
+						if err != nil {
+							return err
+						}
+
+						group, err = sets.AddGroup(flags.group)
+						if err != nil {
+							return err
+						}
+					}
+
+					for _, repoName := range repos {
+						err := sets.AddRepoToGroup(group, repoName)
+						if err != nil {
+							entityInfoMap[repoName] = output.EntityInfo{Result: nil, Error: err}
+						} else {
+							entityInfoMap[repoName] = output.EntityInfo{Result: "created", Error: nil}
+						}
+					}
+
+					return nil
+				},
+			)
 			if err != nil {
 				return err
 			}
@@ -25,16 +25,6 @@ If the repo to be removed does not exist in the group, it will be ignored.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			settingsManager := settings.NewManager(config.ReadConfig(), sh)
 
-			sets, err := settingsManager.Read()
-			if err != nil {
-				return err
-			}
-
-			group, err := sets.GetGroup(flags.group)
-			if err != nil {
-				return err
-			}
-
 			repos, err := utils.GetReposFromStdInOrDefault(flags.repos)
 			if err != nil {
 				return err
@@ -42,24 +32,33 @@ If the repo to be removed does not exist in the group, it will be ignored.`,
 
 			entityInfoMap := map[string]output.EntityInfo{}
 
-			for _, repoName := range repos {
-				err := sets.RemoveRepoFromGroup(group, repoName)
-				if err != nil {
-					if errors.Is(err, settings.ErrRepoAlreadyRemoved) {
-						logrus.
-							WithField("repo", repoName).
-							WithField("group", flags.group).
-							Debug("repository already removed, skipping")
-						entityInfoMap[repoName] = output.EntityInfo{Result: "removing skipped", Error: nil}
-					} else {
-						entityInfoMap[repoName] = output.EntityInfo{Result: nil, Error: err}
+			err = settingsManager.Update(
+				func(sets *settings.Settings) error {
+					group, err := sets.GetGroup(flags.group)
+					if err != nil {
+						return err
+					}
+
+					for _, repoName := range repos {
+						err := sets.RemoveRepoFromGroup(group, repoName)
+						if err != nil {
+							if errors.Is(err, settings.ErrRepoAlreadyRemoved) {
+								logrus.
+									WithField("repo", repoName).
+									WithField("group", flags.group).
+									Debug("repository already removed, skipping")
+								entityInfoMap[repoName] = output.EntityInfo{Result: "removing skipped", Error: nil}
+							} else {
+								entityInfoMap[repoName] = output.EntityInfo{Result: nil, Error: err}
+							}
+						} else {
+							entityInfoMap[repoName] = output.EntityInfo{Result: "removed", Error: nil}
+						}
 					}
-				} else {
-					entityInfoMap[repoName] = output.EntityInfo{Result: "removed", Error: nil}
-				}
-			}
 
-			err = settingsManager.Write(sets)
+					return nil
+				},
+			)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/mih-kopylov/bulker/cmd/hosting"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateHostingCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:   "hosting",
+		Short: "Manages settings of the repositories on GitHub, GitLab or Bitbucket",
+		Long: `Manages settings of the repositories with GitHub, GitLab or Bitbucket REST API.
+The provider is detected by the repository URL unless it's configured with --hosting-provider parameter`,
+	}
+
+	result.AddCommand(hosting.CreateAuditCommand(sh))
+
+	return result
+}
@@ -0,0 +1,82 @@
+package hosting
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/hosting"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func CreateAuditCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		policyFile string
+		apply      bool
+	}
+	var policy *hosting.Policy
+
+	var result = &cobra.Command{
+		Use:   "audit",
+		Short: "Compare repository settings with a policy",
+		Long: `Compare repository settings with a policy and print a line per checked setting.
+Settings that differ from the policy are reported as 'violation', or 'fixed' with --apply parameter.
+Branch protection and reviews are checked for the default branch. Repositories don't need to be cloned.
+
+A policy is a YAML file, settings that are not set are not checked:
+
+    defaultBranch: main
+    branchProtection: true
+    requiredReviews: 1
+
+Example:
+
+    bulker hosting audit -p policy.yaml --apply
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			policy, err = hosting.ReadPolicy(flags.policyFile)
+			return err
+		},
+		RunE: runner.NewCommandRunner(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				type result struct {
+					Setting  string
+					Actual   string
+					Expected string
+					Result   hosting.AuditResult
+				}
+
+				client, err := hosting.NewClient(runContext.Config, runContext.Repo.Url)
+				if err != nil {
+					return nil, err
+				}
+
+				checks, err := hosting.Audit(ctx, client, policy, flags.apply)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(checks) == 0 {
+					return nil, nil
+				}
+
+				var results []result
+				for _, check := range checks {
+					results = append(results, result{check.Setting, check.Actual, check.Expected, check.Result})
+				}
+
+				return results, nil
+			},
+		),
+	}
+
+	filter.AddRepoFlags(result)
+
+	result.Flags().StringVarP(&flags.policyFile, "policy", "p", "", "YAML file with the expected settings")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "policy")
+	result.Flags().BoolVar(&flags.apply, "apply", false, "Change the settings that violate the policy")
+
+	return result
+}
@@ -0,0 +1,59 @@
+package hosting
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testResult struct {
+	Repo     string `json:"repo"`
+	Setting  string `json:"setting,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Result   string `json:"result,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func TestAudit(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "compliant", Url: "git@github.com:owner/compliant.git"},
+		{Name: "violating", Url: "git@github.com:owner/violating.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	tests.PrepareHosting(
+		t, map[string]string{
+			"GET /repos/owner/compliant": `{"default_branch": "main"}`,
+			"GET /repos/owner/compliant/branches/main/protection": `{
+				"required_pull_request_reviews": {"required_approving_review_count": 1}
+			}`,
+			"GET /repos/owner/violating":                 `{"default_branch": "master"}`,
+			"GET /repos/owner/violating/branches/master": `{"protected": false}`,
+		},
+	)
+	policyFile := tests.Path("policy.yaml")
+	err := os.WriteFile(
+		policyFile, []byte("defaultBranch: main\nbranchProtection: true\nrequiredReviews: 1\n"), os.ModePerm,
+	)
+	assert.NoError(t, err)
+
+	command := CreateAuditCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-p "+policyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "audit", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "compliant", Setting: "defaultBranch", Actual: "main", Expected: "main", Result: "ok"},
+				{Repo: "compliant", Setting: "branchProtection", Actual: "true", Expected: "true", Result: "ok"},
+				{Repo: "compliant", Setting: "requiredReviews", Actual: "1", Expected: "1", Result: "ok"},
+				{Repo: "violating", Setting: "defaultBranch", Actual: "master", Expected: "main", Result: "violation"},
+				{Repo: "violating", Setting: "branchProtection", Actual: "false", Expected: "true", Result: "violation"},
+				{Repo: "violating", Setting: "requiredReviews", Actual: "0", Expected: "1", Result: "violation"},
+			},
+		), output,
+	)
+}
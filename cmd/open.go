@@ -43,7 +43,7 @@ The following VCS platforms are supported:
 		),
 	}
 
-	filter.AddCommandFlags(result)
+	filter.AddRepoFlags(result)
 
 	flags.page = shell.PageSources
 	result.Flags().VarP(
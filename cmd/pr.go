@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/mih-kopylov/bulker/cmd/pr"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreatePullRequestsCommand(sh shell.Shell) *cobra.Command {
+	var result = &cobra.Command{
+		Use:   "pr",
+		Short: "Manages pull requests of the repositories",
+		Long: `Manages pull requests of the repositories with GitHub, GitLab or Bitbucket REST API.
+The provider is detected by the repository URL unless it's configured with --hosting-provider parameter.
+The API token is taken from the hosting token configuration`,
+		Aliases: []string{"prs"},
+	}
+
+	result.AddCommand(pr.CreateCreateCommand(sh))
+	result.AddCommand(pr.CreateListCommand(sh))
+	result.AddCommand(pr.CreateStatusCommand(sh))
+
+	return result
+}
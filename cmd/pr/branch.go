@@ -0,0 +1,21 @@
+package pr
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/model"
+)
+
+// currentBranch returns the checked out branch, that pull requests are created from by default
+func currentBranch(gitService *gitops.GitService, repo *model.Repo) (string, error) {
+	repoStatus, err := gitService.Status(repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to get status: %w", err)
+	}
+
+	if repoStatus.Detached {
+		return "", fmt.Errorf("%w at %v, set the branch with --branch", gitops.ErrDetachedHead, repoStatus.Ref)
+	}
+
+	return repoStatus.Ref, nil
+}
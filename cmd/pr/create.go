@@ -0,0 +1,123 @@
+package pr
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/hosting"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/templates"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func CreateCreateCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		branch    string
+		target    string
+		title     string
+		body      string
+		reviewers []string
+	}
+
+	var result = &cobra.Command{
+		Use:   "create",
+		Short: "Create a pull request",
+		Long: `Create a pull request from a branch to the target one.
+If there is an open pull request from the branch already, a new one is not created.
+Title and body are rendered as templates for every repository.
+
+Example:
+
+    bulker pr create -b JIRA-123 --title "{{ .Name }}: update dependencies" -r reviewer
+`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				type result struct {
+					Result hosting.PullRequestResult
+					Id     int
+					Url    string
+				}
+
+				client, err := hosting.NewClient(runContext.Config, runContext.Repo.Url)
+				if err != nil {
+					return nil, err
+				}
+
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+
+				source := flags.branch
+				if source == "" {
+					source, err = currentBranch(&gitService, runContext.Repo)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				existing, err := client.GetPullRequests(
+					ctx, hosting.PullRequestFilter{Source: source, State: hosting.PullRequestOpen},
+				)
+				if err != nil {
+					return nil, err
+				}
+				if len(existing) > 0 {
+					return result{hosting.PullRequestExists, existing[0].Id, existing[0].Url}, nil
+				}
+
+				target := flags.target
+				if target == "" {
+					defaultBranch, err := gitService.GetDefaultBranch(runContext.Repo)
+					if err != nil {
+						return nil, err
+					}
+					target = defaultBranch.Name
+				}
+
+				title, err := templates.Render(&gitService, runContext.Repo, flags.title)
+				if err != nil {
+					return nil, err
+				}
+
+				body, err := templates.Render(&gitService, runContext.Repo, flags.body)
+				if err != nil {
+					return nil, err
+				}
+
+				pullRequest, err := client.CreatePullRequest(
+					ctx, hosting.CreatePullRequestOptions{
+						Source:    source,
+						Target:    target,
+						Title:     title,
+						Body:      body,
+						Reviewers: flags.reviewers,
+					},
+				)
+				// the pull request is reported along with the error if it's created, but the reviewers aren't requested
+				if pullRequest == nil {
+					return nil, err
+				}
+
+				return result{hosting.PullRequestCreated, pullRequest.Id, pullRequest.Url}, err
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(
+		&flags.branch, "branch", "b", "", "Branch to create the pull request from. The current branch is used if not set",
+	)
+	result.Flags().StringVar(
+		&flags.target, "target", "", "Branch to merge the pull request to. The default branch is used if not set",
+	)
+	result.Flags().StringVar(&flags.title, "title", "", "Title of the pull request, rendered as a template")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "title")
+	result.Flags().StringVar(&flags.body, "body", "", "Description of the pull request, rendered as a template")
+	result.Flags().StringSliceVarP(
+		&flags.reviewers, "reviewer", "r", []string{},
+		"Reviewers of the pull request. User names for GitHub and GitLab, account ids or UUIDs for Bitbucket",
+	)
+
+	return result
+}
@@ -0,0 +1,131 @@
+package pr
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testResult struct {
+	Repo   string `json:"repo"`
+	Result string `json:"result,omitempty"`
+	Id     int    `json:"id,omitempty"`
+	Url    string `json:"url,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func TestCreate(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "created", Url: "git@github.com:owner/created.git"},
+		{Name: "existing", Url: "git@github.com:owner/existing.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+	tests.PrepareHosting(
+		t, map[string]string{
+			"GET /repos/owner/created/pulls": `[]`,
+			"POST /repos/owner/created/pulls": `{"number": 3, "title": "created", "state": "open",
+				"html_url": "https://github.com/owner/created/pull/3"}`,
+			"GET /repos/owner/existing/pulls": `[{"number": 1, "title": "existing", "state": "open",
+				"html_url": "https://github.com/owner/existing/pull/1"}]`,
+		},
+	)
+
+	command := CreateCreateCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-b feature --target main --title {{.Name}}")
+	assert.NoError(t, err)
+	assert.Equal(t, "create", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "created", Result: "created", Id: 3, Url: "https://github.com/owner/created/pull/3"},
+				{Repo: "existing", Result: "already exists", Id: 1, Url: "https://github.com/owner/existing/pull/1"},
+			},
+		), output,
+	)
+}
+
+func TestCreate_ApiError(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "git@github.com:owner/repo.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+	tests.PrepareHosting(t, map[string]string{})
+
+	command := CreateCreateCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-b feature --target main --title title")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "repo", Error: "failed to get pull requests: GET /repos/owner/repo/pulls: not found"},
+			},
+		), output,
+	)
+}
+
+func TestCreate_ReviewersError(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "git@github.com:owner/repo.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+	tests.PrepareHosting(
+		t, map[string]string{
+			"GET /repos/owner/repo/pulls": `[]`,
+			"POST /repos/owner/repo/pulls": `{"number": 3, "title": "title", "state": "open",
+				"html_url": "https://github.com/owner/repo/pull/3"}`,
+		},
+	)
+
+	command := CreateCreateCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-b feature --target main --title title -r bob")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{
+					Repo: "repo", Result: "created", Id: 3, Url: "https://github.com/owner/repo/pull/3",
+					Error: "failed to request reviewers: POST /repos/owner/repo/pulls/3/requested_reviewers: not found",
+				},
+			},
+		), output,
+	)
+}
+
+func TestCreate_DetachedHead(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "git@github.com:owner/repo.git"},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git status --porcelain=v2 --branch": {Output: "# branch.oid 0123456789abcdef\n# branch.head (detached)"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+	tests.PrepareHosting(t, map[string]string{})
+
+	command := CreateCreateCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "--target main --title title")
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]testResult{
+				{Repo: "repo", Error: "detached head at 0123456, set the branch with --branch"},
+			},
+		), output,
+	)
+}
@@ -0,0 +1,74 @@
+package pr
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/hosting"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateListCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		branch string
+		state  hosting.PullRequestState
+	}
+	flags.state = hosting.PullRequestOpen
+
+	var result = &cobra.Command{
+		Use:   "list",
+		Short: "Prints a list of pull requests",
+		Long:  "Prints a list of pull requests, one per line. Repositories without pull requests are omitted",
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				type result struct {
+					Id     int
+					Title  string
+					State  hosting.PullRequestState
+					Source string
+					Target string
+					Author string
+					Url    string
+				}
+
+				client, err := hosting.NewClient(runContext.Config, runContext.Repo.Url)
+				if err != nil {
+					return nil, err
+				}
+
+				pullRequests, err := client.GetPullRequests(
+					ctx, hosting.PullRequestFilter{Source: flags.branch, State: flags.state},
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(pullRequests) == 0 {
+					return nil, nil
+				}
+
+				var results []result
+				for _, pullRequest := range pullRequests {
+					results = append(
+						results, result{
+							pullRequest.Id, pullRequest.Title, pullRequest.State, pullRequest.Source,
+							pullRequest.Target, pullRequest.Author, pullRequest.Url,
+						},
+					)
+				}
+
+				return results, nil
+			},
+		),
+	}
+
+	filter.AddRepoFlags(result)
+
+	result.Flags().StringVarP(&flags.branch, "branch", "b", "", "Branch the pull requests are created from")
+	result.Flags().Var(
+		&flags.state, "state", "State of the pull requests to print. One of: open, merged, closed, all",
+	)
+
+	return result
+}
@@ -0,0 +1,57 @@
+package pr
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	type listResult struct {
+		Repo   string `json:"repo"`
+		Id     int    `json:"id"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		Source string `json:"source"`
+		Target string `json:"target"`
+		Author string `json:"author"`
+		Url    string `json:"url"`
+	}
+
+	repos := []settings.Repo{
+		{Name: "empty", Url: "git@github.com:owner/empty.git"},
+		{Name: "repo", Url: "git@github.com:owner/repo.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+	tests.PrepareHosting(
+		t, map[string]string{
+			"GET /repos/owner/empty/pulls": `[]`,
+			"GET /repos/owner/repo/pulls": `[
+				{"number": 2, "title": "Feature", "state": "open", "html_url": "https://github.com/owner/repo/pull/2",
+					"head": {"ref": "feature"}, "base": {"ref": "main"}, "user": {"login": "alice"}},
+				{"number": 1, "title": "Fix", "state": "open", "html_url": "https://github.com/owner/repo/pull/1",
+					"head": {"ref": "fix"}, "base": {"ref": "main"}, "user": {"login": "bob"}}
+			]`,
+		},
+	)
+
+	command := CreateListCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "list", c.Name())
+	assert.JSONEq(
+		t, tests.ToJsonString(
+			[]listResult{
+				{"repo", 2, "Feature", "open", "feature", "main", "alice", "https://github.com/owner/repo/pull/2"},
+				{"repo", 1, "Fix", "open", "fix", "main", "bob", "https://github.com/owner/repo/pull/1"},
+			},
+		), output,
+	)
+}
@@ -0,0 +1,72 @@
+package pr
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/hosting"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateStatusCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+	var flags struct {
+		branch string
+	}
+
+	var result = &cobra.Command{
+		Use:   "status",
+		Short: "Prints the pull request of a branch",
+		Long: `Prints the latest pull request of a branch in any state.
+Repositories without a pull request from the branch are omitted`,
+		RunE: runner.NewCommandRunnerForExistingRepos(
+			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+				type result struct {
+					Id     int
+					State  hosting.PullRequestState
+					Title  string
+					Target string
+					Url    string
+				}
+
+				client, err := hosting.NewClient(runContext.Config, runContext.Repo.Url)
+				if err != nil {
+					return nil, err
+				}
+
+				source := flags.branch
+				if source == "" {
+					gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+					source, err = currentBranch(&gitService, runContext.Repo)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				pullRequests, err := client.GetPullRequests(
+					ctx, hosting.PullRequestFilter{Source: source, State: hosting.PullRequestAll},
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(pullRequests) == 0 {
+					return nil, nil
+				}
+
+				// providers return the most recent pull requests first
+				latest := pullRequests[0]
+				return result{latest.Id, latest.State, latest.Title, latest.Target, latest.Url}, nil
+			},
+		),
+	}
+
+	filter.AddCommandFlags(result)
+
+	result.Flags().StringVarP(
+		&flags.branch, "branch", "b", "", "Branch of the pull request. The current branch is used if not set",
+	)
+
+	return result
+}
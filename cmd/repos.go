@@ -16,8 +16,12 @@ func CreateReposCommand(sh shell.Shell) *cobra.Command {
 	result.AddCommand(repos.CreateListCommand(sh))
 	result.AddCommand(repos.CreateAddCommand(sh))
 	result.AddCommand(repos.CreateRemoveCommand(sh))
+	result.AddCommand(repos.CreateEditCommand(sh))
+	result.AddCommand(repos.CreateRenameCommand(sh))
+	result.AddCommand(repos.CreateDoctorCommand(sh))
 	result.AddCommand(repos.CreateExportCommand(sh))
 	result.AddCommand(repos.CreateImportCommand(sh))
+	result.AddCommand(repos.CreateDiffCommand(sh))
 
 	return result
 }
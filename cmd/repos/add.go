@@ -23,11 +23,6 @@ func CreateAddCommand(sh shell.Shell) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			settingsManager := settings.NewManager(config.ReadConfig(), sh)
 
-			sets, err := settingsManager.Read()
-			if err != nil {
-				return err
-			}
-
 			if flags.name == "" {
 				flags.name = filepath.Base(flags.url)
 				if filepath.Ext(flags.name) == ".git" {
@@ -35,12 +30,11 @@ func CreateAddCommand(sh shell.Shell) *cobra.Command {
 				}
 			}
 
-			err = sets.AddRepo(flags.name, flags.url, flags.tags)
-			if err != nil {
-				return err
-			}
-
-			err = settingsManager.Write(sets)
+			err := settingsManager.Update(
+				func(sets *settings.Settings) error {
+					return sets.AddRepo(flags.name, flags.url, flags.tags)
+				},
+			)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,109 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/output"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+func CreateDiffCommand(sh shell.Shell) *cobra.Command {
+	var flags struct {
+		branch string
+		path   string
+	}
+
+	var result = &cobra.Command{
+		Use:   "diff <source>",
+		Short: "Shows how the repositories configuration differs from an external git repository or a file",
+		Long: `Shows how the repositories configuration differs from an external git repository or a file.
+The source is treated as a local file if it exists, or as "-" to read from stdin, and as a remote repository URL otherwise.
+Each repository and group is reported as it would change if the local configuration was replaced with the source one:
+* added - exists in the source only
+* removed - exists in the local configuration only
+* changed - exists in both, the differing fields are listed`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager := settings.NewManager(config.ReadConfig(), sh)
+			source := args[0]
+
+			fileSource, err := isFileSource(source)
+			if err != nil {
+				return err
+			}
+
+			var diffResult map[string]settings.ExportImportResult
+			if fileSource {
+				if cmd.Flags().Changed("branch") || cmd.Flags().Changed("path") {
+					return errors.New("branch and path can be used only with a remote repository source")
+				}
+				diffResult, err = manager.DiffWith(settings.NewFileTransport(source, cmd.InOrStdin(), nil))
+			} else {
+				diffResult, err = manager.Diff(
+					source, settings.GitTransportOptions{Branch: flags.branch, Path: flags.path},
+				)
+			}
+			if err != nil {
+				return err
+			}
+
+			entityInfoMap := map[string]output.EntityInfo{}
+			for repo, repoResult := range diffResult {
+				switch repoResult.Status {
+				case settings.ExportImportStatusUpToDate:
+					//omit in the result
+				case settings.ExportImportStatusAdded:
+					entityInfoMap[repo] = output.EntityInfo{Result: "added"}
+				case settings.ExportImportStatusRemoved:
+					entityInfoMap[repo] = output.EntityInfo{Result: "removed"}
+				case settings.ExportImportStatusUpdated:
+					entityInfoMap[repo] = output.EntityInfo{
+						Result: fmt.Sprintf("changed: %v", strings.Join(repoResult.Fields, ", ")),
+					}
+				default:
+					entityInfoMap[repo] = output.EntityInfo{
+						Error: fmt.Errorf("status %v is not supported", repoResult.Status),
+					}
+				}
+			}
+
+			err = output.Write(cmd.OutOrStdout(), "repo", entityInfoMap)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	result.Flags().StringVarP(
+		&flags.branch, "branch", "b", "", "Branch of the remote repository to compare with. The default branch is used if empty",
+	)
+	result.Flags().StringVarP(
+		&flags.path, "path", "p", "repos.yaml", "Path of the file relative to the remote repository root",
+	)
+
+	return result
+}
+
+// isFileSource checks whether the source is a local file rather than a remote repository URL
+func isFileSource(source string) (bool, error) {
+	if source == settings.StdStreamFileName {
+		return true, nil
+	}
+
+	info, err := os.Stat(source)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return !info.IsDir(), nil
+}
@@ -0,0 +1,129 @@
+package repos
+
+import (
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+type testDiffResult struct {
+	Repo   string `json:"repo"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+const testDiffContent = `
+version: 2
+data:
+    repos:
+        same:
+            url: https://example.com/same.git
+            tags: [two, one]
+        changed:
+            url: https://example.com/changed-remote.git
+            tags: [one]
+        imported:
+            url: https://example.com/imported.git
+            tags: []
+    groups:
+        same:
+            repos: [same, changed]
+        changed:
+            repos: [same]
+        imported:
+            repos: [imported]
+`
+
+func prepareDiffBulker(t *testing.T, sh shell.Shell) {
+	repos := []settings.Repo{
+		{Name: "same", Url: "https://example.com/same.git", Tags: []string{"one", "two"}},
+		{Name: "changed", Url: "https://example.com/changed.git", Tags: []string{"two"}},
+		{Name: "local", Url: "https://example.com/local.git"},
+	}
+	groups := []settings.Group{
+		{Name: "same", Repos: []string{"changed", "same"}},
+		{Name: "changed", Repos: []string{"local"}},
+		{Name: "local", Repos: []string{"local"}},
+		{Name: settings.PreviousGroupName, Repos: []string{"local"}},
+	}
+	tests.PrepareBulkerWithGroups(t, sh, repos, groups)
+}
+
+var expectedDiffResult = []testDiffResult{
+	{Repo: "changed", Result: "changed: url, tags"},
+	{Repo: "group:changed", Result: "changed: repos"},
+	{Repo: "group:imported", Result: "added"},
+	{Repo: "group:local", Result: "removed"},
+	{Repo: "imported", Result: "added"},
+	{Repo: "local", Result: "removed"},
+}
+
+func TestDiff_File(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	prepareDiffBulker(t, sh)
+	diffFileName := tests.Path("diff.yaml")
+	err := os.WriteFile(diffFileName, []byte(testDiffContent), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateDiffCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, diffFileName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "diff", c.Name())
+		assert.JSONEq(t, tests.ToJsonString(expectedDiffResult), output)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.True(t, sets.RepoExists("local"))
+		assert.False(t, sets.RepoExists("imported"))
+	}
+}
+
+func TestDiff_Stdin(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	prepareDiffBulker(t, sh)
+
+	command := CreateDiffCommand(sh)
+	command.SetIn(strings.NewReader(testDiffContent))
+	_, output, err := tests.ExecuteCommand(command, "-")
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, tests.ToJsonString(expectedDiffResult), output)
+	}
+}
+
+func TestDiff_Remote(t *testing.T) {
+	sh := &shell.NativeShell{}
+	prepareDiffBulker(t, sh)
+	bareGitRepo, err := tests.CreateBareGitRepository("likeRemoteGitRepo")
+	assert.NoError(t, err)
+
+	command := CreateExportCommand(sh)
+	_, _, err = tests.ExecuteCommand(command, "-r "+bareGitRepo+" -p diff/repos.yaml")
+	assert.NoError(t, err)
+
+	command = CreateDiffCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, bareGitRepo+" -p diff/repos.yaml")
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, "[]", output)
+	}
+}
+
+func TestDiff_BranchWithFile(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	prepareDiffBulker(t, sh)
+	diffFileName := tests.Path("diff.yaml")
+	err := os.WriteFile(diffFileName, []byte(testDiffContent), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateDiffCommand(sh)
+	_, _, err = tests.ExecuteCommand(command, diffFileName+" -b main")
+	if assert.Error(t, err) {
+		assert.Equal(t, "branch and path can be used only with a remote repository source", err.Error())
+	}
+}
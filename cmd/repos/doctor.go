@@ -0,0 +1,306 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/fileops"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/output"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+type doctorProblem string
+
+const (
+	problemOrphan      doctorProblem = "orphan"
+	problemUrlMismatch doctorProblem = "url mismatch"
+	problemNotGit      doctorProblem = "not a git repository"
+	problemMissing     doctorProblem = "missing"
+	problemNoOrigin    doctorProblem = "no origin remote"
+)
+
+const (
+	fixRegister  = "register"
+	fixUpdateUrl = "update-url"
+	fixRemove    = "remove"
+	fixReclone   = "reclone"
+)
+
+// doctorFinding describes a single problem of a repository or a directory in the repositories directory
+type doctorFinding struct {
+	problem doctorProblem
+	details string
+	fix     string
+	// url is the remote URL found in the working copy, if any
+	url string
+}
+
+type doctorResult struct {
+	Problem string
+	Details string
+	Fix     string
+}
+
+func (f *doctorFinding) toResult() doctorResult {
+	return doctorResult{Problem: string(f.problem), Details: f.details, Fix: f.fix}
+}
+
+func CreateDoctorCommand(sh shell.Shell) *cobra.Command {
+	var flags struct {
+		fixes []string
+	}
+
+	var result = &cobra.Command{
+		Use:   "doctor",
+		Short: "Finds inconsistencies between the supported repositories and the repositories directory",
+		Long: fmt.Sprintf(
+			`Finds inconsistencies between the supported repositories and the repositories directory.
+The following problems are reported:
+* %v - a directory that is not a supported repository
+* %v - the "origin" remote URL of the working copy differs from the configured one
+* %v - the repository directory exists, but it's not a git working copy
+* %v - the repository is not cloned yet
+* %v - the "origin" remote URL of the working copy can't be read
+
+Problems are only reported by default. Use "--fix" to fix them:
+* %v - adds orphan git working copies to the supported repositories
+* %v - sets the configured URL to the one the working copy uses
+* %v - removes missing repositories from the supported ones
+* %v - clones missing repositories and re-clones the ones that are not git working copies.
+  The existing directory content is deleted`,
+			problemOrphan, problemUrlMismatch, problemNotGit, problemMissing, problemNoOrigin,
+			fixRegister, fixUpdateUrl, fixRemove, fixReclone,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, fix := range flags.fixes {
+				if !slices.Contains([]string{fixRegister, fixUpdateUrl, fixRemove, fixReclone}, fix) {
+					return fmt.Errorf(
+						"unsupported fix '%v', must be one of '%v' '%v' '%v' '%v'", fix,
+						fixRegister, fixUpdateUrl, fixRemove, fixReclone,
+					)
+				}
+			}
+			if slices.Contains(flags.fixes, fixRemove) && slices.Contains(flags.fixes, fixReclone) {
+				return fmt.Errorf("incompatible '%v' and '%v' fixes", fixRemove, fixReclone)
+			}
+
+			conf := config.ReadConfig()
+			manager := settings.NewManager(conf, sh)
+			gitService := gitops.NewGitService(sh, conf.GitBackend)
+
+			sets, err := manager.Read()
+			if err != nil {
+				return err
+			}
+
+			findings, err := diagnose(conf, &gitService, sets)
+			if err != nil {
+				return err
+			}
+
+			if hasSettingsFixes(findings, flags.fixes) {
+				err = manager.Update(
+					func(sets *settings.Settings) error {
+						return applySettingsFixes(sets, findings, flags.fixes)
+					},
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			entityInfoMap := map[string]output.EntityInfo{}
+			for name, finding := range findings {
+				if finding.problem == problemMissing || finding.problem == problemNotGit {
+					err := applyRecloneFix(conf, &gitService, sets, name, finding, flags.fixes)
+					if err != nil {
+						entityInfoMap[name] = output.EntityInfo{Result: finding.toResult(), Error: err}
+						continue
+					}
+				}
+
+				entityInfoMap[name] = output.EntityInfo{Result: finding.toResult()}
+			}
+
+			err = output.Write(cmd.OutOrStdout(), "repo", entityInfoMap)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	result.Flags().StringSliceVar(
+		&flags.fixes, "fix", []string{}, fmt.Sprintf(
+			"Fixes to apply to the found problems. Available fixes: %v, %v, %v, %v",
+			fixRegister, fixUpdateUrl, fixRemove, fixReclone,
+		),
+	)
+
+	return result
+}
+
+// diagnose returns problems found, keyed by the repository or directory name. Healthy repositories are omitted
+func diagnose(conf *config.Config, gitService *gitops.GitService, sets *settings.Settings) (
+	map[string]*doctorFinding, error,
+) {
+	findings := map[string]*doctorFinding{}
+
+	for _, repo := range sets.Repos {
+		modelRepo := newModelRepo(conf, repo.Name, repo.Url)
+
+		err := fileops.CheckRepoExists(modelRepo)
+		if err != nil {
+			if errors.Is(err, fileops.ErrRepositoryNotCloned) {
+				findings[repo.Name] = &doctorFinding{problem: problemMissing}
+				continue
+			}
+			return nil, err
+		}
+
+		isGit, err := fileops.IsGitRepo(modelRepo)
+		if err != nil {
+			return nil, err
+		}
+		if !isGit {
+			findings[repo.Name] = &doctorFinding{problem: problemNotGit}
+			continue
+		}
+
+		remoteUrl, err := gitService.GetNamedRemoteUrl(modelRepo, "origin")
+		if err != nil {
+			findings[repo.Name] = &doctorFinding{problem: problemNoOrigin, details: err.Error()}
+			continue
+		}
+		if remoteUrl != repo.Url {
+			findings[repo.Name] = &doctorFinding{
+				problem: problemUrlMismatch,
+				details: fmt.Sprintf("working copy uses %v", remoteUrl),
+				url:     remoteUrl,
+			}
+		}
+	}
+
+	// the repositories directory is created with the first clone
+	entries, err := os.ReadDir(conf.ReposDirectory)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read repositories directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || sets.RepoExists(entry.Name()) {
+			continue
+		}
+
+		modelRepo := newModelRepo(conf, entry.Name(), "")
+		isGit, err := fileops.IsGitRepo(modelRepo)
+		if err != nil {
+			return nil, err
+		}
+		if !isGit {
+			findings[entry.Name()] = &doctorFinding{problem: problemOrphan, details: string(problemNotGit)}
+			continue
+		}
+
+		remoteUrl, err := gitService.GetRemoteUrl(modelRepo)
+		if err != nil {
+			findings[entry.Name()] = &doctorFinding{problem: problemOrphan, details: err.Error()}
+			continue
+		}
+
+		findings[entry.Name()] = &doctorFinding{problem: problemOrphan, details: remoteUrl, url: remoteUrl}
+	}
+
+	return findings, nil
+}
+
+// settingsFix returns the requested fix of the finding that changes the settings, or an empty string if there is none
+func (f *doctorFinding) settingsFix(fixes []string) string {
+	switch {
+	case f.problem == problemOrphan && f.url != "" && slices.Contains(fixes, fixRegister):
+		return fixRegister
+	case f.problem == problemUrlMismatch && slices.Contains(fixes, fixUpdateUrl):
+		return fixUpdateUrl
+	case f.problem == problemMissing && slices.Contains(fixes, fixRemove):
+		return fixRemove
+	default:
+		return ""
+	}
+}
+
+func hasSettingsFixes(findings map[string]*doctorFinding, fixes []string) bool {
+	for _, finding := range findings {
+		if finding.settingsFix(fixes) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applySettingsFixes(sets *settings.Settings, findings map[string]*doctorFinding, fixes []string) error {
+	for name, finding := range findings {
+		switch finding.settingsFix(fixes) {
+		case fixRegister:
+			err := sets.AddRepo(name, finding.url, []string{})
+			if err != nil {
+				return err
+			}
+			finding.fix = "registered"
+		case fixUpdateUrl:
+			err := sets.UpdateRepoUrl(name, finding.url)
+			if err != nil {
+				return err
+			}
+			finding.fix = "url updated"
+		case fixRemove:
+			err := sets.RemoveRepo(name)
+			if err != nil {
+				return err
+			}
+			finding.fix = "removed"
+		}
+	}
+
+	return nil
+}
+
+func applyRecloneFix(
+	conf *config.Config, gitService *gitops.GitService, sets *settings.Settings, name string,
+	finding *doctorFinding, fixes []string,
+) error {
+	if !slices.Contains(fixes, fixReclone) {
+		return nil
+	}
+
+	repo, err := sets.GetRepo(name)
+	if err != nil {
+		return err
+	}
+
+	cloneResult, err := gitService.CloneRepo(newModelRepo(conf, repo.Name, repo.Url), true)
+	if err != nil {
+		return err
+	}
+
+	finding.fix = cloneResult.String()
+	return nil
+}
+
+func newModelRepo(conf *config.Config, name string, url string) *model.Repo {
+	return &model.Repo{
+		Name: name,
+		Path: filepath.Join(conf.ReposDirectory, name),
+		Url:  url,
+	}
+}
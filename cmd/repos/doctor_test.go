@@ -0,0 +1,225 @@
+package repos
+
+import (
+	"errors"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+type testDoctorResult struct {
+	Repo    string `json:"repo"`
+	Problem string `json:"problem"`
+	Details string `json:"details"`
+	Fix     string `json:"fix"`
+	Error   string `json:"error,omitempty"`
+}
+
+func prepareDoctor(t *testing.T) shell.Shell {
+	repos := []settings.Repo{
+		{Name: "healthy", Url: "https://example.com/tenant/healthy.git"},
+		{Name: "moved", Url: "https://example.com/tenant/moved.git"},
+		{Name: "plain", Url: "https://example.com/tenant/plain.git"},
+		{Name: "missing", Url: "https://example.com/tenant/missing.git"},
+	}
+	remoteUrls := map[string]string{
+		"healthy": "https://example.com/tenant/healthy.git",
+		"moved":   "https://example.com/another/moved.git",
+		"orphan":  "https://example.com/tenant/orphan.git",
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			commandLine := tests.ShellCommandToString(command, arguments)
+			switch commandLine {
+			case "git remote":
+				return "origin\n", nil
+			case "git remote get-url origin":
+				return remoteUrls[repoName] + "\n", nil
+			case "git clone --recurse-submodules https://example.com/tenant/" + repoName + ".git .":
+				return "OK", nil
+			}
+			return "", errors.New("shell not mocked: " + repoName + " " + commandLine)
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+
+	for _, directory := range []string{"healthy/.git", "moved/.git", "orphan/.git", "plain/src"} {
+		err := os.MkdirAll(tests.Path(directory), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	return sh
+}
+
+func TestDoctor(t *testing.T) {
+	sh := prepareDoctor(t)
+
+	command := CreateDoctorCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "doctor", c.Name())
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testDoctorResult{
+					{Repo: "missing", Problem: "missing"},
+					{
+						Repo: "moved", Problem: "url mismatch",
+						Details: "working copy uses https://example.com/another/moved.git",
+					},
+					{Repo: "orphan", Problem: "orphan", Details: "https://example.com/tenant/orphan.git"},
+					{Repo: "plain", Problem: "not a git repository"},
+				},
+			), output,
+		)
+	}
+}
+
+func TestDoctor_FixSettings(t *testing.T) {
+	sh := prepareDoctor(t)
+
+	command := CreateDoctorCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "--fix register,update-url,remove")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testDoctorResult{
+					{Repo: "missing", Problem: "missing", Fix: "removed"},
+					{
+						Repo: "moved", Problem: "url mismatch",
+						Details: "working copy uses https://example.com/another/moved.git", Fix: "url updated",
+					},
+					{
+						Repo: "orphan", Problem: "orphan", Details: "https://example.com/tenant/orphan.git",
+						Fix: "registered",
+					},
+					{Repo: "plain", Problem: "not a git repository"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.False(t, sets.RepoExists("missing"))
+		moved, err := sets.GetRepo("moved")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/another/moved.git", moved.Url)
+		}
+		orphan, err := sets.GetRepo("orphan")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/tenant/orphan.git", orphan.Url)
+		}
+	}
+}
+
+func TestDoctor_FixReclone(t *testing.T) {
+	sh := prepareDoctor(t)
+
+	command := CreateDoctorCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "--fix reclone")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testDoctorResult{
+					{Repo: "missing", Problem: "missing", Fix: "cloned"},
+					{
+						Repo: "moved", Problem: "url mismatch",
+						Details: "working copy uses https://example.com/another/moved.git",
+					},
+					{Repo: "orphan", Problem: "orphan", Details: "https://example.com/tenant/orphan.git"},
+					{Repo: "plain", Problem: "not a git repository", Fix: "re-cloned"},
+				},
+			), output,
+		)
+	}
+}
+
+func TestDoctor_IncompatibleFixes(t *testing.T) {
+	sh := prepareDoctor(t)
+
+	command := CreateDoctorCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--fix remove,reclone")
+	if assert.Error(t, err) {
+		assert.Equal(t, "incompatible 'remove' and 'reclone' fixes", err.Error())
+	}
+}
+
+func TestDoctor_UnsupportedFix(t *testing.T) {
+	sh := prepareDoctor(t)
+
+	command := CreateDoctorCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--fix unknown")
+	if assert.Error(t, err) {
+		assert.Equal(
+			t, "unsupported fix 'unknown', must be one of 'register' 'update-url' 'remove' 'reclone'", err.Error(),
+		)
+	}
+}
+
+func TestDoctor_NoOrigin(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "upstream", Url: "https://example.com/tenant/upstream.git"},
+	}
+	sh := tests.MockShellMap(map[string]tests.MockResult{})
+	tests.PrepareBulker(t, sh, repos)
+	err := os.MkdirAll(tests.Path("upstream", ".git"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateDoctorCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testDoctorResult{
+					{
+						Repo: "upstream", Problem: "no origin remote",
+						Details: "failed to get remote url: , shell not mocked: upstream git remote get-url origin",
+					},
+				},
+			), output,
+		)
+	}
+}
+
+func TestDoctor_MissingReposDirectory(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "missing", Url: "https://example.com/tenant/missing.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	settingsFileName := config.ReadConfig().SettingsFileName
+	viper.Set("reposDirectory", tests.Path("absent"))
+	viper.Set("settingsFileName", settingsFileName)
+
+	command := CreateDoctorCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString([]testDoctorResult{{Repo: "missing", Problem: "missing"}}), output,
+		)
+	}
+}
+
+func TestDoctor_SettingsNotWrittenWithoutFixes(t *testing.T) {
+	sh := prepareDoctor(t)
+	settingsFileName := config.ReadConfig().SettingsFileName
+	modified := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := os.Chtimes(settingsFileName, modified, modified)
+	assert.NoError(t, err)
+
+	command := CreateDoctorCommand(sh)
+	_, _, err = tests.ExecuteCommand(command, "")
+	assert.NoError(t, err)
+
+	info, err := os.Stat(settingsFileName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, modified, info.ModTime().UTC())
+	}
+}
@@ -0,0 +1,118 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/runner"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+func CreateEditCommand(sh shell.Shell) *cobra.Command {
+	var filter = runner.Filter{}
+
+	var flags struct {
+		url        string
+		tags       []string
+		addTags    []string
+		removeTags []string
+		setTags    bool
+	}
+
+	repoHandler := runner.NewCommandRunner(
+		&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
+			type result struct {
+				Url  string
+				Tags string
+			}
+
+			var repo *settings.Repo
+			err := runContext.Manager.Update(
+				func(sets *settings.Settings) error {
+					name := runContext.Repo.Name
+
+					if flags.url != "" {
+						err := sets.UpdateRepoUrl(name, flags.url)
+						if err != nil {
+							return err
+						}
+					}
+
+					if flags.setTags {
+						err := sets.SetRepoTags(name, flags.tags)
+						if err != nil {
+							return err
+						}
+					}
+
+					err := sets.AddRepoTags(name, flags.addTags)
+					if err != nil {
+						return err
+					}
+
+					err = sets.RemoveRepoTags(name, flags.removeTags)
+					if err != nil {
+						return err
+					}
+
+					repo, err = sets.GetRepo(name)
+					return err
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return result{Url: repo.Url, Tags: strings.Join(repo.Tags, ", ")}, nil
+		},
+	)
+
+	var result = &cobra.Command{
+		Use:   "edit",
+		Short: "Changes URL and tags of the supported repositories",
+		Long: `Changes URL and tags of the supported repositories.
+Tags can be changed for many repositories at once. URL can be changed only for a single repository.
+
+Examples:
+"bulker repos edit -n api --url https://example.com/tenant/api.git" - will change the URL of "api" repository
+"bulker repos edit -t backend --add-tags java" - will add "java" tag to all repositories with "backend" tag
+"bulker repos edit -g previous --tags ''" - will remove all tags from repositories of "previous" group`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.setTags = cmd.Flags().Changed("tags")
+
+			if flags.url == "" && !flags.setTags && len(flags.addTags) == 0 && len(flags.removeTags) == 0 {
+				return errors.New("nothing to edit, use --url, --tags, --add-tags or --remove-tags")
+			}
+
+			if flags.url != "" {
+				sets, err := settings.NewManager(config.ReadConfig(), sh).Read()
+				if err != nil {
+					return err
+				}
+
+				matchingRepos := filter.FilterMatchingRepos(sets.Repos, sets.Groups)
+				if len(matchingRepos) != 1 {
+					return fmt.Errorf("URL can be changed only for a single repository, but %v matched", len(matchingRepos))
+				}
+			}
+
+			return repoHandler(cmd, args)
+		},
+	}
+
+	filter.AddRepoFlags(result)
+
+	result.Flags().StringVarP(&flags.url, "url", "u", "", "New URL of the repository")
+	result.Flags().StringSliceVar(&flags.tags, "tags", []string{}, "Replace all the repository tags with these ones")
+	result.Flags().StringSliceVar(&flags.addTags, "add-tags", []string{}, "Tags to add to the repository")
+	result.Flags().StringSliceVar(&flags.removeTags, "remove-tags", []string{}, "Tags to remove from the repository")
+	result.MarkFlagsMutuallyExclusive("tags", "add-tags")
+	result.MarkFlagsMutuallyExclusive("tags", "remove-tags")
+
+	return result
+}
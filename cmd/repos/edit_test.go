@@ -0,0 +1,128 @@
+package repos
+
+import (
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testEditResult struct {
+	Repo  string `json:"repo"`
+	Url   string `json:"url"`
+	Tags  string `json:"tags"`
+	Error string `json:"error,omitempty"`
+}
+
+func TestEdit_Url(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git", Tags: []string{"one"}},
+		{Name: "another", Url: "https://example.com/tenant/another.git", Tags: []string{"one"}},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateEditCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-n repo --url https://example.com/new/repo.git")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "edit", c.Name())
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testEditResult{
+					{Repo: "repo", Url: "https://example.com/new/repo.git", Tags: "one"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		repo, err := sets.GetRepo("another")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/tenant/another.git", repo.Url)
+		}
+	}
+}
+
+func TestEdit_UrlForManyRepos(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git"},
+		{Name: "another", Url: "https://example.com/tenant/another.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateEditCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--url https://example.com/new/repo.git")
+	if assert.Error(t, err) {
+		assert.Equal(t, "URL can be changed only for a single repository, but 2 matched", err.Error())
+	}
+}
+
+func TestEdit_AddAndRemoveTags(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git", Tags: []string{"one", "two"}},
+		{Name: "another", Url: "https://example.com/tenant/another.git", Tags: []string{"two"}},
+		{Name: "skipped", Url: "https://example.com/tenant/skipped.git", Tags: []string{"one"}},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateEditCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-t two --add-tags three,one --remove-tags two")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testEditResult{
+					{Repo: "another", Url: "https://example.com/tenant/another.git", Tags: "three, one"},
+					{Repo: "repo", Url: "https://example.com/tenant/repo.git", Tags: "one, three"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		repo, err := sets.GetRepo("skipped")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"one"}, repo.Tags)
+		}
+	}
+}
+
+func TestEdit_SetTags(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git", Tags: []string{"one", "two"}},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateEditCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo --tags three")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testEditResult{
+					{Repo: "repo", Url: "https://example.com/tenant/repo.git", Tags: "three"},
+				},
+			), output,
+		)
+	}
+}
+
+func TestEdit_NothingToEdit(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateEditCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-n repo")
+	if assert.Error(t, err) {
+		assert.Equal(t, "nothing to edit, use --url, --tags, --add-tags or --remove-tags", err.Error())
+	}
+}
@@ -6,40 +6,70 @@ import (
 	"github.com/mih-kopylov/bulker/internal/output"
 	"github.com/mih-kopylov/bulker/internal/settings"
 	"github.com/mih-kopylov/bulker/internal/shell"
-	"github.com/mih-kopylov/bulker/internal/utils"
 	"github.com/spf13/cobra"
+	"strings"
 )
 
 func CreateExportCommand(sh shell.Shell) *cobra.Command {
 	var flags struct {
-		remote string
+		remote     string
+		file       string
+		branch     string
+		path       string
+		message    string
+		pushBranch string
 	}
 
 	var result = &cobra.Command{
 		Use:   "export",
-		Short: "Exports the repositories configuration into an external git repository",
+		Short: "Exports the repositories configuration into an external git repository or a file",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager := settings.NewManager(config.ReadConfig(), sh)
-			exportResult, err := manager.Export(flags.remote)
+			outputWriter := cmd.OutOrStdout()
+
+			var exportResult map[string]settings.ExportImportResult
+			var err error
+			if flags.file != "" {
+				if flags.file == settings.StdStreamFileName {
+					// stdout is occupied with the exported content
+					outputWriter = cmd.ErrOrStderr()
+				}
+				exportResult, err = manager.ExportTo(settings.NewFileTransport(flags.file, nil, cmd.OutOrStdout()))
+			} else {
+				exportResult, err = manager.Export(
+					flags.remote, settings.GitTransportOptions{
+						Branch:        flags.branch,
+						Path:          flags.path,
+						CommitMessage: flags.message,
+						PushBranch:    flags.pushBranch,
+					},
+				)
+			}
 			if err != nil {
 				return err
 			}
 
 			entityInfoMap := map[string]output.EntityInfo{}
-			for repo, status := range exportResult {
-				switch status {
+			for repo, repoResult := range exportResult {
+				switch repoResult.Status {
 				case settings.ExportImportStatusUpToDate:
 					//omit in the result
 				case settings.ExportImportStatusAdded:
 					entityInfoMap[repo] = output.EntityInfo{Result: "exported"}
 				case settings.ExportImportStatusRemoved:
 					entityInfoMap[repo] = output.EntityInfo{Result: "removal exported"}
+				case settings.ExportImportStatusUpdated:
+					entityInfoMap[repo] = output.EntityInfo{
+						Result: fmt.Sprintf("update exported: %v", strings.Join(repoResult.Fields, ", ")),
+					}
 				default:
-					entityInfoMap[repo] = output.EntityInfo{Error: fmt.Errorf("status %v is not supported", status)}
+					entityInfoMap[repo] = output.EntityInfo{
+						Error: fmt.Errorf("status %v is not supported", repoResult.Status),
+					}
 				}
 			}
 
-			err = output.Write(cmd.OutOrStdout(), "repo", entityInfoMap)
+			err = output.Write(outputWriter, "repo", entityInfoMap)
 			if err != nil {
 				return err
 			}
@@ -49,7 +79,28 @@ func CreateExportCommand(sh shell.Shell) *cobra.Command {
 	}
 
 	result.Flags().StringVarP(&flags.remote, "remote", "r", "", "URL of the remote repository")
-	utils.MarkFlagRequiredOrFail(result.Flags(), "remote")
+	result.Flags().StringVarP(
+		&flags.file, "file", "f", "", `Local file name to write to.
+Use "-" to write to stdout`,
+	)
+	result.Flags().StringVarP(
+		&flags.branch, "branch", "b", "", "Branch of the remote repository to export to. The default branch is used if empty",
+	)
+	result.Flags().StringVarP(
+		&flags.path, "path", "p", "repos.yaml", "Path of the file relative to the remote repository root",
+	)
+	result.Flags().StringVarP(
+		&flags.message, "message", "m", "Export bulker repositories", "Message of the export commit",
+	)
+	result.Flags().StringVar(
+		&flags.pushBranch, "push-branch", "", `New branch to push the export commit to instead of the target one.
+Use it to open a pull request and review the changes before they are merged`,
+	)
+	result.MarkFlagsOneRequired("remote", "file")
+	result.MarkFlagsMutuallyExclusive("remote", "file")
+	for _, remoteFlag := range []string{"branch", "path", "message", "push-branch"} {
+		result.MarkFlagsMutuallyExclusive("file", remoteFlag)
+	}
 
 	return result
 }
@@ -1,6 +1,7 @@
 package repos
 
 import (
+	"bytes"
 	"github.com/mih-kopylov/bulker/internal/settings"
 	"github.com/mih-kopylov/bulker/internal/shell"
 	"github.com/mih-kopylov/bulker/internal/tests"
@@ -48,14 +49,287 @@ func TestExport_ToEmptyRepo(t *testing.T) {
 		if assert.NoError(t, err) {
 			assert.YAMLEq(
 				t, `
-version: 1
+version: 2
 data:
     repos:
         repo:
             url: https://example.com
             tags: []
+    groups: {}
 `, reposFileContent,
 			)
 		}
 	}
 }
+
+func TestExport_ToFile(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	exportFileName := tests.Path("export.yaml")
+
+	command := CreateExportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-f "+exportFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString([]testExportResult{{Repo: "repo", Result: "exported"}}), output,
+		)
+
+		reposFileContent, err := os.ReadFile(exportFileName)
+		if assert.NoError(t, err) {
+			assert.YAMLEq(
+				t, `
+version: 2
+data:
+    repos:
+        repo:
+            url: https://example.com
+            tags: []
+    groups: {}
+`, string(reposFileContent),
+			)
+		}
+	}
+
+	command = CreateExportCommand(sh)
+	_, output, err = tests.ExecuteCommand(command, "-f "+exportFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, "[]", output)
+	}
+}
+
+func TestExport_ToStdout(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateExportCommand(sh)
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	command.SetOut(stdout)
+	command.SetErr(stderr)
+	command.SetArgs([]string{"-f", "-"})
+	err := command.Execute()
+	if assert.NoError(t, err) {
+		assert.YAMLEq(
+			t, `
+version: 2
+data:
+    repos:
+        repo:
+            url: https://example.com
+            tags: []
+    groups: {}
+`, stdout.String(),
+		)
+		assert.JSONEq(
+			t, tests.ToJsonString([]testExportResult{{Repo: "repo", Result: "exported"}}), stderr.String(),
+		)
+	}
+}
+
+func TestExport_RequiredFlags(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, nil)
+
+	command := CreateExportCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "")
+	if assert.Error(t, err) {
+		assert.Equal(t, "at least one of the flags in the group [remote file] is required", err.Error())
+	}
+}
+
+func TestExport_Groups(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com"},
+		{Name: "another", Url: "https://example.com/another"},
+	}
+	groups := []settings.Group{
+		{Name: "group", Repos: []string{"repo", "another"}},
+		{Name: settings.PreviousGroupName, Repos: []string{"repo"}},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulkerWithGroups(t, sh, repos, groups)
+	exportFileName := tests.Path("export.yaml")
+
+	command := CreateExportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-f "+exportFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testExportResult{
+					{Repo: "another", Result: "exported"},
+					{Repo: "group:group", Result: "exported"},
+					{Repo: "repo", Result: "exported"},
+				},
+			), output,
+		)
+
+		reposFileContent, err := os.ReadFile(exportFileName)
+		if assert.NoError(t, err) {
+			assert.YAMLEq(
+				t, `
+version: 2
+data:
+    repos:
+        another:
+            url: https://example.com/another
+            tags: []
+        repo:
+            url: https://example.com
+            tags: []
+    groups:
+        group:
+            repos: [another, repo]
+`, string(reposFileContent),
+			)
+		}
+	}
+}
+
+func TestExport_ToPushBranch(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com"},
+	}
+	sh := &shell.NativeShell{}
+	tests.PrepareBulker(t, sh, repos)
+	bareGitRepo, err := tests.CreateBareGitRepository("likeRemoteGitRepo")
+	assert.NoError(t, err)
+
+	command := CreateExportCommand(sh)
+	_, _, err = tests.ExecuteCommand(command, "-r "+bareGitRepo)
+	assert.NoError(t, err)
+
+	tests.PrepareBulker(t, sh, append(repos, settings.Repo{Name: "another", Url: "https://example.com/another"}))
+	command = CreateExportCommand(sh)
+	_, output, err := tests.ExecuteCommand(
+		command, "-r "+bareGitRepo+" -p config/bulker.yaml --push-branch review -m Update",
+	)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testExportResult{
+					{Repo: "another", Result: "exported"},
+					{Repo: "repo", Result: "exported"},
+				},
+			), output,
+		)
+
+		commit, err := tests.GetBranchCommit(bareGitRepo, "review")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Update\n", commit.Message)
+			file, err := commit.File("config/bulker.yaml")
+			if assert.NoError(t, err) {
+				content, err := file.Contents()
+				assert.NoError(t, err)
+				assert.Contains(t, content, "https://example.com/another")
+			}
+		}
+
+		masterContent, err := tests.GetFileContent(bareGitRepo, "repos.yaml")
+		if assert.NoError(t, err) {
+			assert.NotContains(t, masterContent, "another")
+		}
+	}
+}
+
+func TestExport_ToBranch(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com"},
+	}
+	sh := &shell.NativeShell{}
+	tests.PrepareBulker(t, sh, repos)
+	bareGitRepo, err := tests.CreateBareGitRepository("likeRemoteGitRepo")
+	assert.NoError(t, err)
+
+	command := CreateExportCommand(sh)
+	_, _, err = tests.ExecuteCommand(command, "-r "+bareGitRepo+" --push-branch review")
+	assert.NoError(t, err)
+
+	tests.PrepareBulker(t, sh, append(repos, settings.Repo{Name: "another", Url: "https://example.com/another"}))
+	command = CreateExportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-r "+bareGitRepo+" -b review")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString([]testExportResult{{Repo: "another", Result: "exported"}}), output,
+		)
+
+		commit, err := tests.GetBranchCommit(bareGitRepo, "review")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Export bulker repositories\n", commit.Message)
+			file, err := commit.File("repos.yaml")
+			if assert.NoError(t, err) {
+				content, err := file.Contents()
+				assert.NoError(t, err)
+				assert.Contains(t, content, "https://example.com/another")
+			}
+		}
+	}
+}
+
+func TestExport_RemoteFlagsWithFile(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, nil)
+
+	command := CreateExportCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-f export.yaml -b main")
+	if assert.Error(t, err) {
+		assert.Equal(
+			t, "if any flags in the group [file branch] are set none of the others can be; [branch file] were all set",
+			err.Error(),
+		)
+	}
+}
+
+func TestExport_UpdatedRepo(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, []settings.Repo{{Name: "repo", Url: "https://example.com"}})
+	exportFileName := tests.Path("export.yaml")
+
+	command := CreateExportCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-f "+exportFileName)
+	assert.NoError(t, err)
+
+	tests.PrepareBulker(
+		t, sh, []settings.Repo{{Name: "repo", Url: "https://example.com/moved", Tags: []string{"tag"}}},
+	)
+	command = CreateExportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-f "+exportFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString([]testExportResult{{Repo: "repo", Result: "update exported: url, tags"}}), output,
+		)
+	}
+}
+
+func TestExport_Environments(t *testing.T) {
+	for _, environment := range tests.GitEnvironments {
+		t.Run(
+			environment.Name, func(t *testing.T) {
+				environment.Set(t)
+				sh := &shell.NativeShell{}
+				tests.PrepareBulker(t, sh, []settings.Repo{{Name: "repo", Url: "https://example.com"}})
+				bareGitRepo, err := tests.CreateBareGitRepository("likeRemoteGitRepo")
+				assert.NoError(t, err)
+
+				command := CreateExportCommand(sh)
+				_, output, err := tests.ExecuteCommand(command, "-r "+bareGitRepo)
+				if assert.NoError(t, err) {
+					assert.JSONEq(
+						t, tests.ToJsonString([]testExportResult{{Repo: "repo", Result: "exported"}}), output,
+					)
+				}
+
+				command = CreateExportCommand(sh)
+				_, output, err = tests.ExecuteCommand(command, "-r "+bareGitRepo)
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, "[]", output)
+				}
+			},
+		)
+	}
+}
@@ -6,36 +6,62 @@ import (
 	"github.com/mih-kopylov/bulker/internal/output"
 	"github.com/mih-kopylov/bulker/internal/settings"
 	"github.com/mih-kopylov/bulker/internal/shell"
-	"github.com/mih-kopylov/bulker/internal/utils"
 	"github.com/spf13/cobra"
+	"strings"
 )
 
 func CreateImportCommand(sh shell.Shell) *cobra.Command {
 	var flags struct {
-		remote string
+		remote   string
+		file     string
+		branch   string
+		path     string
+		strategy settings.MergeStrategy
 	}
 
 	var result = &cobra.Command{
 		Use:   "import",
-		Short: "Imports the repositories configuration from an external git repository",
+		Short: "Imports the repositories configuration from an external git repository or a file",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager := settings.NewManager(config.ReadConfig(), sh)
-			importResult, err := manager.Import(flags.remote)
+
+			var importResult map[string]settings.ExportImportResult
+			var err error
+			if flags.file != "" {
+				importResult, err = manager.ImportFrom(
+					settings.NewFileTransport(flags.file, cmd.InOrStdin(), nil), flags.strategy,
+				)
+			} else {
+				importResult, err = manager.Import(
+					flags.remote, settings.GitTransportOptions{Branch: flags.branch, Path: flags.path},
+					flags.strategy,
+				)
+			}
 			if err != nil {
 				return err
 			}
 
 			entityInfoMap := map[string]output.EntityInfo{}
-			for repo, status := range importResult {
-				switch status {
+			for repo, repoResult := range importResult {
+				switch repoResult.Status {
 				case settings.ExportImportStatusUpToDate:
 					//omit in the result
 				case settings.ExportImportStatusAdded:
 					entityInfoMap[repo] = output.EntityInfo{Result: "imported"}
 				case settings.ExportImportStatusRemoved:
 					entityInfoMap[repo] = output.EntityInfo{Result: "removed"}
+				case settings.ExportImportStatusUpdated:
+					entityInfoMap[repo] = output.EntityInfo{
+						Result: fmt.Sprintf("updated: %v", strings.Join(repoResult.Fields, ", ")),
+					}
+				case settings.ExportImportStatusConflict:
+					entityInfoMap[repo] = output.EntityInfo{
+						Result: fmt.Sprintf("conflict: %v", strings.Join(repoResult.Fields, ", ")),
+					}
 				default:
-					entityInfoMap[repo] = output.EntityInfo{Error: fmt.Errorf("status %v is not supported", status)}
+					entityInfoMap[repo] = output.EntityInfo{
+						Error: fmt.Errorf("status %v is not supported", repoResult.Status),
+					}
 				}
 			}
 
@@ -49,7 +75,36 @@ func CreateImportCommand(sh shell.Shell) *cobra.Command {
 	}
 
 	result.Flags().StringVarP(&flags.remote, "remote", "r", "", "URL of the remote repository")
-	utils.MarkFlagRequiredOrFail(result.Flags(), "remote")
+	result.Flags().StringVarP(
+		&flags.file, "file", "f", "", `Local file name to read from.
+Use "-" to read from stdin`,
+	)
+	result.Flags().StringVarP(
+		&flags.branch, "branch", "b", "", "Branch of the remote repository to import from. The default branch is used if empty",
+	)
+	result.Flags().StringVarP(
+		&flags.path, "path", "p", "repos.yaml", "Path of the file relative to the remote repository root",
+	)
+	result.MarkFlagsOneRequired("remote", "file")
+
+	flags.strategy = settings.MergeTheirs
+	result.Flags().VarP(
+		&flags.strategy, "strategy", "s", fmt.Sprintf(
+			`How to merge the imported repositories with the local ones:
+* %v - the imported list replaces the local one, local-only repositories are removed
+* %v - only new repositories are added, the existing ones are kept as is
+* %v - new repositories are added, tags of the existing ones are merged
+New groups are added with every strategy. Repositories of existing groups are replaced with '%v',
+kept as is with '%v' and merged with '%v'. Local-only groups are always kept.
+Groups with repositories that are missing after the import are not changed.
+Repositories and groups that differ and are not updated are reported as conflicts`,
+			settings.MergeTheirs, settings.MergeOurs, settings.MergeUnion,
+			settings.MergeTheirs, settings.MergeOurs, settings.MergeUnion,
+		),
+	)
+	result.MarkFlagsMutuallyExclusive("remote", "file")
+	result.MarkFlagsMutuallyExclusive("file", "branch")
+	result.MarkFlagsMutuallyExclusive("file", "path")
 
 	return result
 }
@@ -0,0 +1,360 @@
+package repos
+
+import (
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+type testImportResult struct {
+	Repo   string `json:"repo"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+const testImportContent = `
+version: 1
+data:
+    repos:
+        imported:
+            url: https://example.com/imported.git
+            tags:
+                - one
+`
+
+func TestImport_FromFile(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "local", Url: "https://example.com/local.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	importFileName := tests.Path("import.yaml")
+	err := os.WriteFile(importFileName, []byte(testImportContent), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateImportCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-f "+importFileName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "import", c.Name())
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testImportResult{
+					{Repo: "imported", Result: "imported"},
+					{Repo: "local", Result: "removed"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.False(t, sets.RepoExists("local"))
+		repo, err := sets.GetRepo("imported")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/imported.git", repo.Url)
+			assert.Equal(t, []string{"one"}, repo.Tags)
+		}
+	}
+}
+
+func TestImport_FromStdin(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, nil)
+
+	command := CreateImportCommand(sh)
+	command.SetIn(strings.NewReader(testImportContent))
+	_, output, err := tests.ExecuteCommand(command, "-f -")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString([]testImportResult{{Repo: "imported", Result: "imported"}}), output,
+		)
+	}
+}
+
+func TestImport_FileNotFound(t *testing.T) {
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, nil)
+	importFileName := tests.Path("missing.yaml")
+
+	command := CreateImportCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-f "+importFileName)
+	if assert.Error(t, err) {
+		assert.Equal(t, importFileName+" not found", err.Error())
+	}
+}
+
+const testMergeImportContent = `
+version: 1
+data:
+    repos:
+        same:
+            url: https://example.com/same.git
+            tags: [one]
+        tags:
+            url: https://example.com/tags.git
+            tags: [two]
+        url:
+            url: https://example.com/imported/url.git
+            tags: []
+        new:
+            url: https://example.com/new.git
+            tags: []
+`
+
+func prepareMergeImport(t *testing.T) string {
+	repos := []settings.Repo{
+		{Name: "same", Url: "https://example.com/same.git", Tags: []string{"one"}},
+		{Name: "tags", Url: "https://example.com/tags.git", Tags: []string{"one"}},
+		{Name: "url", Url: "https://example.com/local/url.git", Tags: []string{}},
+		{Name: "local", Url: "https://example.com/local.git", Tags: []string{}},
+	}
+	groups := []settings.Group{
+		{Name: "group", Repos: []string{"local", "same"}},
+	}
+	tests.PrepareBulkerWithGroups(t, tests.MockShellEmpty(), repos, groups)
+
+	importFileName := tests.Path("import.yaml")
+	err := os.WriteFile(importFileName, []byte(testMergeImportContent), os.ModePerm)
+	assert.NoError(t, err)
+	return importFileName
+}
+
+func TestImport_StrategyTheirs(t *testing.T) {
+	importFileName := prepareMergeImport(t)
+	sh := tests.MockShellEmpty()
+
+	command := CreateImportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-s theirs -f "+importFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testImportResult{
+					{Repo: "local", Result: "removed"},
+					{Repo: "new", Result: "imported"},
+					{Repo: "tags", Result: "updated: tags"},
+					{Repo: "url", Result: "updated: url"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.False(t, sets.RepoExists("local"))
+		repo, err := sets.GetRepo("url")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/imported/url.git", repo.Url)
+		}
+		group, err := sets.GetGroup("group")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"same"}, group.Repos)
+		}
+	}
+}
+
+func TestImport_StrategyOurs(t *testing.T) {
+	importFileName := prepareMergeImport(t)
+	sh := tests.MockShellEmpty()
+
+	command := CreateImportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-s ours -f "+importFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testImportResult{
+					{Repo: "new", Result: "imported"},
+					{Repo: "tags", Result: "conflict: tags"},
+					{Repo: "url", Result: "conflict: url"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.True(t, sets.RepoExists("local"))
+		repo, err := sets.GetRepo("tags")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"one"}, repo.Tags)
+		}
+		group, err := sets.GetGroup("group")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"local", "same"}, group.Repos)
+		}
+	}
+}
+
+func TestImport_StrategyUnion(t *testing.T) {
+	importFileName := prepareMergeImport(t)
+	sh := tests.MockShellEmpty()
+
+	command := CreateImportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-s union -f "+importFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testImportResult{
+					{Repo: "new", Result: "imported"},
+					{Repo: "tags", Result: "updated: tags"},
+					{Repo: "url", Result: "conflict: url"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.True(t, sets.RepoExists("local"))
+		repo, err := sets.GetRepo("tags")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"one", "two"}, repo.Tags)
+		}
+		repo, err = sets.GetRepo("url")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/local/url.git", repo.Url)
+		}
+	}
+}
+
+func TestImport_UnsupportedStrategy(t *testing.T) {
+	importFileName := prepareMergeImport(t)
+	sh := tests.MockShellEmpty()
+
+	command := CreateImportCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-s unknown -f "+importFileName)
+	if assert.Error(t, err) {
+		assert.Equal(
+			t, `invalid argument "unknown" for "-s, --strategy" flag: must be one of 'theirs' 'ours' 'union'`,
+			err.Error(),
+		)
+	}
+}
+
+func TestImport_Groups(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/repo.git", Tags: []string{}},
+	}
+	groups := []settings.Group{
+		{Name: "local", Repos: []string{"repo"}},
+		{Name: "shared", Repos: []string{"repo"}},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulkerWithGroups(t, sh, repos, groups)
+	importFileName := tests.Path("import.yaml")
+	err := os.WriteFile(
+		importFileName, []byte(`
+version: 2
+data:
+    repos:
+        repo:
+            url: https://example.com/repo.git
+            tags: []
+        another:
+            url: https://example.com/another.git
+            tags: []
+    groups:
+        shared:
+            repos: [another, repo]
+        new:
+            repos: [another]
+`), os.ModePerm,
+	)
+	assert.NoError(t, err)
+
+	command := CreateImportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-s ours -f "+importFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testImportResult{
+					{Repo: "another", Result: "imported"},
+					{Repo: "group:new", Result: "imported"},
+					{Repo: "group:shared", Result: "conflict: repos"},
+				},
+			), output,
+		)
+	}
+
+	command = CreateImportCommand(sh)
+	_, output, err = tests.ExecuteCommand(command, "-s union -f "+importFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString([]testImportResult{{Repo: "group:shared", Result: "updated: repos"}}), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		group, err := sets.GetGroup("shared")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"another", "repo"}, group.Repos)
+		}
+		group, err = sets.GetGroup("local")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"repo"}, group.Repos)
+		}
+	}
+}
+
+func TestImport_GroupWithMissingRepo(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/repo.git", Tags: []string{}},
+	}
+	groups := []settings.Group{
+		{Name: "shared", Repos: []string{"repo"}},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulkerWithGroups(t, sh, repos, groups)
+	importFileName := tests.Path("import.yaml")
+	err := os.WriteFile(
+		importFileName, []byte(`
+version: 2
+data:
+    repos:
+        repo:
+            url: https://example.com/repo.git
+            tags: []
+    groups:
+        shared:
+            repos: [removed]
+        new:
+            repos: [removed, repo]
+        valid:
+            repos: [repo]
+`), os.ModePerm,
+	)
+	assert.NoError(t, err)
+
+	command := CreateImportCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-s theirs -f "+importFileName)
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testImportResult{
+					{Repo: "group:new", Result: "conflict: repos"},
+					{Repo: "group:shared", Result: "conflict: repos"},
+					{Repo: "group:valid", Result: "imported"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.False(t, sets.GroupExists("new"))
+		group, err := sets.GetGroup("shared")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"repo"}, group.Repos)
+		}
+	}
+}
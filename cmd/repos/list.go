@@ -39,7 +39,7 @@ func CreateListCommand(sh shell.Shell) *cobra.Command {
 		),
 	}
 
-	filter.AddCommandFlags(result)
+	filter.AddRepoFlags(result)
 
 	return result
 }
@@ -20,17 +20,11 @@ func CreateRemoveCommand(sh shell.Shell) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			settingsManager := settings.NewManager(config.ReadConfig(), sh)
 
-			sets, err := settingsManager.Read()
-			if err != nil {
-				return err
-			}
-
-			err = sets.RemoveRepo(flags.name)
-			if err != nil {
-				return err
-			}
-
-			err = settingsManager.Write(sets)
+			err := settingsManager.Update(
+				func(sets *settings.Settings) error {
+					return sets.RemoveRepo(flags.name)
+				},
+			)
 			if err != nil {
 				return err
 			}
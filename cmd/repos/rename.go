@@ -0,0 +1,120 @@
+package repos
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/output"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func CreateRenameCommand(sh shell.Shell) *cobra.Command {
+	var flags struct {
+		name    string
+		newName string
+		move    bool
+	}
+
+	var result = &cobra.Command{
+		Use:   "rename",
+		Short: "Renames a supported repository",
+		Long: `Renames a supported repository. All the groups that contain the repository are updated as well.
+The cloned repository directory is kept as is unless "--move" flag is set.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			type result struct {
+				PreviousName string
+				Directory    string
+			}
+
+			conf := config.ReadConfig()
+			settingsManager := settings.NewManager(conf, sh)
+
+			source := filepath.Join(conf.ReposDirectory, flags.name)
+			target := filepath.Join(conf.ReposDirectory, flags.newName)
+			moveDirectory := false
+			err := settingsManager.Update(
+				func(sets *settings.Settings) error {
+					err := sets.RenameRepo(flags.name, flags.newName)
+					if err != nil {
+						return err
+					}
+
+					if !flags.move {
+						return nil
+					}
+
+					// the directory is moved after the settings are written, but it's checked before,
+					// so that the settings are left untouched if it can't be moved
+					moveDirectory, err = canMoveRepoDirectory(source, target)
+					return err
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			directoryResult := "kept"
+			if flags.move {
+				directoryResult = "missing"
+			}
+			if moveDirectory {
+				err = os.Rename(source, target)
+				if err != nil {
+					return fmt.Errorf("repository is renamed, but failed to move its directory: %w", err)
+				}
+				directoryResult = "moved"
+			}
+
+			err = output.Write(
+				cmd.OutOrStdout(), "repo",
+				map[string]output.EntityInfo{
+					flags.newName: {Result: result{flags.name, directoryResult}},
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	result.Flags().StringVarP(&flags.name, "name", "n", "", "Current name of the repository")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "name")
+
+	result.Flags().StringVar(&flags.newName, "new-name", "", "New name of the repository")
+	utils.MarkFlagRequiredOrFail(result.Flags(), "new-name")
+
+	result.Flags().BoolVarP(
+		&flags.move, "move", "m", false, "Move the cloned repository directory according to the new name",
+	)
+
+	return result
+}
+
+// canMoveRepoDirectory checks that the cloned repository directory can be moved to the target one.
+// It returns false if the repository is not cloned
+func canMoveRepoDirectory(source string, target string) (bool, error) {
+	sourceExists, err := utils.Exists(source)
+	if err != nil {
+		return false, err
+	}
+	if !sourceExists {
+		return false, nil
+	}
+
+	targetExists, err := utils.Exists(target)
+	if err != nil {
+		return false, err
+	}
+	if targetExists {
+		return false, fmt.Errorf("directory %v already exists", target)
+	}
+
+	return true, nil
+}
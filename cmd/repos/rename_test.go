@@ -0,0 +1,151 @@
+package repos
+
+import (
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testRenameResult struct {
+	Repo         string `json:"repo"`
+	PreviousName string `json:"previousName"`
+	Directory    string `json:"directory"`
+}
+
+func TestRename(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git"},
+		{Name: "another", Url: "https://example.com/tenant/another.git"},
+	}
+	groups := []settings.Group{
+		{Name: "group", Repos: []string{"another", "repo"}},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulkerWithGroups(t, sh, repos, groups)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRenameCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "-n repo --new-name renamed")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "rename", c.Name())
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testRenameResult{
+					{Repo: "renamed", PreviousName: "repo", Directory: "kept"},
+				},
+			), output,
+		)
+	}
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.False(t, sets.RepoExists("repo"))
+		repo, err := sets.GetRepo("renamed")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/tenant/repo.git", repo.Url)
+		}
+		group, err := sets.GetGroup("group")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"another", "renamed"}, group.Repos)
+		}
+	}
+
+	exists, err := utils.Exists(tests.Path("repo"))
+	if assert.NoError(t, err) {
+		assert.True(t, exists)
+	}
+}
+
+func TestRename_Move(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRenameCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo --new-name renamed --move")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testRenameResult{
+					{Repo: "renamed", PreviousName: "repo", Directory: "moved"},
+				},
+			), output,
+		)
+	}
+
+	exists, err := utils.Exists(tests.Path("renamed"))
+	if assert.NoError(t, err) {
+		assert.True(t, exists)
+	}
+}
+
+func TestRename_MoveNotCloned(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateRenameCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-n repo --new-name renamed --move")
+	if assert.NoError(t, err) {
+		assert.JSONEq(
+			t, tests.ToJsonString(
+				[]testRenameResult{
+					{Repo: "renamed", PreviousName: "repo", Directory: "missing"},
+				},
+			), output,
+		)
+	}
+
+	exists, err := utils.Exists(tests.Path("renamed"))
+	if assert.NoError(t, err) {
+		assert.False(t, exists)
+	}
+}
+
+func TestRename_MoveTargetExists(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+	assert.NoError(t, os.Mkdir(tests.Path("repo"), os.ModePerm))
+	assert.NoError(t, os.Mkdir(tests.Path("renamed"), os.ModePerm))
+
+	command := CreateRenameCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-n repo --new-name renamed --move")
+	assert.Error(t, err)
+
+	manager := settings.NewManager(config.ReadConfig(), sh)
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.True(t, sets.RepoExists("repo"))
+		assert.False(t, sets.RepoExists("renamed"))
+	}
+}
+
+func TestRename_NameAlreadyExists(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/tenant/repo.git"},
+		{Name: "another", Url: "https://example.com/tenant/another.git"},
+	}
+	sh := tests.MockShellEmpty()
+	tests.PrepareBulker(t, sh, repos)
+
+	command := CreateRenameCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "-n repo --new-name another")
+	if assert.Error(t, err) {
+		assert.Equal(t, "repository already exists", err.Error())
+	}
+}
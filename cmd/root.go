@@ -64,6 +64,45 @@ func CreateRootCommand(applicationVersion string, sh shell.Shell) *cobra.Command
 	)
 	utils.BindFlag(result.PersistentFlags().Lookup("no-progress"), "noProgress")
 
+	var gitBackend = config.GitBackendExec
+	result.PersistentFlags().Var(
+		&gitBackend,
+		"git-backend", fmt.Sprintf(
+			"How to read repositories state. Available backends: %v - runs git binary, %v - reads repositories in process",
+			config.GitBackendExec, config.GitBackendNative,
+		),
+	)
+	utils.BindFlag(result.PersistentFlags().Lookup("git-backend"), "gitBackend")
+
+	result.PersistentFlags().String(
+		"worktree-layout", config.DefaultWorktreeLayout,
+		"Directory of git worktrees. {repo} and {worktree} are replaced with repository and worktree names. "+
+			"Relative directory is resolved against the repositories directory",
+	)
+	utils.BindFlag(result.PersistentFlags().Lookup("worktree-layout"), "worktreeLayout")
+
+	var hostingProvider = config.HostingProviderDetect
+	result.PersistentFlags().Var(
+		&hostingProvider,
+		"hosting-provider", fmt.Sprintf(
+			"Hosting API to manage repositories with. Available providers: %v, %v, %v. "+
+				"Detected by the repository URL if not set",
+			config.HostingProviderGithub, config.HostingProviderGitlab, config.HostingProviderBitbucket,
+		),
+	)
+	utils.BindFlag(result.PersistentFlags().Lookup("hosting-provider"), "hostingProvider")
+
+	result.PersistentFlags().String(
+		"hosting-api-url", "",
+		"Base URL of the hosting API, like https://gitlab.example.com/api/v4. The public API is used if not set",
+	)
+	utils.BindFlag(result.PersistentFlags().Lookup("hosting-api-url"), "hostingApiUrl")
+
+	result.PersistentFlags().String(
+		"hosting-token", "", "Token to authenticate hosting API requests. Prefer B_HOSTINGTOKEN environment variable",
+	)
+	utils.BindFlag(result.PersistentFlags().Lookup("hosting-token"), "hostingToken")
+
 	var output = config.TableOutputFormat
 	result.PersistentFlags().Var(
 		&output,
@@ -80,6 +119,9 @@ func CreateRootCommand(applicationVersion string, sh shell.Shell) *cobra.Command
 	result.AddCommand(CreateStatusCommand(sh))
 	result.AddCommand(CreateRunCommand(sh))
 	result.AddCommand(CreateOpenCommand(sh))
+	result.AddCommand(CreatePullRequestsCommand(sh))
+	result.AddCommand(CreateCiCommand(sh))
+	result.AddCommand(CreateHostingCommand(sh))
 	result.AddCommand(CreateFilesCommand(sh))
 	result.AddCommand(CreateConfigureCommand())
 	result.AddCommand(CreatePropertiesCommand(sh))
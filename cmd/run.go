@@ -3,13 +3,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"github.com/mih-kopylov/bulker/internal/gitops"
 	"github.com/mih-kopylov/bulker/internal/runner"
 	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/templates"
 	"github.com/spf13/cobra"
 )
 
 func CreateRunCommand(sh shell.Shell) *cobra.Command {
 	var filter = runner.Filter{}
+	var flags struct {
+		template bool
+	}
 
 	var result = &cobra.Command{
 		Use:   "run",
@@ -20,13 +25,31 @@ according to POSIX standard
 
 See https://pubs.opengroup.org/onlinepubs/9699919799/basedefs/V1_chap12.html#tag_12_02 :: Guideline 10
 
-Example: "bulker run -- mvn -B -q clean"`,
+Example: "bulker run -- mvn -B -q clean"
+
+The command arguments are passed as is. With --template parameter they are rendered for every repository.
+` + templates.Help + `
+
+Example: "bulker run --template -- git tag {{.Name}}-{{.Prop \"package.json\" \"$.version\"}}"`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: runner.NewCommandRunnerForExistingRepos(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				output, err := sh.RunCommand(runContext.Repo.Path, runContext.Args[0], runContext.Args[1:]...)
+				args := runContext.Args
+				if flags.template {
+					gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+					args = make([]string, len(runContext.Args))
+					for i, arg := range runContext.Args {
+						renderedArg, err := templates.Render(&gitService, runContext.Repo, arg)
+						if err != nil {
+							return nil, err
+						}
+						args[i] = renderedArg
+					}
+				}
+
+				output, err := sh.RunCommand(runContext.Repo.Path, args[0], args[1:]...)
 				if err != nil {
-					return nil, fmt.Errorf("failed to run %v: %v %w", runContext.Args, output, err)
+					return nil, fmt.Errorf("failed to run %v: %v %w", args, output, err)
 				}
 				return output, nil
 			},
@@ -35,5 +58,10 @@ Example: "bulker run -- mvn -B -q clean"`,
 
 	filter.AddCommandFlags(result)
 
+	result.Flags().BoolVar(
+		&flags.template, "template", false,
+		"Render the command arguments as templates. Arguments are passed as is otherwise, even if they contain '{{'",
+	)
+
 	return result
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testRunResult struct {
+	Repo   string `json:"repo"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func TestRun_ArgumentsPassedAsIs(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/repo"},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"docker inspect --format {{.Id}}": {Output: "id"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRunCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "-- docker inspect --format {{.Id}}")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testRunResult{{Repo: "repo", Result: "id"}}), output)
+}
+
+func TestRun_Template(t *testing.T) {
+	repos := []settings.Repo{
+		{Name: "repo", Url: "https://example.com/repo"},
+	}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git tag repo-release": {Output: "OK"},
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	err := os.Mkdir(tests.Path("repo"), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateRunCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "--template -- git tag {{.Name}}-release")
+	assert.NoError(t, err)
+	assert.JSONEq(t, tests.ToJsonString([]testRunResult{{Repo: "repo", Result: "OK"}}), output)
+}
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mih-kopylov/bulker/internal/gitops"
 	"github.com/mih-kopylov/bulker/internal/runner"
 	"github.com/mih-kopylov/bulker/internal/shell"
 	"github.com/spf13/cobra"
+	"strconv"
 	"strings"
 )
 
@@ -14,8 +16,15 @@ func CreateStatusCommand(sh shell.Shell) *cobra.Command {
 	var filter = runner.Filter{}
 
 	flags := struct {
-		status StatusFilter
-		ref    RefFilter
+		status     StatusFilter
+		ref        RefFilter
+		upstream   RefFilter
+		ahead      CountFilter
+		behind     CountFilter
+		staged     CountFilter
+		unstaged   CountFilter
+		untracked  CountFilter
+		submodules CountFilter
 	}{}
 
 	var result = &cobra.Command{
@@ -24,22 +33,50 @@ func CreateStatusCommand(sh shell.Shell) *cobra.Command {
 		Long: `Prints status of all registered repositories. Status value can be one of:
 * Clean - the repository successfully cloned, there are no uncommitted changes
 * Dirty - the repository successfully cloned, but there are uncommitted changes
-* Missing - the repository is not cloned yet`,
+* Missing - the repository is not cloned yet
+Along with the status, the current ref, its upstream branch, the number of commits ahead and behind the upstream,
+the number of staged, unstaged and untracked files, and the number of submodules that are not initialized
+or not checked out at the recorded commit are printed`,
 		RunE: runner.NewCommandRunner(
 			&filter, sh, func(ctx context.Context, runContext *runner.RunContext) (interface{}, error) {
-				gitService := gitops.NewGitService(sh)
-				repoStatus, ref, err := gitService.Status(runContext.Repo)
+				gitService := gitops.NewGitService(sh, runContext.Config.GitBackend)
+				repoStatus, err := gitService.Status(runContext.Repo)
 				if err != nil {
 					return nil, fmt.Errorf("failed to get status: %w", err)
 				}
 
 				type result struct {
-					Status string
-					Ref    string
+					Status     string
+					Ref        string
+					Upstream   string
+					Ahead      int
+					Behind     int
+					Staged     int
+					Unstaged   int
+					Untracked  int
+					Submodules int
 				}
 
-				if flags.status.Matches(repoStatus.String()) && flags.ref.Matches(ref) {
-					return result{repoStatus.String(), ref}, nil
+				if flags.status.Matches(repoStatus.Status.String()) &&
+					flags.ref.Matches(repoStatus.Ref) &&
+					flags.upstream.Matches(repoStatus.Upstream) &&
+					flags.ahead.Matches(repoStatus.Ahead) &&
+					flags.behind.Matches(repoStatus.Behind) &&
+					flags.staged.Matches(repoStatus.Staged) &&
+					flags.unstaged.Matches(repoStatus.Unstaged) &&
+					flags.untracked.Matches(repoStatus.Untracked) &&
+					flags.submodules.Matches(repoStatus.Submodules) {
+					return result{
+						Status:     repoStatus.Status.String(),
+						Ref:        repoStatus.Ref,
+						Upstream:   repoStatus.Upstream,
+						Ahead:      repoStatus.Ahead,
+						Behind:     repoStatus.Behind,
+						Staged:     repoStatus.Staged,
+						Unstaged:   repoStatus.Unstaged,
+						Untracked:  repoStatus.Untracked,
+						Submodules: repoStatus.Submodules,
+					}, nil
 				}
 				return nil, nil
 			},
@@ -64,6 +101,43 @@ Examples:
 "bulker status --ref master" - will keep only repositories with "master" ref
 "bulker status --ref !master" - will keep repositories with any ref except "master"`,
 	)
+	result.Flags().Var(
+		&flags.upstream, "upstream",
+		`Keep repositories with specified upstream branch.
+Examples: 
+"bulker status --upstream origin/master" - will keep only repositories tracking "origin/master"
+"bulker status --upstream !origin/master" - will keep repositories tracking any branch except "origin/master"`,
+	)
+	result.Flags().Var(
+		&flags.ahead, "ahead",
+		`Keep repositories with specified number of commits ahead of the upstream branch.
+Examples: 
+"bulker status --ahead !0" - will keep only repositories with unpushed commits
+"bulker status --ahead >5" - will keep repositories with more than 5 unpushed commits`,
+	)
+	result.Flags().Var(
+		&flags.behind, "behind",
+		`Keep repositories with specified number of commits behind the upstream branch.
+Examples: 
+"bulker status --behind !0" - will keep only repositories with commits to pull
+"bulker status --behind 0" - will keep repositories that are up-to-date with the upstream branch`,
+	)
+	result.Flags().Var(
+		&flags.staged, "staged", `Keep repositories with specified number of staged files.
+Supports the same syntax as "--ahead"`,
+	)
+	result.Flags().Var(
+		&flags.unstaged, "unstaged", `Keep repositories with specified number of unstaged files.
+Supports the same syntax as "--ahead"`,
+	)
+	result.Flags().Var(
+		&flags.untracked, "untracked", `Keep repositories with specified number of untracked files.
+Supports the same syntax as "--ahead"`,
+	)
+	result.Flags().Var(
+		&flags.submodules, "submodules", `Keep repositories with specified number of drifted submodules.
+Supports the same syntax as "--ahead"`,
+	)
 
 	return result
 }
@@ -135,3 +209,58 @@ func (f *RefFilter) Matches(ref string) bool {
 
 	return false
 }
+
+// CountFilter implements Value in spf13/pflag for custom flag type.
+// Supports exact "5", negated "!5", greater than ">5" and less than "<5" values
+type CountFilter struct {
+	operator string
+	value    int
+}
+
+func (f *CountFilter) String() string {
+	if f.operator == "" {
+		return ""
+	}
+	if f.operator == "=" {
+		return strconv.Itoa(f.value)
+	}
+
+	return f.operator + strconv.Itoa(f.value)
+}
+
+func (f *CountFilter) Set(v string) error {
+	operator := "="
+	value := v
+	if strings.HasPrefix(v, "!") || strings.HasPrefix(v, ">") || strings.HasPrefix(v, "<") {
+		operator = v[:1]
+		value = v[1:]
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.New("must be a number optionally prefixed with '!', '>' or '<'")
+	}
+
+	f.operator = operator
+	f.value = count
+	return nil
+}
+
+func (f *CountFilter) Type() string {
+	return "CountFilter"
+}
+
+func (f *CountFilter) Matches(count int) bool {
+	switch f.operator {
+	case "":
+		return true
+	case "!":
+		return count != f.value
+	case ">":
+		return count > f.value
+	case "<":
+		return count < f.value
+	default:
+		return count == f.value
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/settings"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+type testStatusResult struct {
+	Repo       string `json:"repo"`
+	Error      string `json:"error,omitempty"`
+	Status     string `json:"status"`
+	Ref        string `json:"ref"`
+	Upstream   string `json:"upstream"`
+	Ahead      int    `json:"ahead"`
+	Behind     int    `json:"behind"`
+	Staged     int    `json:"staged"`
+	Unstaged   int    `json:"unstaged"`
+	Untracked  int    `json:"untracked"`
+	Submodules int    `json:"submodules"`
+}
+
+func prepareStatusBulker(t *testing.T) shell.Shell {
+	repos := []settings.Repo{
+		{Name: "pushed", Url: "https://example.com/pushed"},
+		{Name: "unpushed", Url: "https://example.com/unpushed"},
+		{Name: "dirty", Url: "https://example.com/dirty"},
+	}
+	statusOutputs := map[string]string{
+		"pushed": `# branch.oid 0123456789abcdef
+# branch.head master
+# branch.upstream origin/master
+# branch.ab +0 -0`,
+		"unpushed": `# branch.oid 0123456789abcdef
+# branch.head feature
+# branch.upstream origin/feature
+# branch.ab +2 -1`,
+		"dirty": `# branch.oid 0123456789abcdef
+# branch.head master
+1 M. N... 100644 100644 100644 0123 4567 staged.txt
+1 .M N... 100644 100644 100644 0123 4567 unstaged.txt
+? untracked.txt`,
+	}
+	sh := tests.MockShellFunc(
+		func(repoName string, command string, arguments []string) (string, error) {
+			commandLine := tests.ShellCommandToString(command, arguments)
+			if commandLine == "git submodule status" {
+				return " 0123456789abcdef libs/common (heads/main)\n+0123456789abcdef libs/outdated (heads/main)\n", nil
+			}
+			if commandLine != "git status --porcelain=v2 --branch" {
+				return "", fmt.Errorf("shell not mocked: %v %v", repoName, commandLine)
+			}
+			return statusOutputs[repoName], nil
+		},
+	)
+	tests.PrepareBulker(t, sh, repos)
+	for _, repo := range repos {
+		err := os.Mkdir(tests.Path(repo.Name), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	return sh
+}
+
+var (
+	testStatusDirty = testStatusResult{
+		Repo: "dirty", Status: "dirty", Ref: "master", Staged: 1, Unstaged: 1, Untracked: 1,
+	}
+	testStatusPushed = testStatusResult{
+		Repo: "pushed", Status: "clean", Ref: "master", Upstream: "origin/master",
+	}
+	testStatusUnpushed = testStatusResult{
+		Repo: "unpushed", Status: "clean", Ref: "feature", Upstream: "origin/feature", Ahead: 2, Behind: 1,
+	}
+)
+
+func TestStatus(t *testing.T) {
+	sh := prepareStatusBulker(t)
+
+	command := CreateStatusCommand(sh)
+	c, output, err := tests.ExecuteCommand(command, "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "status", c.Name())
+		assert.JSONEq(
+			t, tests.ToJsonString([]testStatusResult{testStatusDirty, testStatusPushed, testStatusUnpushed}), output,
+		)
+	}
+}
+
+func TestStatus_Filters(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     string
+		expected []testStatusResult
+	}{
+		{name: "ahead", args: "--ahead !0", expected: []testStatusResult{testStatusUnpushed}},
+		{name: "behind", args: "--behind >0", expected: []testStatusResult{testStatusUnpushed}},
+		{name: "staged", args: "--staged 1", expected: []testStatusResult{testStatusDirty}},
+		{
+			name:     "unstaged",
+			args:     "--unstaged <1",
+			expected: []testStatusResult{testStatusPushed, testStatusUnpushed},
+		},
+		{name: "untracked", args: "--untracked !0", expected: []testStatusResult{testStatusDirty}},
+		{name: "upstream", args: "--upstream origin/master", expected: []testStatusResult{testStatusPushed}},
+		{
+			name:     "combined",
+			args:     "--ref master --ahead 0",
+			expected: []testStatusResult{testStatusDirty, testStatusPushed},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				sh := prepareStatusBulker(t)
+
+				command := CreateStatusCommand(sh)
+				_, output, err := tests.ExecuteCommand(command, tt.args)
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, tests.ToJsonString(tt.expected), output)
+				}
+			},
+		)
+	}
+}
+
+func TestStatus_Submodules(t *testing.T) {
+	sh := prepareStatusBulker(t)
+	err := os.WriteFile(tests.Path("pushed", ".gitmodules"), []byte(""), os.ModePerm)
+	assert.NoError(t, err)
+
+	command := CreateStatusCommand(sh)
+	_, output, err := tests.ExecuteCommand(command, "--submodules !0")
+	if assert.NoError(t, err) {
+		expected := testStatusPushed
+		expected.Submodules = 1
+		assert.JSONEq(t, tests.ToJsonString([]testStatusResult{expected}), output)
+	}
+}
+
+func TestStatus_WrongCountFilter(t *testing.T) {
+	sh := prepareStatusBulker(t)
+
+	command := CreateStatusCommand(sh)
+	_, _, err := tests.ExecuteCommand(command, "--ahead many")
+	if assert.Error(t, err) {
+		assert.Equal(
+			t, `invalid argument "many" for "--ahead" flag: must be a number optionally prefixed with '!', '>' or '<'`,
+			err.Error(),
+		)
+	}
+}
@@ -7,17 +7,46 @@ import (
 	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Config struct {
-	Debug            bool         `mapstructure:"debug"`
-	SettingsFileName string       `mapstructure:"settingsFileName"`
-	ReposDirectory   string       `mapstructure:"reposDirectory"`
-	RunMode          RunMode      `mapstructure:"runMode"`
-	MaxWorkers       int          `mapstructure:"maxWorkers"`
-	NoProgress       bool         `mapstructure:"noProgress"`
-	Output           OutputFormat `mapstructure:"output"`
-	GitMode          GitMode      `mapstructure:"gitMode"`
+	Debug            bool            `mapstructure:"debug"`
+	SettingsFileName string          `mapstructure:"settingsFileName"`
+	ReposDirectory   string          `mapstructure:"reposDirectory"`
+	RunMode          RunMode         `mapstructure:"runMode"`
+	MaxWorkers       int             `mapstructure:"maxWorkers"`
+	NoProgress       bool            `mapstructure:"noProgress"`
+	Output           OutputFormat    `mapstructure:"output"`
+	GitMode          GitMode         `mapstructure:"gitMode"`
+	GitBackend       GitBackend      `mapstructure:"gitBackend"`
+	WorktreeLayout   string          `mapstructure:"worktreeLayout"`
+	HostingProvider  HostingProvider `mapstructure:"hostingProvider"`
+	HostingApiUrl    string          `mapstructure:"hostingApiUrl"`
+	HostingToken     string          `mapstructure:"hostingToken"`
+}
+
+const (
+	// DefaultWorktreeLayout keeps worktrees in a hidden directory, so that they are not taken for repositories
+	DefaultWorktreeLayout   = ".worktrees/{repo}/{worktree}"
+	worktreeRepoPlaceholder = "{repo}"
+	worktreeNamePlaceholder = "{worktree}"
+)
+
+// WorktreePath returns a directory of the `worktree` of the `repo` according to the worktree layout.
+// A relative layout is resolved against the repositories directory
+func (c *Config) WorktreePath(repo string, worktree string) string {
+	layout := c.WorktreeLayout
+	if layout == "" {
+		layout = DefaultWorktreeLayout
+	}
+
+	path := strings.NewReplacer(worktreeRepoPlaceholder, repo, worktreeNamePlaceholder, worktree).Replace(layout)
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(c.ReposDirectory, path)
 }
 
 func ReadConfig() *Config {
@@ -31,12 +60,15 @@ func ReadConfig() *Config {
 	return config
 }
 
+// WriteConfig saves the configuration to the file readable by the current user only.
+// The hosting token is not saved, it's expected to come from the environment
 func WriteConfig(conf *Config, fileName string) error {
 	var confMap map[string]any
 	err := mapstructure.Decode(conf, &confMap)
 	if err != nil {
 		return err
 	}
+	delete(confMap, "hostingToken")
 
 	confBytes, err := yaml.Marshal(confMap)
 	if err != nil {
@@ -48,7 +80,13 @@ func WriteConfig(conf *Config, fileName string) error {
 		return err
 	}
 
-	err = os.WriteFile(fileName, confBytes, os.ModePerm)
+	err = os.WriteFile(fileName, confBytes, 0600)
+	if err != nil {
+		return err
+	}
+
+	// files written by older versions are readable by everyone
+	err = os.Chmod(fileName, 0600)
 	if err != nil {
 		return err
 	}
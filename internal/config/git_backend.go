@@ -0,0 +1,31 @@
+package config
+
+import "fmt"
+
+// GitBackend implements Value in spf13/pflag for custom flag type
+type GitBackend string
+
+func (b *GitBackend) String() string {
+	return string(*b)
+}
+
+func (b *GitBackend) Set(v string) error {
+	switch v {
+	case string(GitBackendExec), string(GitBackendNative):
+		*b = GitBackend(v)
+		return nil
+	default:
+		return fmt.Errorf("must be either '%s' or '%s'", GitBackendExec, GitBackendNative)
+	}
+}
+
+func (b *GitBackend) Type() string {
+	return "GitBackend"
+}
+
+const (
+	// GitBackendExec runs the git binary
+	GitBackendExec GitBackend = "exec"
+	// GitBackendNative reads repositories in process. Operations that change repositories still run the git binary
+	GitBackendNative GitBackend = "native"
+)
@@ -0,0 +1,36 @@
+package config
+
+import "fmt"
+
+// HostingProvider implements Value in spf13/pflag for custom flag type
+type HostingProvider string
+
+func (p *HostingProvider) String() string {
+	return string(*p)
+}
+
+func (p *HostingProvider) Set(v string) error {
+	switch v {
+	case string(HostingProviderDetect), string(HostingProviderGithub), string(HostingProviderGitlab),
+		string(HostingProviderBitbucket):
+		*p = HostingProvider(v)
+		return nil
+	default:
+		return fmt.Errorf(
+			"must be one of '%s' '%s' '%s' '%s'", HostingProviderDetect, HostingProviderGithub, HostingProviderGitlab,
+			HostingProviderBitbucket,
+		)
+	}
+}
+
+func (p *HostingProvider) Type() string {
+	return "HostingProvider"
+}
+
+const (
+	// HostingProviderDetect chooses the provider by the host of the repository URL
+	HostingProviderDetect    HostingProvider = ""
+	HostingProviderGithub    HostingProvider = "github"
+	HostingProviderGitlab    HostingProvider = "gitlab"
+	HostingProviderBitbucket HostingProvider = "bitbucket"
+)
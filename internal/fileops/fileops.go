@@ -16,6 +16,7 @@ var (
 	ErrSourceNotFound      = errors.New("source not found")
 	ErrTargetAlreadyExists = errors.New("target already exists")
 	ErrRepositoryNotCloned = errors.New("repository not cloned")
+	ErrWorktreeNotFound    = errors.New("worktree not found")
 )
 
 func Copy(repo *model.Repo, source string, target string, force bool) (string, string, error) {
@@ -115,6 +116,11 @@ func CheckRepoExists(repo *model.Repo) error {
 	return nil
 }
 
+// IsGitRepo checks whether the repository directory is a git working copy
+func IsGitRepo(repo *model.Repo) (bool, error) {
+	return utils.Exists(filepath.Join(repo.Path, ".git"))
+}
+
 type FileSearchResult struct {
 	FileName string
 	Matches  []string
@@ -12,6 +12,7 @@ const (
 	RefPrefix       = "refs/"
 	RefHeadPrefix   = RefPrefix + "heads/"
 	RefRemotePrefix = RefPrefix + "remotes/"
+	RefTagPrefix    = RefPrefix + "tags/"
 	Head            = "HEAD"
 )
 
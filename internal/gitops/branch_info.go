@@ -0,0 +1,19 @@
+package gitops
+
+// BranchInfo describes the last commit of a branch, its upstream and how it relates to another ref
+type BranchInfo struct {
+	Branch Branch
+	// LastCommit is the commit the branch points to
+	LastCommit Commit
+	// Upstream is the branch the local one tracks. Empty for remote branches and the ones without upstream
+	Upstream string
+	// Ahead is the number of branch commits that are not merged to the ref
+	Ahead int
+	// Behind is the number of ref commits that are not merged to the branch
+	Behind int
+}
+
+// Merged reports whether all the branch commits are merged to the ref
+func (b *BranchInfo) Merged() bool {
+	return b.Ahead == 0
+}
@@ -2,6 +2,7 @@ package gitops
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -11,6 +12,16 @@ type Commit struct {
 	Author     CommitUser
 	CommitDate time.Time
 	Committer  CommitUser
+	Subject    string
+	Body       string
+}
+
+// ShortId returns the commit id abbreviated the way git does by default
+func (c *Commit) ShortId() string {
+	if len(c.Id) <= shortCommitIdLength {
+		return c.Id
+	}
+	return c.Id[:shortCommitIdLength]
 }
 
 type CommitUser struct {
@@ -21,3 +32,74 @@ type CommitUser struct {
 func (u *CommitUser) String() string {
 	return fmt.Sprintf("%v <%v>", u.Name, u.Email)
 }
+
+type CommitOptions struct {
+	// Pattern of the files to commit. All files are committed by default
+	Pattern string
+	// Message of the commit. The message of the amended commit is kept if it's empty
+	Message string
+	// StripComments removes lines starting with "#" from the message, as git does for commit templates
+	StripComments bool
+	// Author overrides the commit author, like "Name <email>"
+	Author string
+	// CoAuthors are added to the message as "Co-authored-by" trailers
+	CoAuthors []string
+	// SignOff adds "Signed-off-by" trailer of the committer
+	SignOff bool
+	// Amend replaces the last commit instead of creating a new one
+	Amend bool
+}
+
+// LogOptions limits commits returned by GitBackend.Log
+type LogOptions struct {
+	// Ref is the reference to get commits from
+	Ref string
+	// Exclude is the reference, commits reachable from which are not returned. Ignored if empty
+	Exclude string
+	// Since skips commits committed before the time. Ignored if zero
+	Since time.Time
+	// Until skips commits committed after the time. Ignored if zero
+	Until time.Time
+	// Authors are regexp patterns that the "Name <email>" of the commit author is matched with.
+	// A commit is returned if any of them matches
+	Authors []string
+	// Paths skip commits that don't change files in any of the paths
+	Paths []string
+	// MaxCount limits the number of returned commits. Ignored if zero
+	MaxCount int
+}
+
+// ParseRefRange splits a range like `v1.0.0..main` into the reference to get commits from and the one to exclude.
+// Omitted sides of the range, as well as an empty range, mean HEAD.
+// Symmetric difference ranges like `main...feature` are not supported
+func ParseRefRange(refRange string) (string, string, error) {
+	if strings.Contains(refRange, "...") {
+		return "", "", fmt.Errorf("three-dot range %v is not supported, use two-dot range instead", refRange)
+	}
+
+	exclude, ref, isRange := strings.Cut(refRange, "..")
+	if !isRange {
+		ref = exclude
+		exclude = ""
+	} else if exclude == "" {
+		exclude = Head
+	}
+
+	if ref == "" {
+		ref = Head
+	}
+
+	return ref, exclude, nil
+}
+
+// parseCommitMessage splits the message the same way git does: the first paragraph joined into a line is the subject,
+// the rest is the body
+func parseCommitMessage(message string) (string, string) {
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n\n")
+	subjectLines := strings.Split(subject, "\n")
+	for i, line := range subjectLines {
+		subjectLines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.Join(subjectLines, " "), strings.TrimSpace(body)
+}
@@ -0,0 +1,14 @@
+package gitops
+
+type CommitResult string
+
+const (
+	CommitCommitted CommitResult = "committed"
+	CommitAmended   CommitResult = "amended"
+	CommitNothing   CommitResult = "nothing to commit"
+	CommitError     CommitResult = "error"
+)
+
+func (r *CommitResult) String() string {
+	return string(*r)
+}
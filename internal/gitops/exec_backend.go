@@ -0,0 +1,321 @@
+package gitops
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+const (
+	commitFieldSeparator  = "\x1f"
+	commitRecordSeparator = "\x1e"
+	// commitFormat prints commit fields separated with the unit separator and commits with the record separator,
+	// so that the output doesn't depend on the locale and the user configuration
+	commitFormat = "%H%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%s%x1f%b%x1e"
+	// branchCommitFormat prints the branch name followed by the same fields as commitFormat does
+	branchCommitFormat = "%(refname)%1f%(objectname)%1f%(authorname)%1f%(authoremail:trim)%1f" +
+		"%(authordate:iso-strict)%1f%(committername)%1f%(committeremail:trim)%1f%(committerdate:iso-strict)%1f" +
+		"%(subject)%1f%(body)%1e"
+)
+
+// execBackend reads repositories running the git binary and parsing its machine-readable output
+type execBackend struct {
+	sh shell.Shell
+}
+
+func (b *execBackend) Status(repo *model.Repo) (RepoStatus, error) {
+	statusOutput, err := b.sh.RunCommand(repo.Path, "git", "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return RepoStatus{}, fmt.Errorf("failed to get git status: %v, %w", statusOutput, err)
+	}
+
+	return b.parseStatus(statusOutput)
+}
+
+func (b *execBackend) Branches(repo *model.Repo) ([]Branch, error) {
+	return b.forEachBranch(repo)
+}
+
+func (b *execBackend) MergedBranches(repo *model.Repo, ref string) ([]Branch, error) {
+	return b.forEachBranch(repo, "--merged", ref)
+}
+
+func (b *execBackend) UnmergedBranches(repo *model.Repo, ref string) ([]Branch, error) {
+	return b.forEachBranch(repo, "--no-merged", ref)
+}
+
+func (b *execBackend) Tags(repo *model.Repo) ([]Tag, error) {
+	output, err := b.sh.RunCommand(repo.Path, "git", "for-each-ref", "--format=%(refname)", RefTagPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v, %w", output, err)
+	}
+
+	var result []Tag
+	for _, line := range strings.Fields(output) {
+		tag, err := parseTag(line, "")
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *tag)
+	}
+
+	return result, nil
+}
+
+func (b *execBackend) Upstreams(repo *model.Repo) (map[string]string, error) {
+	output, err := b.sh.RunCommand(
+		repo.Path, "git", "for-each-ref", "--format=%(refname)%1f%(upstream:short)", RefHeadPrefix,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upstream branches: %v, %w", output, err)
+	}
+
+	result := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		refName, upstream, _ := strings.Cut(strings.TrimSpace(line), commitFieldSeparator)
+		if upstream == "" {
+			continue
+		}
+		result[strings.TrimPrefix(refName, RefHeadPrefix)] = upstream
+	}
+
+	return result, nil
+}
+
+func (b *execBackend) BranchCommits(repo *model.Repo) (map[string]Commit, error) {
+	output, err := b.sh.RunCommand(
+		repo.Path, "git", "for-each-ref", "--format="+branchCommitFormat, RefHeadPrefix, RefRemotePrefix,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch commits: %v, %w", output, err)
+	}
+
+	result := map[string]Commit{}
+	for _, record := range strings.Split(output, commitRecordSeparator) {
+		refName, commitRecord, _ := strings.Cut(strings.TrimSpace(record), commitFieldSeparator)
+		// remote HEAD is a symbolic reference to a branch, not a branch itself
+		if refName == "" || strings.HasSuffix(refName, "/"+Head) {
+			continue
+		}
+
+		commits, err := b.parseCommits(commitRecord)
+		if err != nil {
+			return nil, err
+		}
+		result[refName] = commits[0]
+	}
+
+	return result, nil
+}
+
+func (b *execBackend) AheadBehind(repo *model.Repo, branch string, ref string) (int, int, error) {
+	output, err := b.sh.RunCommand(
+		repo.Path, "git", "rev-list", "--left-right", "--count", fmt.Sprintf("%v...%v", branch, ref),
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count commits: %v, %w", output, err)
+	}
+
+	var ahead, behind int
+	_, err = fmt.Sscanf(strings.TrimSpace(output), "%d\t%d", &ahead, &behind)
+	if err != nil {
+		return 0, 0, fmt.Errorf("can't parse ahead and behind counts: %v, %w", output, err)
+	}
+
+	return ahead, behind, nil
+}
+
+func (b *execBackend) Submodules(repo *model.Repo) ([]Submodule, error) {
+	// a repository without submodules is the common case, so git is not run for it at all
+	exists, err := hasSubmodules(repo)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	output, err := b.sh.RunCommand(repo.Path, "git", "submodule", "status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submodules: %v, %w", output, err)
+	}
+
+	return parseSubmodules(output)
+}
+
+func (b *execBackend) Log(repo *model.Repo, options LogOptions) ([]Commit, error) {
+	arguments := []string{"--no-pager", "log", "--format=" + commitFormat}
+	if options.MaxCount > 0 {
+		arguments = append(arguments, fmt.Sprintf("--max-count=%v", options.MaxCount))
+	}
+	if !options.Since.IsZero() {
+		arguments = append(arguments, "--since="+options.Since.Format(time.RFC3339))
+	}
+	if !options.Until.IsZero() {
+		arguments = append(arguments, "--until="+options.Until.Format(time.RFC3339))
+	}
+	if len(options.Authors) > 0 {
+		arguments = append(arguments, "--extended-regexp")
+		for _, author := range options.Authors {
+			arguments = append(arguments, "--author="+author)
+		}
+	}
+	arguments = append(arguments, options.Ref)
+	if options.Exclude != "" {
+		arguments = append(arguments, "--not", options.Exclude)
+	}
+	if len(options.Paths) > 0 {
+		arguments = append(arguments, "--")
+		arguments = append(arguments, options.Paths...)
+	}
+
+	output, err := b.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %v, %w", output, err)
+	}
+
+	return b.parseCommits(output)
+}
+
+func (b *execBackend) forEachBranch(repo *model.Repo, filterArguments ...string) ([]Branch, error) {
+	arguments := append([]string{"for-each-ref", "--format=%(refname)"}, filterArguments...)
+	arguments = append(arguments, RefHeadPrefix, RefRemotePrefix)
+	output, err := b.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %v, %w", output, err)
+	}
+
+	return b.parseBranches(output)
+}
+
+func (b *execBackend) parseBranches(consoleOutputString string) ([]Branch, error) {
+	var result []Branch
+	outputBranchNames := strings.FieldsFunc(
+		consoleOutputString, func(r rune) bool {
+			return r == '\n'
+		},
+	)
+	for _, outputBranchName := range outputBranchNames {
+		branch, err := parseBranch(outputBranchName)
+		if err != nil {
+			if errors.Is(err, ErrDetachedHead) {
+				continue
+			}
+			return nil, err
+		}
+
+		if branch.Name == Head {
+			continue
+		}
+		result = append(result, *branch)
+	}
+
+	return result, nil
+}
+
+// parseCommits parses `git log` output printed with commitFormat
+func (b *execBackend) parseCommits(consoleOutputString string) ([]Commit, error) {
+	var commits []Commit
+	for _, record := range strings.Split(consoleOutputString, commitRecordSeparator) {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, commitFieldSeparator)
+		if len(fields) != 9 {
+			return nil, fmt.Errorf("failed to parse commit from console output: %v", record)
+		}
+
+		authorDateTime, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse author date from commit")
+		}
+
+		commitDateTime, err := time.Parse(time.RFC3339, fields[6])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse commit date from commit")
+		}
+
+		commit := Commit{
+			Id:         fields[0],
+			AuthorDate: authorDateTime,
+			Author: CommitUser{
+				Name:  fields[1],
+				Email: fields[2],
+			},
+			CommitDate: commitDateTime,
+			Committer: CommitUser{
+				Name:  fields[4],
+				Email: fields[5],
+			},
+			Subject: fields[7],
+			Body:    strings.TrimSpace(fields[8]),
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// parseStatus parses `git status --porcelain=v2 --branch` output
+func (b *execBackend) parseStatus(statusOutput string) (RepoStatus, error) {
+	result := RepoStatus{Status: StatusClean}
+	var oid string
+	var head string
+
+	lines := strings.FieldsFunc(
+		statusOutput, func(r rune) bool {
+			return r == '\n'
+		},
+	)
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "# branch.oid "):
+			oid = strings.TrimPrefix(line, "# branch.oid ")
+		case strings.HasPrefix(line, "# branch.head "):
+			head = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.upstream "):
+			result.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			_, err := fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &result.Ahead, &result.Behind)
+			if err != nil {
+				return RepoStatus{}, fmt.Errorf("can't parse ahead and behind counts: %v, %w", line, err)
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			if len(line) < 4 {
+				return RepoStatus{}, fmt.Errorf("can't parse changed entry: %v", line)
+			}
+			if line[2] != '.' {
+				result.Staged++
+			}
+			if line[3] != '.' {
+				result.Unstaged++
+			}
+		case strings.HasPrefix(line, "u "):
+			result.Unstaged++
+		case strings.HasPrefix(line, "? "):
+			result.Untracked++
+		}
+	}
+
+	switch head {
+	case "":
+		return RepoStatus{}, fmt.Errorf("can't parse status result for head reference: %v", statusOutput)
+	case "(detached)":
+		result.Detached = true
+		result.Ref = oid
+		if len(oid) > shortCommitIdLength {
+			result.Ref = oid[:shortCommitIdLength]
+		}
+	default:
+		result.Ref = head
+	}
+
+	if result.Staged > 0 || result.Unstaged > 0 || result.Untracked > 0 {
+		result.Status = StatusDirty
+	}
+
+	return result, nil
+}
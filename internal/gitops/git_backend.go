@@ -0,0 +1,42 @@
+package gitops
+
+import (
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/shell"
+)
+
+// GitBackend reads the state of cloned repositories.
+// Operations that change repositories always run the git binary
+type GitBackend interface {
+	// Status returns the working tree status of the repository and how its branch tracks the upstream one
+	Status(repo *model.Repo) (RepoStatus, error)
+	// Branches returns local and remote branches
+	Branches(repo *model.Repo) ([]Branch, error)
+	// MergedBranches returns local and remote branches that are merged to `ref`
+	MergedBranches(repo *model.Repo, ref string) ([]Branch, error)
+	// UnmergedBranches returns local and remote branches that are not merged to `ref`
+	UnmergedBranches(repo *model.Repo, ref string) ([]Branch, error)
+	// Upstreams returns names of the branches that local branches track, by the local branch names.
+	// Branches without an upstream are omitted
+	Upstreams(repo *model.Repo) (map[string]string, error)
+	// BranchCommits returns the commits local and remote branches point to, by the full branch names
+	BranchCommits(repo *model.Repo) (map[string]Commit, error)
+	// AheadBehind returns the number of commits reachable from `branch` only and from `ref` only
+	AheadBehind(repo *model.Repo, branch string, ref string) (int, int, error)
+	// Tags returns local tags
+	Tags(repo *model.Repo) ([]Tag, error)
+	// Submodules returns the submodules declared in the repository along with their state
+	Submodules(repo *model.Repo) ([]Submodule, error)
+	// Log returns commits matching the options, the most recently committed first
+	Log(repo *model.Repo, options LogOptions) ([]Commit, error)
+}
+
+// NewGitBackend creates a backend of the type. The git binary is used by default
+func NewGitBackend(sh shell.Shell, backend config.GitBackend) GitBackend {
+	if backend == config.GitBackendNative {
+		return &nativeBackend{}
+	}
+
+	return &execBackend{sh: sh}
+}
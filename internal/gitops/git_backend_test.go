@@ -0,0 +1,376 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBackends = []config.GitBackend{config.GitBackendExec, config.GitBackendNative}
+
+// prepareBackendRepo clones a repository with "main" branch tracking the remote one,
+// "feature" branch merged to "main" and "work" branch with a commit that is not merged
+func prepareBackendRepo(t *testing.T, sh shell.Shell) *model.Repo {
+	tests.GitEnvironments[0].Set(t)
+	remoteDir := prepareRemoteRepo(t, sh)
+	repo := &model.Repo{Name: "repo", Url: remoteDir, Path: filepath.Join(t.TempDir(), "repo")}
+	runGit(t, sh, "", "clone", remoteDir, repo.Path)
+
+	runGit(t, sh, repo.Path, "branch", "feature")
+	runGit(t, sh, repo.Path, "checkout", "-b", "work")
+	commitFile(t, sh, repo, "work.txt")
+	runGit(t, sh, repo.Path, "checkout", "main")
+
+	return repo
+}
+
+func commitFile(t *testing.T, sh shell.Shell, repo *model.Repo, fileName string) {
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Path, fileName), []byte(fileName), os.ModePerm))
+	runGit(t, sh, repo.Path, "add", fileName)
+	runGit(t, sh, repo.Path, "commit", "-m", "add "+fileName)
+}
+
+func revParse(t *testing.T, sh shell.Shell, repo *model.Repo, ref string) string {
+	output, err := sh.RunCommand(repo.Path, "git", "rev-parse", ref)
+	require.NoError(t, err, output)
+	return strings.TrimSpace(output)
+}
+
+func TestGitBackend_Status(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+
+				status, err := backend.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(t, RepoStatus{Status: StatusClean, Ref: "main", Upstream: "origin/main"}, status)
+
+				commitFile(t, sh, repo, "ahead.txt")
+				require.NoError(t, os.WriteFile(filepath.Join(repo.Path, "file.txt"), []byte("changed"), os.ModePerm))
+				require.NoError(t, os.WriteFile(filepath.Join(repo.Path, "staged.txt"), []byte("staged"), os.ModePerm))
+				runGit(t, sh, repo.Path, "add", "staged.txt")
+				require.NoError(t, os.WriteFile(filepath.Join(repo.Path, "new.txt"), []byte("new"), os.ModePerm))
+
+				status, err = backend.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(
+					t, RepoStatus{
+						Status:    StatusDirty,
+						Ref:       "main",
+						Upstream:  "origin/main",
+						Ahead:     1,
+						Staged:    1,
+						Unstaged:  1,
+						Untracked: 1,
+					}, status,
+				)
+
+				runGit(t, sh, repo.Path, "reset", "--hard", "HEAD")
+				runGit(t, sh, repo.Path, "push", "origin", "main")
+				runGit(t, sh, repo.Path, "reset", "--hard", "HEAD~1")
+				status, err = backend.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(
+					t, RepoStatus{
+						Status: StatusDirty, Ref: "main", Upstream: "origin/main", Behind: 1, Untracked: 1,
+					}, status,
+				)
+
+				runGit(t, sh, repo.Path, "checkout", "work")
+				status, err = backend.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(t, RepoStatus{Status: StatusDirty, Ref: "work", Untracked: 1}, status)
+
+				runGit(t, sh, repo.Path, "checkout", "--detach", "work")
+				status, err = backend.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(
+					t, RepoStatus{
+						Status: StatusDirty, Ref: revParse(t, sh, repo, "work")[:shortCommitIdLength], Detached: true,
+						Untracked: 1,
+					}, status,
+				)
+			},
+		)
+	}
+}
+
+func TestGitBackend_Branches(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+
+				branches, err := backend.Branches(repo)
+				require.NoError(t, err)
+				assert.Equal(
+					t, []Branch{{Name: "feature"}, {Name: "main"}, {Name: "work"}, {Name: "main", Remote: "origin"}},
+					branches,
+				)
+
+				branches, err = backend.MergedBranches(repo, "origin/main")
+				require.NoError(t, err)
+				assert.Equal(
+					t, []Branch{{Name: "feature"}, {Name: "main"}, {Name: "main", Remote: "origin"}}, branches,
+				)
+
+				branches, err = backend.UnmergedBranches(repo, "main")
+				require.NoError(t, err)
+				assert.Equal(t, []Branch{{Name: "work"}}, branches)
+
+				_, err = backend.UnmergedBranches(repo, "missing")
+				assert.Error(t, err)
+			},
+		)
+	}
+}
+
+func TestGitBackend_Upstreams(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+				runGit(t, sh, repo.Path, "branch", "--set-upstream-to", "main", "feature")
+
+				upstreams, err := backend.Upstreams(repo)
+				require.NoError(t, err)
+				assert.Equal(t, map[string]string{"main": "origin/main", "feature": "main"}, upstreams)
+			},
+		)
+	}
+}
+
+func TestGitBackend_Tags(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+
+				tags, err := backend.Tags(repo)
+				require.NoError(t, err)
+				assert.Empty(t, tags)
+
+				runGit(t, sh, repo.Path, "tag", "v2", "work")
+				runGit(t, sh, repo.Path, "tag", "--annotate", "--message", "release", "v1")
+
+				tags, err = backend.Tags(repo)
+				require.NoError(t, err)
+				assert.Equal(t, []Tag{{Name: "v1"}, {Name: "v2"}}, tags)
+			},
+		)
+	}
+}
+
+func TestGitBackend_Log(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+				runGit(t, sh, repo.Path, "checkout", "work")
+				commitFile(t, sh, repo, "second.txt")
+
+				commits, err := backend.Log(repo, LogOptions{Ref: "work", Exclude: "main"})
+				require.NoError(t, err)
+				if assert.Len(t, commits, 2) {
+					assert.Equal(t, revParse(t, sh, repo, "work"), commits[0].Id)
+					assert.Equal(t, revParse(t, sh, repo, "work~1"), commits[1].Id)
+					assert.Equal(t, CommitUser{Name: "test", Email: "test@example.com"}, commits[0].Author)
+					assert.Equal(t, CommitUser{Name: "test", Email: "test@example.com"}, commits[0].Committer)
+					assert.False(t, commits[0].CommitDate.IsZero())
+					assert.Equal(t, "add second.txt", commits[0].Subject)
+				}
+
+				commits, err = backend.Log(repo, LogOptions{Ref: "main", Exclude: "work"})
+				require.NoError(t, err)
+				assert.Empty(t, commits)
+
+				_, err = backend.Log(repo, LogOptions{Ref: "missing", Exclude: "main"})
+				assert.Error(t, err)
+			},
+		)
+	}
+}
+
+func TestGitBackend_LogOptions(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+				require.NoError(t, os.MkdirAll(filepath.Join(repo.Path, "docs"), os.ModePerm))
+				commitFile(t, sh, repo, "docs/readme.txt")
+				runGit(
+					t, sh, repo.Path, "commit", "--allow-empty", "--author", "Other <other@example.com>",
+					"-m", "multiline\nsubject", "-m", "body of\nthe commit",
+				)
+
+				commits, err := backend.Log(repo, LogOptions{Ref: "HEAD"})
+				require.NoError(t, err)
+				if assert.Len(t, commits, 3) {
+					assert.Equal(t, "multiline subject", commits[0].Subject)
+					assert.Equal(t, "body of\nthe commit", commits[0].Body)
+					assert.Equal(t, "add docs/readme.txt", commits[1].Subject)
+					assert.Equal(t, "", commits[1].Body)
+				}
+
+				commits, err = backend.Log(repo, LogOptions{Ref: "HEAD", Authors: []string{"^Other", "nobody"}})
+				require.NoError(t, err)
+				if assert.Len(t, commits, 1) {
+					assert.Equal(t, CommitUser{Name: "Other", Email: "other@example.com"}, commits[0].Author)
+				}
+
+				commits, err = backend.Log(repo, LogOptions{Ref: "HEAD", Paths: []string{"docs"}})
+				require.NoError(t, err)
+				if assert.Len(t, commits, 1) {
+					assert.Equal(t, "add docs/readme.txt", commits[0].Subject)
+				}
+
+				commits, err = backend.Log(repo, LogOptions{Ref: "HEAD", MaxCount: 2})
+				require.NoError(t, err)
+				if assert.Len(t, commits, 2) {
+					assert.Equal(t, "multiline subject", commits[0].Subject)
+				}
+
+				commits, err = backend.Log(repo, LogOptions{Ref: "HEAD", Since: time.Now().Add(time.Hour)})
+				require.NoError(t, err)
+				assert.Empty(t, commits)
+
+				commits, err = backend.Log(repo, LogOptions{Ref: "HEAD", Until: time.Now().Add(-time.Hour)})
+				require.NoError(t, err)
+				assert.Empty(t, commits)
+			},
+		)
+	}
+}
+
+// TestGitBackend_LeftRight checks commits of a branch that merged another branch started before the merge base,
+// that are not reachable through the merge base
+func TestGitBackend_LeftRight(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+
+				date := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+				commit := func(fileName string) {
+					date = date.Add(time.Hour)
+					t.Setenv("GIT_AUTHOR_DATE", date.Format(time.RFC3339))
+					t.Setenv("GIT_COMMITTER_DATE", date.Format(time.RFC3339))
+					commitFile(t, sh, repo, fileName)
+				}
+
+				runGit(t, sh, repo.Path, "checkout", "-b", "old", "main")
+				commit("old.txt")
+				runGit(t, sh, repo.Path, "checkout", "main")
+				commit("main1.txt")
+				commit("main2.txt")
+				runGit(t, sh, repo.Path, "checkout", "work")
+				runGit(t, sh, repo.Path, "merge", "--no-ff", "-m", "merge old", "old")
+				runGit(t, sh, repo.Path, "branch", "--set-upstream-to=main")
+
+				status, err := backend.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(t, RepoStatus{Status: StatusClean, Ref: "work", Upstream: "main", Ahead: 3, Behind: 2}, status)
+
+				commits, err := backend.Log(repo, LogOptions{Ref: "work", Exclude: "main"})
+				require.NoError(t, err)
+				var subjects []string
+				for _, item := range commits {
+					subjects = append(subjects, item.Subject)
+				}
+				assert.ElementsMatch(t, []string{"merge old", "add old.txt", "add work.txt"}, subjects)
+
+				commits, err = backend.Log(repo, LogOptions{Ref: "old", Exclude: "work"})
+				require.NoError(t, err)
+				assert.Empty(t, commits)
+			},
+		)
+	}
+}
+
+func TestGitBackend_LinkedWorktree(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+
+				worktreePath := filepath.Join(t.TempDir(), "work")
+				runGit(t, sh, repo.Path, "worktree", "add", worktreePath, "work")
+				worktreeRepo := &model.Repo{Name: repo.Name, Url: repo.Url, Path: worktreePath}
+
+				status, err := backend.Status(worktreeRepo)
+				require.NoError(t, err)
+				assert.Equal(t, RepoStatus{Status: StatusClean, Ref: "work"}, status)
+
+				commits, err := backend.Log(worktreeRepo, LogOptions{Ref: "work", Exclude: "main"})
+				require.NoError(t, err)
+				if assert.Len(t, commits, 1) {
+					assert.Equal(t, "add work.txt", commits[0].Subject)
+				}
+			},
+		)
+	}
+}
+
+func TestGitBackend_BranchCommits(t *testing.T) {
+	for _, backendName := range testBackends {
+		t.Run(
+			string(backendName), func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				backend := NewGitBackend(sh, backendName)
+				commitFile(t, sh, repo, "main.txt")
+
+				branchCommits, err := backend.BranchCommits(repo)
+				require.NoError(t, err)
+				for branch, subject := range map[string]string{
+					"refs/heads/main":          "add main.txt",
+					"refs/heads/work":          "add work.txt",
+					"refs/heads/feature":       "initial",
+					"refs/remotes/origin/main": "initial",
+				} {
+					commit := branchCommits[branch]
+					assert.Equal(t, subject, commit.Subject, branch)
+					assert.Equal(t, revParse(t, sh, repo, branch), commit.Id, branch)
+					assert.Equal(t, CommitUser{Name: "test", Email: "test@example.com"}, commit.Author, branch)
+				}
+				assert.NotContains(t, branchCommits, "refs/remotes/origin/HEAD")
+
+				ahead, behind, err := backend.AheadBehind(repo, "work", "main")
+				require.NoError(t, err)
+				assert.Equal(t, 1, ahead)
+				assert.Equal(t, 1, behind)
+
+				ahead, behind, err = backend.AheadBehind(repo, "origin/main", "main")
+				require.NoError(t, err)
+				assert.Equal(t, 0, ahead)
+				assert.Equal(t, 1, behind)
+			},
+		)
+	}
+}
@@ -14,15 +14,19 @@ import (
 	"os"
 	"regexp"
 	"strings"
-	"time"
 )
 
+// shortCommitIdLength is the length git abbreviates commit hashes to by default
+const shortCommitIdLength = 7
+
 type GitService struct {
-	sh shell.Shell
+	sh      shell.Shell
+	backend GitBackend
 }
 
-func NewGitService(sh shell.Shell) GitService {
-	return GitService{sh: sh}
+// NewGitService creates a service that reads repositories with the backend
+func NewGitService(sh shell.Shell, backend config.GitBackend) GitService {
+	return GitService{sh: sh, backend: NewGitBackend(sh, backend)}
 }
 
 func (g *GitService) CloneRepo(repo *model.Repo, recreate bool) (CloneResult, error) {
@@ -46,7 +50,7 @@ func (g *GitService) CloneRepo(repo *model.Repo, recreate bool) (CloneResult, er
 			emptyDir = false
 			originalDirectoryDeleted = true
 		} else {
-			_, _, err := g.Status(repo)
+			_, err := g.Status(repo)
 			if err != nil {
 				return CloneError, err
 			}
@@ -61,7 +65,7 @@ func (g *GitService) CloneRepo(repo *model.Repo, recreate bool) (CloneResult, er
 		}
 	}
 
-	output, err := g.sh.RunCommand(repo.Path, "git", "clone", repo.Url, ".")
+	output, err := g.sh.RunCommand(repo.Path, "git", "clone", "--recurse-submodules", repo.Url, ".")
 	if err != nil {
 		return CloneError, fmt.Errorf("failed to clone repository: %v, %w", output, err)
 	}
@@ -85,15 +89,68 @@ func (g *GitService) Fetch(repo *model.Repo) error {
 func (g *GitService) Pull(repo *model.Repo) error {
 	output, err := g.sh.RunCommand(repo.Path, "git", "pull", "--prune")
 	if err != nil {
-		if strings.Contains(output, "There is no tracking information for the current branch") {
+		if !g.hasUpstream(repo) {
 			return fmt.Errorf("no remote upstream configured")
 		}
 		return fmt.Errorf("failed to pull remote: %v, %w", output, err)
 	}
 
+	exists, err := hasSubmodules(repo)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return g.UpdateSubmodules(repo, false)
+	}
+
+	return nil
+}
+
+// SyncSubmodules updates submodule URLs from the repository configuration, initializes missing submodules
+// and checks them out at the recorded commits. If `remote` is set, they are updated to the latest remote commits instead
+func (g *GitService) SyncSubmodules(repo *model.Repo, remote bool) (SubmoduleResult, error) {
+	exists, err := hasSubmodules(repo)
+	if err != nil {
+		return SubmodulesError, err
+	}
+	if !exists {
+		return SubmodulesNone, nil
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", "submodule", "sync", "--recursive")
+	if err != nil {
+		return SubmodulesError, fmt.Errorf("failed to sync submodules: %v, %w", output, err)
+	}
+
+	err = g.UpdateSubmodules(repo, remote)
+	if err != nil {
+		return SubmodulesError, err
+	}
+
+	return SubmodulesSynced, nil
+}
+
+// UpdateSubmodules initializes missing submodules recursively and checks them out at the recorded commits,
+// or at the latest remote commits if `remote` is set
+func (g *GitService) UpdateSubmodules(repo *model.Repo, remote bool) error {
+	arguments := []string{"submodule", "update", "--init", "--recursive"}
+	if remote {
+		arguments = append(arguments, "--remote")
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		return fmt.Errorf("failed to update submodules: %v, %w", output, err)
+	}
+
 	return nil
 }
 
+// GetSubmodules returns the submodules of the repository along with their state
+func (g *GitService) GetSubmodules(repo *model.Repo) ([]Submodule, error) {
+	return g.backend.Submodules(repo)
+}
+
 func (g *GitService) Push(repo *model.Repo, branch string, allBranches bool, force bool) error {
 	remote, err := g.getTheOnlyRemote(repo)
 	if err != nil {
@@ -125,31 +182,30 @@ func (g *GitService) Push(repo *model.Repo, branch string, allBranches bool, for
 	return nil
 }
 
-func (g *GitService) Status(repo *model.Repo) (StatusResult, string, error) {
+func (g *GitService) Status(repo *model.Repo) (RepoStatus, error) {
 	err := fileops.CheckRepoExists(repo)
 	if err != nil {
 		if errors.Is(err, fileops.ErrRepositoryNotCloned) {
-			return StatusMissing, "", nil
+			return RepoStatus{Status: StatusMissing}, nil
 		} else {
-			return StatusError, "", fmt.Errorf("failed to get stat of the directory %v: %w", repo.Path, err)
+			return RepoStatus{Status: StatusError}, fmt.Errorf(
+				"failed to get stat of the directory %v: %w", repo.Path, err,
+			)
 		}
 	}
 
-	statusOutput, err := g.sh.RunCommand(repo.Path, "git", "status")
+	result, err := g.backend.Status(repo)
 	if err != nil {
-		return StatusError, "", fmt.Errorf("failed to get git status: %v, %w", statusOutput, err)
+		return RepoStatus{Status: StatusError}, err
 	}
 
-	ref, err := g.parseHeadRef(statusOutput)
+	submodules, err := g.backend.Submodules(repo)
 	if err != nil {
-		return StatusError, "", err
+		return RepoStatus{Status: StatusError}, err
 	}
+	result.Submodules = lo.CountBy(submodules, func(submodule Submodule) bool { return submodule.Drifted() })
 
-	if strings.Contains(statusOutput, "working tree clean") {
-		return StatusClean, ref, nil
-	}
-
-	return StatusDirty, ref, nil
+	return result, nil
 }
 
 func (g *GitService) CreateBranch(repo *model.Repo, name string) (CreateResult, error) {
@@ -174,7 +230,7 @@ func (g *GitService) RemoveBranch(repo *model.Repo, branch Branch) error {
 	if branch.IsLocal() {
 		output, err := g.sh.RunCommand(repo.Path, "git", "branch", "-D", branch.Short())
 		if err != nil {
-			if strings.Contains(output, "checked out at") {
+			if g.isCheckedOut(repo, branch.Short()) {
 				return fmt.Errorf("the branch is checked out")
 			}
 			return fmt.Errorf("failed to remove local branch: %v %w", output, err)
@@ -218,22 +274,58 @@ func (g *GitService) CleanBranches(repo *model.Repo, mode config.GitMode) (strin
 	return strings.TrimSpace(result.String()), nil
 }
 
-func (g *GitService) Commit(repo *model.Repo, pattern string, message string) error {
+// Commit stages files matching the pattern and commits them.
+// If there is nothing to commit, the result tells so without an error
+func (g *GitService) Commit(repo *model.Repo, options CommitOptions) (CommitResult, error) {
+	pattern := options.Pattern
 	if pattern == "" {
 		pattern = "**"
 	}
 
+	if options.Message == "" && !options.Amend {
+		return CommitError, errors.New("commit message is required")
+	}
+
 	output, err := g.sh.RunCommand(repo.Path, "git", "add", pattern)
 	if err != nil {
-		return fmt.Errorf("failed to add changes to stage: %v %w", output, err)
+		return CommitError, fmt.Errorf("failed to add changes to stage: %v %w", output, err)
+	}
+
+	arguments := []string{"commit"}
+	if options.Message != "" {
+		arguments = append(arguments, "-m", options.Message)
+	} else {
+		arguments = append(arguments, "--no-edit")
+	}
+	if options.StripComments {
+		arguments = append(arguments, "--cleanup=strip")
+	}
+	if options.Author != "" {
+		arguments = append(arguments, "--author="+options.Author)
+	}
+	for _, coAuthor := range options.CoAuthors {
+		arguments = append(arguments, "--trailer", "Co-authored-by: "+coAuthor)
+	}
+	if options.SignOff {
+		arguments = append(arguments, "--signoff")
+	}
+	if options.Amend {
+		arguments = append(arguments, "--amend")
 	}
 
-	output, err = g.sh.RunCommand(repo.Path, "git", "commit", "-m", message)
+	output, err = g.sh.RunCommand(repo.Path, "git", arguments...)
 	if err != nil {
-		return fmt.Errorf("failed to commit: %v %w", output, err)
+		if !options.Amend && !g.hasStagedChanges(repo) {
+			return CommitNothing, nil
+		}
+		return CommitError, fmt.Errorf("failed to commit: %v %w", output, err)
 	}
 
-	return nil
+	if options.Amend {
+		return CommitAmended, nil
+	}
+
+	return CommitCommitted, nil
 }
 
 func (g *GitService) Checkout(repo *model.Repo, ref string) (CheckoutResult, error) {
@@ -251,19 +343,21 @@ func (g *GitService) Checkout(repo *model.Repo, ref string) (CheckoutResult, err
 		return CheckoutError, fmt.Errorf("failed to checkout: %v, %w", output, err)
 	}
 
-	if strings.Contains(output, "Already on") {
-		return CheckoutOk, nil
-	}
-
-	if strings.Contains(output, "Switched to branch") {
-		return CheckoutOk, nil
-	}
+	return CheckoutOk, nil
+}
 
-	if strings.Contains(output, "Switched to a new branch") {
-		return CheckoutOk, nil
-	}
+// Rebase rebases the current branch onto `ref`.
+// If there are conflicts, the rebase is aborted and the conflicting files are returned
+func (g *GitService) Rebase(repo *model.Repo, ref string) (IntegrateResult, []string, error) {
+	return g.integrate(repo, ref, IntegrateRebased, []string{"rebase", ref}, []string{"rebase", "--abort"})
+}
 
-	return CheckoutError, fmt.Errorf("unknown checkout status: %v", output)
+// Merge merges `ref` into the current branch.
+// If there are conflicts, the merge is aborted and the conflicting files are returned
+func (g *GitService) Merge(repo *model.Repo, ref string) (IntegrateResult, []string, error) {
+	return g.integrate(
+		repo, ref, IntegrateMerged, []string{"merge", "--no-edit", ref}, []string{"merge", "--abort"},
+	)
 }
 
 func (g *GitService) Discard(repo *model.Repo) error {
@@ -275,18 +369,183 @@ func (g *GitService) Discard(repo *model.Repo) error {
 	return nil
 }
 
-func (g *GitService) GetBranches(repo *model.Repo, mode config.GitMode, pattern string) ([]Branch, error) {
-	reg, err := regexp.Compile("^" + pattern + "$")
+// StashPush stashes uncommitted changes of tracked files, and untracked ones if `includeUntracked` is set
+func (g *GitService) StashPush(repo *model.Repo, message string, includeUntracked bool) (StashResult, error) {
+	status, err := g.Status(repo)
+	if err != nil {
+		return StashError, err
+	}
+	if status.Staged == 0 && status.Unstaged == 0 && (!includeUntracked || status.Untracked == 0) {
+		return StashNoChanges, nil
+	}
+
+	arguments := []string{"stash", "push"}
+	if includeUntracked {
+		arguments = append(arguments, "--include-untracked")
+	}
+	if message != "" {
+		arguments = append(arguments, "--message", message)
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		return StashError, fmt.Errorf("failed to stash: %v, %w", output, err)
+	}
+
+	return StashCreated, nil
+}
+
+// StashPop applies the stash entry `ref`, or the latest one if `ref` is empty, and removes it from the stash.
+// If the changes can't be applied, the entry is kept in the stash.
+// If they conflict with the working tree, the conflicts are left in it and StashConflict is returned
+func (g *GitService) StashPop(repo *model.Repo, ref string) (StashResult, error) {
+	return g.stashCommand(repo, "pop", ref, StashRestored)
+}
+
+// StashDrop removes the stash entry `ref`, or the latest one if `ref` is empty
+func (g *GitService) StashDrop(repo *model.Repo, ref string) (StashResult, error) {
+	return g.stashCommand(repo, "drop", ref, StashDropped)
+}
+
+// StashList returns the stash entries starting from the latest one
+func (g *GitService) StashList(repo *model.Repo) ([]Stash, error) {
+	output, err := g.sh.RunCommand(repo.Path, "git", "stash", "list", "--format="+stashFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stash: %v, %w", output, err)
+	}
+
+	return parseStashes(output)
+}
+
+// WithAutostash stashes uncommitted changes of tracked files, runs the operation and restores the changes.
+// The changes are restored even if the operation fails. If they conflict with the result of the operation,
+// the conflicts are left in the working tree and the entry is kept in the stash
+func (g *GitService) WithAutostash(repo *model.Repo, operation func() error) (StashResult, error) {
+	stashResult, err := g.StashPush(repo, AutostashMessage, false)
+	if err != nil {
+		return stashResult, err
+	}
+
+	operationErr := operation()
+
+	if stashResult == StashCreated {
+		output, err := g.sh.RunCommand(repo.Path, "git", "stash", "pop")
+		if err != nil {
+			popErr := g.stashConflictError(repo, err)
+			if popErr != nil {
+				stashResult = StashConflict
+			} else {
+				popErr = fmt.Errorf("failed to restore stash, the changes are kept in the stash: %v, %w", output, err)
+			}
+			if operationErr != nil {
+				return stashResult, fmt.Errorf("%w, %w", operationErr, popErr)
+			}
+
+			return stashResult, popErr
+		}
+		stashResult = StashRestored
+	}
+
+	return stashResult, operationErr
+}
+
+// GetWorktrees returns the worktrees of the repository starting from the main one
+func (g *GitService) GetWorktrees(repo *model.Repo) ([]Worktree, error) {
+	output, err := g.sh.RunCommand(repo.Path, "git", "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %v, %w", output, err)
+	}
+
+	return parseWorktrees(output)
+}
+
+// AddWorktree checks `branch` out to a new worktree at `path`.
+// If `create` is set, the branch is created from `ref`, or from the current commit if `ref` is empty.
+// Otherwise, a branch that exists only in the remote repository is checked out as a new tracking branch
+func (g *GitService) AddWorktree(
+	repo *model.Repo, path string, branch string, create bool, ref string,
+) (WorktreeResult, error) {
+	worktree, err := g.findWorktree(repo, path)
+	if err != nil {
+		return WorktreeError, err
+	}
+
+	if worktree != nil {
+		return WorktreeExists, nil
+	}
+
+	arguments := []string{"worktree", "add"}
+	if create {
+		arguments = append(arguments, "-b", branch, path)
+		if ref != "" {
+			arguments = append(arguments, ref)
+		}
+	} else {
+		arguments = append(arguments, path, branch)
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		return WorktreeError, fmt.Errorf("failed to add worktree: %v, %w", output, err)
+	}
+
+	return WorktreeAdded, nil
+}
+
+// RemoveWorktree removes the worktree at `path`.
+// A worktree with uncommitted changes or a locked one is removed only if `force` is set
+func (g *GitService) RemoveWorktree(repo *model.Repo, path string, force bool) (WorktreeResult, error) {
+	worktree, err := g.findWorktree(repo, path)
+	if err != nil {
+		return WorktreeError, err
+	}
+
+	if worktree == nil {
+		return WorktreeNotFound, nil
+	}
+
+	if worktree.Main {
+		return WorktreeError, fmt.Errorf("main worktree can't be removed")
+	}
+
+	arguments := []string{"worktree", "remove"}
+	if force {
+		// git requires the flag twice to remove a locked worktree
+		arguments = append(arguments, "--force", "--force")
+	}
+	arguments = append(arguments, path)
+
+	output, err := g.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		return WorktreeError, fmt.Errorf("failed to remove worktree: %v, %w", output, err)
+	}
+
+	return WorktreeRemoved, nil
+}
+
+func (g *GitService) findWorktree(repo *model.Repo, path string) (*Worktree, error) {
+	worktrees, err := g.GetWorktrees(repo)
 	if err != nil {
 		return nil, err
 	}
 
-	output, err := g.sh.RunCommand(repo.Path, "git", "branch", "-a", "--format=%(refname)")
+	path = normalizeWorktreePath(repo.Path, path)
+	for _, worktree := range worktrees {
+		if normalizeWorktreePath(repo.Path, worktree.Path) == path {
+			return &worktree, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (g *GitService) GetBranches(repo *model.Repo, mode config.GitMode, pattern string) ([]Branch, error) {
+	reg, err := regexp.Compile("^" + pattern + "$")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get branches: %v, %w", output, err)
+		return nil, err
 	}
 
-	branches, err := g.parseBranches(output)
+	branches, err := g.backend.Branches(repo)
 	if err != nil {
 		return nil, err
 	}
@@ -312,6 +571,99 @@ func (g *GitService) GetBranches(repo *model.Repo, mode config.GitMode, pattern
 
 }
 
+// GetTags returns local tags and tags of the remote repository, depending on `mode`, that match `pattern`
+func (g *GitService) GetTags(repo *model.Repo, mode config.GitMode, pattern string) ([]Tag, error) {
+	reg, err := regexp.Compile("^" + pattern + "$")
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	if mode.Includes(config.GitModeLocal) {
+		localTags, err := g.backend.Tags(repo)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, localTags...)
+	}
+	if mode.Includes(config.GitModeRemote) {
+		remoteTags, err := g.getRemoteTags(repo)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, remoteTags...)
+	}
+
+	var result []Tag
+	for _, tag := range tags {
+		if reg.MatchString(tag.Name) || pattern == tag.Short() {
+			result = append(result, tag)
+		}
+	}
+
+	return result, nil
+}
+
+// CreateTag creates a tag at `ref`, or at the current commit if `ref` is empty.
+// The tag is annotated if `message` is set, otherwise it's lightweight
+func (g *GitService) CreateTag(repo *model.Repo, name string, ref string, message string) (TagResult, error) {
+	tags, err := g.GetTags(repo, config.GitModeLocal, regexp.QuoteMeta(name))
+	if err != nil {
+		return TagError, err
+	}
+
+	if len(tags) > 0 {
+		return TagError, fmt.Errorf("tag already exists")
+	}
+
+	arguments := []string{"tag"}
+	if message != "" {
+		arguments = append(arguments, "--annotate", "--message", message)
+	}
+	arguments = append(arguments, name)
+	if ref != "" {
+		arguments = append(arguments, ref)
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		return TagError, fmt.Errorf("failed to create tag: %v, %w", output, err)
+	}
+
+	return TagCreated, nil
+}
+
+// PushTag pushes the local tag to the remote repository
+func (g *GitService) PushTag(repo *model.Repo, tag Tag) (TagResult, error) {
+	remote, err := g.getTheOnlyRemote(repo)
+	if err != nil {
+		return TagError, err
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", "push", remote, tag.String())
+	if err != nil {
+		return TagError, fmt.Errorf("failed to push tag: %v, %w", output, err)
+	}
+
+	return TagPushed, nil
+}
+
+func (g *GitService) RemoveTag(repo *model.Repo, tag Tag) (TagResult, error) {
+	if tag.IsLocal() {
+		output, err := g.sh.RunCommand(repo.Path, "git", "tag", "--delete", tag.Name)
+		if err != nil {
+			return TagError, fmt.Errorf("failed to remove local tag: %v, %w", output, err)
+		}
+	} else {
+		output, err := g.sh.RunCommand(repo.Path, "git", "push", tag.Remote, "--delete", tag.String())
+		if err != nil {
+			return TagError, fmt.Errorf("failed to remove remote tag: %v, %w", output, err)
+		}
+	}
+
+	return TagRemoved, nil
+}
+
 func (g *GitService) GetDefaultBranch(repo *model.Repo) (*Branch, error) {
 	remote, err := g.getTheOnlyRemote(repo)
 	if err != nil {
@@ -323,7 +675,7 @@ func (g *GitService) GetDefaultBranch(repo *model.Repo) (*Branch, error) {
 
 // GetUnmergedBranches returns branches that are not merged to `ref`
 func (g *GitService) GetUnmergedBranches(repo *model.Repo, mode config.GitMode, ref string) ([]Branch, error) {
-	branches, err := g.getUnmergedBranches(repo, ref)
+	branches, err := g.backend.UnmergedBranches(repo, ref)
 	if err != nil {
 		return nil, err
 	}
@@ -350,161 +702,143 @@ func (g *GitService) GetUnmergedBranches(repo *model.Repo, mode config.GitMode,
 // The commits are ordered by `committedAt` attribute
 // Returns an error when `branch` and `ref` don't have a common parent
 func (g *GitService) GetUnmergedCommits(repo *model.Repo, branch Branch, ref string) ([]Commit, error) {
-	output, err := g.sh.RunCommand(
-		repo.Path, "git", "--no-pager", "log", branch.Short(), "--not", ref,
-		"--pretty=fuller",
-	)
+	return g.backend.Log(repo, LogOptions{Ref: branch.Short(), Exclude: ref})
+}
+
+// GetBranchesInfo returns the last commit and the upstream of the branches,
+// and the number of commits they are ahead and behind `ref`
+func (g *GitService) GetBranchesInfo(repo *model.Repo, branches []Branch, ref string) ([]BranchInfo, error) {
+	upstreams, err := g.backend.Upstreams(repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get unmerged commits: %v, %w", output, err)
+		return nil, err
 	}
 
-	return g.parseCommits(output)
-}
+	branchCommits, err := g.backend.BranchCommits(repo)
+	if err != nil {
+		return nil, err
+	}
 
-func (g *GitService) parseBranches(consoleOutputString string) ([]Branch, error) {
-	var result []Branch
-	outputBranchNames := strings.FieldsFunc(
-		consoleOutputString, func(r rune) bool {
-			return r == '\n'
-		},
-	)
-	for _, outputBranchName := range outputBranchNames {
-		branch, err := parseBranch(outputBranchName)
+	var result []BranchInfo
+	for _, branch := range branches {
+		lastCommit, exists := branchCommits[branch.String()]
+		if !exists {
+			return nil, fmt.Errorf("failed to find the last commit of %v", branch.Short())
+		}
+
+		ahead, behind, err := g.backend.AheadBehind(repo, branch.Short(), ref)
 		if err != nil {
-			if errors.Is(err, ErrDetachedHead) {
-				continue
-			}
 			return nil, err
 		}
 
-		if branch.Name == Head {
-			continue
+		info := BranchInfo{
+			Branch:     branch,
+			LastCommit: lastCommit,
+			Ahead:      ahead,
+			Behind:     behind,
 		}
-		result = append(result, *branch)
+		if branch.IsLocal() {
+			info.Upstream = upstreams[branch.Name]
+		}
+		result = append(result, info)
 	}
 
 	return result, nil
 }
 
-func (g *GitService) parseCommits(consoleOutputString string) ([]Commit, error) {
-	commitRegexp := regexp.MustCompile(
-		`commit\s+(\w+?)\n?.*
-Author:\s+(.+?) <(.+?)>
-AuthorDate:\s+(.+?)
-Commit:\s+(.+?) <(.+?)>
-CommitDate:\s+(.+?)
-`,
-	)
-
-	var commits []Commit
-	submatches := commitRegexp.FindAllStringSubmatch(consoleOutputString, -1)
-	if submatches == nil {
-		return nil, errors.New("failed to parse commits from console output")
-	}
-	for _, submatch := range submatches {
-		commitId := submatch[1]
-		authorName := submatch[2]
-		authorEmail := submatch[3]
-		authorDate := submatch[4]
-		commitName := submatch[5]
-		commitEmail := submatch[6]
-		commitDate := submatch[7]
-
-		authorDateTime, err := time.Parse("Mon Jan 2 15:04:05 2006 -0700", authorDate)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse author date from commit")
-		}
-
-		commitDateTime, err := time.Parse("Mon Jan 2 15:04:05 2006 -0700", commitDate)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse commit date from commit")
-		}
-
-		commit := Commit{
-			Id:         commitId,
-			AuthorDate: authorDateTime,
-			Author: CommitUser{
-				Name:  authorName,
-				Email: authorEmail,
-			},
-			CommitDate: commitDateTime,
-			Committer: CommitUser{
-				Name:  commitName,
-				Email: commitEmail,
-			},
-		}
+// GetCommits returns commits matching the options, the most recently committed first
+func (g *GitService) GetCommits(repo *model.Repo, options LogOptions) ([]Commit, error) {
+	return g.backend.Log(repo, options)
+}
 
-		commits = append(commits, commit)
+// GetRemoteUrl returns URL of the repository remote the repository was cloned from
+func (g *GitService) GetRemoteUrl(repo *model.Repo) (string, error) {
+	remote, err := g.getTheOnlyRemote(repo)
+	if err != nil {
+		return "", err
 	}
 
-	return commits, nil
+	return g.GetNamedRemoteUrl(repo, remote)
 }
 
-func (g *GitService) getDefaultRemoteBranch(repo *model.Repo, remote string) (*Branch, error) {
-	output, err := g.sh.RunCommand(
-		repo.Path, "git", "remote", "show", remote,
-	)
+func (g *GitService) GetNamedRemoteUrl(repo *model.Repo, remote string) (string, error) {
+	output, err := g.sh.RunCommand(repo.Path, "git", "remote", "get-url", remote)
 	if err != nil {
-		return nil, fmt.Errorf("failed to show remote: %v, %w", output, err)
+		return "", fmt.Errorf("failed to get remote url: %v, %w", output, err)
 	}
 
-	reg, err := regexp.Compile("HEAD branch: (.+)")
+	return strings.TrimSpace(output), nil
+}
+
+func (g *GitService) getDefaultRemoteBranch(repo *model.Repo, remote string) (*Branch, error) {
+	output, err := g.sh.RunCommand(repo.Path, "git", "ls-remote", "--symref", remote, Head)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to list remote HEAD: %v, %w", output, err)
 	}
 
-	var branchName string
-	err = scanRegexp(output, reg, &branchName)
-	if err != nil {
-		return nil, err
+	// the symbolic reference line looks like "ref: refs/heads/master<TAB>HEAD"
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "ref:" && fields[2] == Head {
+			return &Branch{Name: strings.TrimPrefix(fields[1], RefHeadPrefix), Remote: remote}, nil
+		}
 	}
 
-	return &Branch{Name: branchName, Remote: remote}, nil
+	return nil, fmt.Errorf("can't find remote HEAD branch: %v", output)
 }
 
-func (g *GitService) parseHeadRef(statusResult string) (string, error) {
-	reg, err := regexp.Compile("On branch (.+)\n.*")
+// getRemoteTags returns tags of the remote repository, which requires a connection to it
+func (g *GitService) getRemoteTags(repo *model.Repo) ([]Tag, error) {
+	remote, err := g.getTheOnlyRemote(repo)
 	if err != nil {
-		return "", err
-	}
-
-	submatch := reg.FindStringSubmatch(statusResult)
-	if submatch != nil {
-		return submatch[1], nil
+		return nil, err
 	}
 
-	reg, err = regexp.Compile("HEAD detached at (.+)\n.*")
+	output, err := g.sh.RunCommand(repo.Path, "git", "ls-remote", "--tags", "--refs", remote)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to list remote tags: %v, %w", output, err)
 	}
 
-	submatch = reg.FindStringSubmatch(statusResult)
-	if submatch != nil {
-		return submatch[1], nil
+	// every line looks like "<commit id><TAB>refs/tags/v1.0.0"
+	var result []Tag
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		tag, err := parseTag(fields[1], remote)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *tag)
 	}
 
-	return "", fmt.Errorf("can't parse status result for head reference: %v", statusResult)
+	return result, nil
 }
 
-func (g *GitService) getUnmergedBranches(repo *model.Repo, ref string) ([]Branch, error) {
-	output, err := g.sh.RunCommand(repo.Path, "git", "branch", "-a", "--format=%(refname)", "--no-merged", ref)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get branches: %v, %w", output, err)
-	}
+// hasStagedChanges checks whether there are changes to commit.
+// The command exits with 1 if there are changes, any other failure is considered as changes present too
+func (g *GitService) hasStagedChanges(repo *model.Repo) bool {
+	_, err := g.sh.RunCommand(repo.Path, "git", "diff", "--cached", "--quiet")
+	return err != nil
+}
 
-	return g.parseBranches(output)
+// hasUpstream checks whether the current branch tracks a remote one
+func (g *GitService) hasUpstream(repo *model.Repo) bool {
+	_, err := g.sh.RunCommand(repo.Path, "git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	return err == nil
 }
 
-func (g *GitService) cleanLocalBranches(repo *model.Repo, defaultRemoteBranch *Branch, result *bytes.Buffer) error {
+// isCheckedOut checks whether the local branch is checked out in the repository or any of its worktrees
+func (g *GitService) isCheckedOut(repo *model.Repo, branchName string) bool {
 	output, err := g.sh.RunCommand(
-		repo.Path, "git", "branch", "-a", "--format=%(refname)", "--merged",
-		defaultRemoteBranch.Name,
+		repo.Path, "git", "for-each-ref", "--format=%(worktreepath)", RefHeadPrefix+branchName,
 	)
-	if err != nil {
-		return fmt.Errorf("failed to get branches: %v, %w", output, err)
-	}
+	return err == nil && strings.TrimSpace(output) != ""
+}
 
-	branches, err := g.parseBranches(output)
+func (g *GitService) cleanLocalBranches(repo *model.Repo, defaultRemoteBranch *Branch, result *bytes.Buffer) error {
+	branches, err := g.backend.MergedBranches(repo, defaultRemoteBranch.Name)
 	if err != nil {
 		return err
 	}
@@ -518,7 +852,7 @@ func (g *GitService) cleanLocalBranches(repo *model.Repo, defaultRemoteBranch *B
 		}
 		output, err := g.sh.RunCommand(repo.Path, "git", "branch", "-d", branch.Name)
 		if err != nil {
-			if strings.Contains(output, "checked out at") {
+			if g.isCheckedOut(repo, branch.Name) {
 				result.WriteString(fmt.Sprintf("%v: failed: %v\n", branch.Name, output))
 				return fmt.Errorf("the branch is checked out")
 			}
@@ -534,15 +868,7 @@ func (g *GitService) cleanLocalBranches(repo *model.Repo, defaultRemoteBranch *B
 func (g *GitService) cleanRemoteBranches(
 	repo *model.Repo, remote string, defaultRemoteBranch *Branch, result *bytes.Buffer,
 ) error {
-	output, err := g.sh.RunCommand(
-		repo.Path, "git", "branch", "-a", "--format=%(refname)", "--merged",
-		defaultRemoteBranch.Short(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to get branches: %v, %w", output, err)
-	}
-
-	branches, err := g.parseBranches(output)
+	branches, err := g.backend.MergedBranches(repo, defaultRemoteBranch.Short())
 	if err != nil {
 		return err
 	}
@@ -565,6 +891,138 @@ func (g *GitService) cleanRemoteBranches(
 	return nil
 }
 
+// integrate fast-forwards the current branch to `ref` if possible, otherwise runs the git command.
+// The command is aborted if it fails, so that the repository stays as it was
+func (g *GitService) integrate(
+	repo *model.Repo, ref string, successResult IntegrateResult, arguments []string, abortArguments []string,
+) (IntegrateResult, []string, error) {
+	status, err := g.Status(repo)
+	if err != nil {
+		return IntegrateError, nil, err
+	}
+	if status.Staged > 0 || status.Unstaged > 0 {
+		return IntegrateError, nil, errors.New("the repository has uncommitted changes")
+	}
+
+	upToDate, err := g.isAncestor(repo, ref, Head)
+	if err != nil {
+		return IntegrateError, nil, err
+	}
+	if upToDate {
+		return IntegrateUpToDate, nil, nil
+	}
+
+	fastForward, err := g.isAncestor(repo, Head, ref)
+	if err != nil {
+		return IntegrateError, nil, err
+	}
+	if fastForward {
+		output, err := g.sh.RunCommand(repo.Path, "git", "merge", "--ff-only", ref)
+		if err != nil {
+			return IntegrateError, nil, fmt.Errorf("failed to fast-forward: %v, %w", output, err)
+		}
+		return IntegrateFastForwarded, nil, nil
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", arguments...)
+	if err == nil {
+		return successResult, nil, nil
+	}
+
+	conflicts, conflictsErr := g.getConflictingFiles(repo)
+	if conflictsErr != nil || len(conflicts) == 0 {
+		// the command failed for another reason, but it can still be in progress
+		_, _ = g.sh.RunCommand(repo.Path, "git", abortArguments...)
+		return IntegrateError, nil, fmt.Errorf("failed to %v: %v, %w", arguments[0], output, err)
+	}
+
+	abortOutput, err := g.sh.RunCommand(repo.Path, "git", abortArguments...)
+	if err != nil {
+		return IntegrateError, conflicts, fmt.Errorf("failed to abort %v: %v, %w", arguments[0], abortOutput, err)
+	}
+
+	return IntegrateConflict, conflicts, nil
+}
+
+// isAncestor checks whether `ancestor` commit is reachable from `ref`
+func (g *GitService) isAncestor(repo *model.Repo, ancestor string, ref string) (bool, error) {
+	output, err := g.sh.RunCommand(repo.Path, "git", "merge-base", "--is-ancestor", ancestor, ref)
+	if err == nil {
+		return true, nil
+	}
+	if shell.ExitCode(err) == 1 {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("failed to compare %v and %v: %v, %w", ancestor, ref, output, err)
+}
+
+func (g *GitService) getConflictingFiles(repo *model.Repo) ([]string, error) {
+	output, err := g.sh.RunCommand(repo.Path, "git", "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conflicting files: %v, %w", output, err)
+	}
+
+	return strings.FieldsFunc(
+		output, func(r rune) bool {
+			return r == '\n'
+		},
+	), nil
+}
+
+func (g *GitService) stashCommand(
+	repo *model.Repo, command string, ref string, successResult StashResult,
+) (StashResult, error) {
+	stashes, err := g.StashList(repo)
+	if err != nil {
+		return StashError, err
+	}
+
+	arguments := []string{"stash", command}
+	if ref == "" {
+		if len(stashes) == 0 {
+			return StashNotFound, nil
+		}
+	} else {
+		_, found := lo.Find(
+			stashes, func(item Stash) bool {
+				return item.Ref == ref
+			},
+		)
+		if !found {
+			return StashNotFound, nil
+		}
+		arguments = append(arguments, ref)
+	}
+
+	output, err := g.sh.RunCommand(repo.Path, "git", arguments...)
+	if err != nil {
+		if command == "pop" {
+			conflictErr := g.stashConflictError(repo, err)
+			if conflictErr != nil {
+				return StashConflict, conflictErr
+			}
+		}
+		return StashError, fmt.Errorf("failed to %v stash: %v, %w", command, output, err)
+	}
+
+	return successResult, nil
+}
+
+// stashConflictError returns an error with the files that a restored stash entry conflicts in,
+// or nil if there are no conflicts. `err` is the error of restoring the entry
+func (g *GitService) stashConflictError(repo *model.Repo, err error) error {
+	conflicts, conflictsErr := g.getConflictingFiles(repo)
+	if conflictsErr != nil || len(conflicts) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"restored stash conflicts in %v, resolve the conflicts and drop the stash entry: %w",
+		strings.Join(conflicts, ", "), err,
+	)
+}
+
 func (g *GitService) getTheOnlyRemote(repo *model.Repo) (string, error) {
 	output, err := g.sh.RunCommand(repo.Path, "git", "remote")
 	if err != nil {
@@ -0,0 +1,44 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitService_GetBranchesInfo(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+	commitFile(t, sh, repo, "main.txt")
+
+	infos, err := gitService.GetBranchesInfo(
+		repo, []Branch{{Name: "main"}, {Name: "work"}, {Name: "main", Remote: "origin"}}, "main",
+	)
+	require.NoError(t, err)
+	if assert.Len(t, infos, 3) {
+		assert.Equal(t, "add main.txt", infos[0].LastCommit.Subject)
+		assert.Equal(t, "origin/main", infos[0].Upstream)
+		assert.Equal(t, 0, infos[0].Ahead)
+		assert.Equal(t, 0, infos[0].Behind)
+		assert.True(t, infos[0].Merged())
+
+		assert.Equal(t, "add work.txt", infos[1].LastCommit.Subject)
+		assert.Equal(t, "", infos[1].Upstream)
+		assert.Equal(t, 1, infos[1].Ahead)
+		assert.Equal(t, 1, infos[1].Behind)
+		assert.False(t, infos[1].Merged())
+
+		assert.Equal(t, "initial", infos[2].LastCommit.Subject)
+		assert.Equal(t, "", infos[2].Upstream)
+		assert.Equal(t, 0, infos[2].Ahead)
+		assert.Equal(t, 1, infos[2].Behind)
+		assert.True(t, infos[2].Merged())
+	}
+
+	_, err = gitService.GetBranchesInfo(repo, []Branch{{Name: "missing"}}, "main")
+	assert.Error(t, err)
+}
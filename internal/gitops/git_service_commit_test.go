@@ -0,0 +1,54 @@
+package gitops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitService_Commit(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+	head := revParse(t, sh, repo, "HEAD")
+
+	result, err := gitService.Commit(repo, CommitOptions{Message: "nothing"})
+	require.NoError(t, err)
+	assert.Equal(t, CommitNothing, result)
+	assert.Equal(t, head, revParse(t, sh, repo, "HEAD"))
+
+	writeFile(t, repo, "file.txt", "changed")
+	result, err = gitService.Commit(
+		repo, CommitOptions{
+			Message:       "# comment\nSubject\n\nBody",
+			StripComments: true,
+			Author:        "Bot <bot@example.com>",
+			CoAuthors:     []string{"Alice <alice@example.com>"},
+			SignOff:       true,
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, CommitCommitted, result)
+	assert.Equal(t, head, revParse(t, sh, repo, "HEAD~1"))
+
+	output, err := sh.RunCommand(repo.Path, "git", "log", "-1", "--format=%an <%ae>%n%B")
+	require.NoError(t, err)
+	assert.Equal(
+		t, "Bot <bot@example.com>\nSubject\n\nBody\n\n"+
+			"Signed-off-by: test <test@example.com>\nCo-authored-by: Alice <alice@example.com>",
+		strings.TrimSpace(output),
+	)
+
+	result, err = gitService.Commit(repo, CommitOptions{Message: "Amended", Amend: true})
+	require.NoError(t, err)
+	assert.Equal(t, CommitAmended, result)
+	assert.Equal(t, head, revParse(t, sh, repo, "HEAD~1"))
+
+	output, err = sh.RunCommand(repo.Path, "git", "log", "-1", "--format=%B")
+	require.NoError(t, err)
+	assert.Equal(t, "Amended", strings.TrimSpace(output))
+}
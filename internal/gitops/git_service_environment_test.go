@@ -0,0 +1,113 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareRemoteRepo creates a bare repository with a single commit in "main" branch
+func prepareRemoteRepo(t *testing.T, sh shell.Shell) string {
+	remoteDir := filepath.Join(t.TempDir(), "remote")
+	seedDir := filepath.Join(t.TempDir(), "seed")
+	require.NoError(t, os.MkdirAll(remoteDir, os.ModePerm))
+	require.NoError(t, os.MkdirAll(seedDir, os.ModePerm))
+
+	runGit(t, sh, remoteDir, "init", "--bare")
+	runGit(t, sh, remoteDir, "symbolic-ref", "HEAD", RefHeadPrefix+"main")
+	runGit(t, sh, seedDir, "init")
+	runGit(t, sh, seedDir, "checkout", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(seedDir, "file.txt"), []byte("content"), os.ModePerm))
+	runGit(t, sh, seedDir, "add", ".")
+	runGit(t, sh, seedDir, "commit", "-m", "initial")
+	runGit(t, sh, seedDir, "remote", "add", "origin", remoteDir)
+	runGit(t, sh, seedDir, "push", "origin", "main")
+
+	return remoteDir
+}
+
+func runGit(t *testing.T, sh shell.Shell, dir string, arguments ...string) {
+	output, err := sh.RunCommand(dir, "git", arguments...)
+	require.NoError(t, err, output)
+}
+
+func TestGitService_Environments(t *testing.T) {
+	for _, environment := range tests.GitEnvironments {
+		t.Run(
+			environment.Name, func(t *testing.T) {
+				environment.Set(t)
+				sh := &shell.NativeShell{}
+				gitService := NewGitService(sh, config.GitBackendExec)
+				remoteDir := prepareRemoteRepo(t, sh)
+				repo := &model.Repo{Name: "repo", Url: remoteDir, Path: filepath.Join(t.TempDir(), "repo")}
+
+				cloneResult, err := gitService.CloneRepo(repo, false)
+				require.NoError(t, err)
+				assert.Equal(t, ClonedSuccessfully, cloneResult)
+
+				status, err := gitService.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(t, RepoStatus{Status: StatusClean, Ref: "main", Upstream: "origin/main"}, status)
+
+				require.NoError(t, os.WriteFile(filepath.Join(repo.Path, "new.txt"), []byte("new"), os.ModePerm))
+				status, err = gitService.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(
+					t, RepoStatus{Status: StatusDirty, Ref: "main", Upstream: "origin/main", Untracked: 1}, status,
+				)
+
+				commitResult, err := gitService.Commit(repo, CommitOptions{Pattern: "new.txt", Message: "add new file"})
+				require.NoError(t, err)
+				assert.Equal(t, CommitCommitted, commitResult)
+
+				commitResult, err = gitService.Commit(repo, CommitOptions{Message: "nothing"})
+				require.NoError(t, err)
+				assert.Equal(t, CommitNothing, commitResult)
+				status, err = gitService.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(t, RepoStatus{Status: StatusClean, Ref: "main", Upstream: "origin/main", Ahead: 1}, status)
+
+				commits, err := gitService.GetUnmergedCommits(repo, Branch{Name: "main"}, "origin/main")
+				require.NoError(t, err)
+				if assert.Len(t, commits, 1) {
+					assert.Equal(t, CommitUser{Name: "test", Email: "test@example.com"}, commits[0].Author)
+				}
+
+				defaultBranch, err := gitService.GetDefaultBranch(repo)
+				require.NoError(t, err)
+				assert.Equal(t, &Branch{Name: "main", Remote: "origin"}, defaultBranch)
+
+				createResult, err := gitService.CreateBranch(repo, "feature")
+				require.NoError(t, err)
+				assert.Equal(t, CreateOk, createResult)
+
+				checkoutResult, err := gitService.Checkout(repo, "feature")
+				require.NoError(t, err)
+				assert.Equal(t, CheckoutOk, checkoutResult)
+
+				branches, err := gitService.GetBranches(repo, config.GitModeAll, ".*")
+				require.NoError(t, err)
+				assert.ElementsMatch(
+					t, []Branch{{Name: "feature"}, {Name: "main"}, {Name: "main", Remote: "origin"}}, branches,
+				)
+
+				err = gitService.Pull(repo)
+				if assert.Error(t, err) {
+					assert.Equal(t, "no remote upstream configured", err.Error())
+				}
+
+				err = gitService.RemoveBranch(repo, Branch{Name: "feature"})
+				if assert.Error(t, err) {
+					assert.Equal(t, "the branch is checked out", err.Error())
+				}
+			},
+		)
+	}
+}
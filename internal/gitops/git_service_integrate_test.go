@@ -0,0 +1,112 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type integrateFunc func(gitService *GitService, repo *model.Repo, ref string) (IntegrateResult, []string, error)
+
+var integrateFunctions = map[string]integrateFunc{
+	"rebase": (*GitService).Rebase,
+	"merge":  (*GitService).Merge,
+}
+
+func writeFile(t *testing.T, repo *model.Repo, fileName string, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Path, fileName), []byte(content), os.ModePerm))
+}
+
+func TestGitService_Integrate(t *testing.T) {
+	for name, integrate := range integrateFunctions {
+		t.Run(
+			name, func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				gitService := NewGitService(sh, config.GitBackendExec)
+
+				runGit(t, sh, repo.Path, "checkout", "work")
+				result, conflicts, err := integrate(&gitService, repo, "main")
+				require.NoError(t, err)
+				assert.Equal(t, IntegrateUpToDate, result)
+				assert.Empty(t, conflicts)
+
+				runGit(t, sh, repo.Path, "checkout", "main")
+				commitFile(t, sh, repo, "main.txt")
+				runGit(t, sh, repo.Path, "checkout", "feature")
+				result, _, err = integrate(&gitService, repo, "main")
+				require.NoError(t, err)
+				assert.Equal(t, IntegrateFastForwarded, result)
+				assert.Equal(t, revParse(t, sh, repo, "main"), revParse(t, sh, repo, "HEAD"))
+
+				runGit(t, sh, repo.Path, "checkout", "work")
+				result, _, err = integrate(&gitService, repo, "main")
+				require.NoError(t, err)
+				if name == "rebase" {
+					assert.Equal(t, IntegrateRebased, result)
+					assert.Equal(t, revParse(t, sh, repo, "main"), revParse(t, sh, repo, "HEAD~1"))
+				} else {
+					assert.Equal(t, IntegrateMerged, result)
+					assert.Equal(t, revParse(t, sh, repo, "main"), revParse(t, sh, repo, "HEAD^2"))
+				}
+			},
+		)
+	}
+}
+
+func TestGitService_IntegrateConflict(t *testing.T) {
+	for name, integrate := range integrateFunctions {
+		t.Run(
+			name, func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				gitService := NewGitService(sh, config.GitBackendExec)
+
+				writeFile(t, repo, "file.txt", "main")
+				runGit(t, sh, repo.Path, "commit", "-am", "change on main")
+				runGit(t, sh, repo.Path, "checkout", "work")
+				writeFile(t, repo, "file.txt", "work")
+				runGit(t, sh, repo.Path, "commit", "-am", "change on work")
+				head := revParse(t, sh, repo, "HEAD")
+
+				result, conflicts, err := integrate(&gitService, repo, "main")
+				require.NoError(t, err)
+				assert.Equal(t, IntegrateConflict, result)
+				assert.Equal(t, []string{"file.txt"}, conflicts)
+
+				assert.Equal(t, head, revParse(t, sh, repo, "HEAD"))
+				status, err := gitService.Status(repo)
+				require.NoError(t, err)
+				assert.Equal(t, RepoStatus{Status: StatusClean, Ref: "work"}, status)
+			},
+		)
+	}
+}
+
+func TestGitService_IntegrateUncommittedChanges(t *testing.T) {
+	for name, integrate := range integrateFunctions {
+		t.Run(
+			name, func(t *testing.T) {
+				sh := &shell.NativeShell{}
+				repo := prepareBackendRepo(t, sh)
+				gitService := NewGitService(sh, config.GitBackendExec)
+
+				runGit(t, sh, repo.Path, "checkout", "feature")
+				commitFile(t, sh, repo, "feature.txt")
+				writeFile(t, repo, "file.txt", "uncommitted")
+
+				result, _, err := integrate(&gitService, repo, "work")
+				if assert.Error(t, err) {
+					assert.Equal(t, "the repository has uncommitted changes", err.Error())
+				}
+				assert.Equal(t, IntegrateError, result)
+			},
+		)
+	}
+}
@@ -0,0 +1,72 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitService_Release(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+	writeFile(t, repo, "package.json", `{"name": "repo", "version": "0.0.0"}`)
+	runGit(t, sh, repo.Path, "add", "package.json")
+	runGit(t, sh, repo.Path, "commit", "-m", "feat: initial version")
+
+	options := ReleaseOptions{TagPrefix: "v", VersionFile: "package.json", VersionPath: "$.version"}
+	result, err := gitService.Release(repo, options)
+	require.NoError(t, err)
+	assert.Equal(
+		t, ReleaseResult{NewVersion: "0.1.0", Reason: "minor: 1 feature", VersionFile: "package.json"}, result,
+	)
+	assert.Equal(t, revParse(t, sh, repo, "HEAD"), revParse(t, sh, repo, "v0.1.0^{commit}"))
+	content, err := os.ReadFile(filepath.Join(repo.Path, "package.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "repo", "version": "0.1.0"}`, string(content))
+
+	result, err = gitService.Release(repo, options)
+	require.NoError(t, err)
+	assert.Equal(t, ReleaseResult{OldVersion: "0.1.0", Reason: "no commits since the latest version"}, result)
+
+	runGit(t, sh, repo.Path, "commit", "--allow-empty", "-m", "fix: bug")
+	runGit(t, sh, repo.Path, "tag", "v2.0.0", "work")
+	result, err = gitService.Release(repo, ReleaseOptions{TagPrefix: "v"})
+	require.NoError(t, err)
+	assert.Equal(t, ReleaseResult{OldVersion: "0.1.0", NewVersion: "0.1.1", Reason: "patch: 1 fix"}, result)
+	assert.Equal(t, revParse(t, sh, repo, "HEAD"), revParse(t, sh, repo, "v0.1.1^{commit}"))
+
+	writeFile(t, repo, "file.txt", "changed")
+	_, err = gitService.Release(repo, options)
+	assert.EqualError(t, err, "the repository has uncommitted changes")
+}
+
+func TestGitService_Release_TagFailure(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+	writeFile(t, repo, "package.json", `{"name": "repo", "version": "0.0.0"}`)
+	runGit(t, sh, repo.Path, "add", "package.json")
+	runGit(t, sh, repo.Path, "commit", "-m", "feat: initial version")
+	head := revParse(t, sh, repo, "HEAD")
+
+	// "x..0.1.0" is not a valid tag name, so the tag can't be created after the version is committed
+	options := ReleaseOptions{TagPrefix: "x..", VersionFile: "package.json", VersionPath: "$.version"}
+	_, err := gitService.Release(repo, options)
+	assert.ErrorContains(t, err, "failed to create tag")
+	assert.Equal(t, head, revParse(t, sh, repo, "HEAD"))
+	content, err := os.ReadFile(filepath.Join(repo.Path, "package.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "repo", "version": "0.0.0"}`, string(content))
+
+	runGit(t, sh, repo.Path, "tag", "v0.1.0", "work")
+	options.TagPrefix = "v"
+	_, err = gitService.Release(repo, options)
+	assert.EqualError(t, err, "tag v0.1.0 already exists")
+	assert.Equal(t, head, revParse(t, sh, repo, "HEAD"))
+}
@@ -0,0 +1,149 @@
+package gitops
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitService_Stash(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+
+	result, err := gitService.StashPush(repo, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, StashNoChanges, result)
+
+	writeFile(t, repo, "untracked.txt", "untracked")
+	result, err = gitService.StashPush(repo, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, StashNoChanges, result)
+
+	result, err = gitService.StashPush(repo, "first", true)
+	require.NoError(t, err)
+	assert.Equal(t, StashCreated, result)
+	assert.NoFileExists(t, filepath.Join(repo.Path, "untracked.txt"))
+
+	writeFile(t, repo, "file.txt", "changed")
+	result, err = gitService.StashPush(repo, "second", false)
+	require.NoError(t, err)
+	assert.Equal(t, StashCreated, result)
+
+	stashes, err := gitService.StashList(repo)
+	require.NoError(t, err)
+	if assert.Len(t, stashes, 2) {
+		assert.Equal(t, "stash@{0}", stashes[0].Ref)
+		assert.Equal(t, "On main: second", stashes[0].Message)
+		assert.Equal(t, "stash@{1}", stashes[1].Ref)
+		assert.Equal(t, "On main: first", stashes[1].Message)
+	}
+
+	result, err = gitService.StashPop(repo, "stash@{5}")
+	require.NoError(t, err)
+	assert.Equal(t, StashNotFound, result)
+
+	result, err = gitService.StashPop(repo, "stash@{1}")
+	require.NoError(t, err)
+	assert.Equal(t, StashRestored, result)
+	assert.FileExists(t, filepath.Join(repo.Path, "untracked.txt"))
+
+	result, err = gitService.StashDrop(repo, "")
+	require.NoError(t, err)
+	assert.Equal(t, StashDropped, result)
+
+	result, err = gitService.StashDrop(repo, "")
+	require.NoError(t, err)
+	assert.Equal(t, StashNotFound, result)
+}
+
+func TestGitService_WithAutostash(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+
+	writeFile(t, repo, "file.txt", "changed")
+	result, err := gitService.WithAutostash(
+		repo, func() error {
+			_, err := gitService.Checkout(repo, "feature")
+			return err
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, StashRestored, result)
+
+	status, err := gitService.Status(repo)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", status.Ref)
+	assert.Equal(t, 1, status.Unstaged)
+
+	operationErr := errors.New("operation failed")
+	result, err = gitService.WithAutostash(
+		repo, func() error {
+			return operationErr
+		},
+	)
+	assert.ErrorIs(t, err, operationErr)
+	assert.Equal(t, StashRestored, result)
+	content, err := os.ReadFile(filepath.Join(repo.Path, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "changed", string(content))
+
+	stashes, err := gitService.StashList(repo)
+	require.NoError(t, err)
+	assert.Empty(t, stashes)
+}
+
+func TestGitService_WithAutostash_Conflict(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+
+	writeFile(t, repo, "file.txt", "stashed")
+	operationErr := errors.New("operation failed")
+	result, err := gitService.WithAutostash(
+		repo, func() error {
+			commitFile(t, sh, repo, "file.txt")
+			return operationErr
+		},
+	)
+	assert.ErrorIs(t, err, operationErr)
+	assert.ErrorContains(t, err, "restored stash conflicts in file.txt")
+	assert.Equal(t, StashConflict, result)
+
+	content, err := os.ReadFile(filepath.Join(repo.Path, "file.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<<<<<<<")
+
+	stashes, err := gitService.StashList(repo)
+	require.NoError(t, err)
+	if assert.Len(t, stashes, 1) {
+		assert.Equal(t, "On main: "+AutostashMessage, stashes[0].Message)
+	}
+}
+
+func TestGitService_StashPop_Conflict(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+
+	writeFile(t, repo, "file.txt", "stashed")
+	result, err := gitService.StashPush(repo, "conflicting", false)
+	require.NoError(t, err)
+	assert.Equal(t, StashCreated, result)
+	commitFile(t, sh, repo, "file.txt")
+
+	result, err = gitService.StashPop(repo, "")
+	assert.ErrorContains(t, err, "restored stash conflicts in file.txt")
+	assert.Equal(t, StashConflict, result)
+
+	stashes, err := gitService.StashList(repo)
+	require.NoError(t, err)
+	assert.Len(t, stashes, 1)
+}
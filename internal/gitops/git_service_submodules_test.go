@@ -0,0 +1,120 @@
+package gitops
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareSubmoduleRepo clones a repository with "libs/lib" submodule added and pushed to the remote one
+func prepareSubmoduleRepo(t *testing.T, sh shell.Shell) *model.Repo {
+	repo := prepareBackendRepo(t, sh)
+	// submodules are cloned from local directories in tests
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	runGit(t, sh, repo.Path, "submodule", "add", prepareRemoteRepo(t, sh), "libs/lib")
+	runGit(t, sh, repo.Path, "commit", "-m", "add lib")
+	runGit(t, sh, repo.Path, "push", "origin", "main")
+
+	return repo
+}
+
+func assertSubmodules(t *testing.T, sh shell.Shell, repo *model.Repo, expected []Submodule) {
+	for _, backend := range testBackends {
+		submodules, err := NewGitBackend(sh, backend).Submodules(repo)
+		if assert.NoError(t, err, backend) {
+			assert.Equal(t, expected, submodules, backend)
+		}
+	}
+}
+
+func TestGitService_Submodules(t *testing.T) {
+	sh := &shell.NativeShell{}
+	origin := prepareSubmoduleRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+	repo := &model.Repo{Name: "clone", Url: origin.Url, Path: filepath.Join(t.TempDir(), "clone")}
+
+	result, err := gitService.CloneRepo(repo, false)
+	require.NoError(t, err)
+	assert.Equal(t, ClonedSuccessfully, result)
+	assert.FileExists(t, filepath.Join(repo.Path, "libs", "lib", "file.txt"))
+	assertSubmodules(t, sh, repo, []Submodule{{Path: "libs/lib", State: SubmoduleUpToDate}})
+
+	commitFile(t, sh, &model.Repo{Path: filepath.Join(repo.Path, "libs", "lib")}, "drift.txt")
+	assertSubmodules(t, sh, repo, []Submodule{{Path: "libs/lib", State: SubmoduleOutdated}})
+	status, err := gitService.Status(repo)
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.Submodules)
+
+	syncResult, err := gitService.SyncSubmodules(repo, false)
+	require.NoError(t, err)
+	assert.Equal(t, SubmodulesSynced, syncResult)
+	assertSubmodules(t, sh, repo, []Submodule{{Path: "libs/lib", State: SubmoduleUpToDate}})
+
+	runGit(t, sh, repo.Path, "submodule", "deinit", "--all")
+	assertSubmodules(t, sh, repo, []Submodule{{Path: "libs/lib", State: SubmoduleNotInitialized}})
+
+	syncResult, err = gitService.SyncSubmodules(repo, false)
+	require.NoError(t, err)
+	assert.Equal(t, SubmodulesSynced, syncResult)
+	status, err = gitService.Status(repo)
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.Submodules)
+
+	runGit(t, sh, origin.Path, "submodule", "add", prepareRemoteRepo(t, sh), "libs/other")
+	runGit(t, sh, origin.Path, "commit", "-m", "add other")
+	runGit(t, sh, origin.Path, "push", "origin", "main")
+	require.NoError(t, gitService.Pull(repo))
+	assert.FileExists(t, filepath.Join(repo.Path, "libs", "other", "file.txt"))
+	assertSubmodules(
+		t, sh, repo, []Submodule{
+			{Path: "libs/lib", State: SubmoduleUpToDate},
+			{Path: "libs/other", State: SubmoduleUpToDate},
+		},
+	)
+}
+
+func TestGitService_SyncSubmodulesWithoutSubmodules(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+
+	result, err := gitService.SyncSubmodules(repo, false)
+	require.NoError(t, err)
+	assert.Equal(t, SubmodulesNone, result)
+	assertSubmodules(t, sh, repo, nil)
+}
+
+func Test_parseSubmodules(t *testing.T) {
+	submodules, err := parseSubmodules(` 0123456789abcdef libs/common (heads/main)
+-0123456789abcdef libs/missing
++0123456789abcdef libs/outdated (v1.0.0-1-g0123456)
+U0123456789abcdef libs/conflict
+ 0123456789abcdef libs/with space (heads/main)
+-0123456789abcdef libs/missing with space
+`)
+	require.NoError(t, err)
+	assert.Equal(
+		t, []Submodule{
+			{Path: "libs/common", State: SubmoduleUpToDate},
+			{Path: "libs/missing", State: SubmoduleNotInitialized},
+			{Path: "libs/outdated", State: SubmoduleOutdated},
+			{Path: "libs/conflict", State: SubmoduleConflict},
+			{Path: "libs/with space", State: SubmoduleUpToDate},
+			{Path: "libs/missing with space", State: SubmoduleNotInitialized},
+		}, submodules,
+	)
+
+	_, err = parseSubmodules("?0123456789abcdef libs/unknown")
+	assert.Error(t, err)
+
+	_, err = parseSubmodules(" 0123456789abcdef")
+	assert.Error(t, err)
+}
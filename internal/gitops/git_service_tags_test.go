@@ -0,0 +1,56 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitService_Tags(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+
+	result, err := gitService.CreateTag(repo, "v1.0.0", "", "release")
+	require.NoError(t, err)
+	assert.Equal(t, TagCreated, result)
+
+	result, err = gitService.CreateTag(repo, "v1.1.0", "work", "")
+	require.NoError(t, err)
+	assert.Equal(t, TagCreated, result)
+	assert.Equal(t, revParse(t, sh, repo, "work"), revParse(t, sh, repo, "v1.1.0"))
+
+	_, err = gitService.CreateTag(repo, "v1.0.0", "", "")
+	assert.EqualError(t, err, "tag already exists")
+
+	tags, err := gitService.GetTags(repo, config.GitModeAll, "v1.*")
+	require.NoError(t, err)
+	assert.Equal(t, []Tag{{Name: "v1.0.0"}, {Name: "v1.1.0"}}, tags)
+
+	result, err = gitService.PushTag(repo, Tag{Name: "v1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, TagPushed, result)
+
+	tags, err = gitService.GetTags(repo, config.GitModeRemote, ".*")
+	require.NoError(t, err)
+	assert.Equal(t, []Tag{{Name: "v1.0.0", Remote: "origin"}}, tags)
+
+	tags, err = gitService.GetTags(repo, config.GitModeAll, "origin/v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []Tag{{Name: "v1.0.0", Remote: "origin"}}, tags)
+
+	result, err = gitService.RemoveTag(repo, Tag{Name: "v1.0.0", Remote: "origin"})
+	require.NoError(t, err)
+	assert.Equal(t, TagRemoved, result)
+
+	result, err = gitService.RemoveTag(repo, Tag{Name: "v1.1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, TagRemoved, result)
+
+	tags, err = gitService.GetTags(repo, config.GitModeAll, ".*")
+	require.NoError(t, err)
+	assert.Equal(t, []Tag{{Name: "v1.0.0"}}, tags)
+}
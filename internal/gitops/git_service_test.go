@@ -4,6 +4,7 @@ import (
 	"github.com/mih-kopylov/bulker/internal/shell"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_parseBranches(t *testing.T) {
@@ -48,8 +49,8 @@ refs/remotes/origin/master`,
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
-				gitService := NewGitService(&shell.NativeShell{})
-				got, err := gitService.parseBranches(tt.consoleOutputString)
+				backend := &execBackend{sh: &shell.NativeShell{}}
+				got, err := backend.parseBranches(tt.consoleOutputString)
 				if (err != nil) != tt.wantErr {
 					t.Errorf("parseBranches() error = %v, wantErr %v", err, tt.wantErr)
 					return
@@ -61,3 +62,146 @@ refs/remotes/origin/master`,
 		)
 	}
 }
+
+func Test_parseStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusOutput string
+		want         RepoStatus
+		wantErr      bool
+	}{
+		{
+			name: "clean branch without upstream",
+			statusOutput: `# branch.oid 0123456789abcdef
+# branch.head master`,
+			want: RepoStatus{Status: StatusClean, Ref: "master"},
+		},
+		{
+			name: "clean branch ahead and behind upstream",
+			statusOutput: `# branch.oid 0123456789abcdef
+# branch.head feature/a
+# branch.upstream origin/feature/a
+# branch.ab +2 -3`,
+			want: RepoStatus{Status: StatusClean, Ref: "feature/a", Upstream: "origin/feature/a", Ahead: 2, Behind: 3},
+		},
+		{
+			name: "detached head",
+			statusOutput: `# branch.oid 0123456789abcdef
+# branch.head (detached)`,
+			want: RepoStatus{Status: StatusClean, Ref: "0123456", Detached: true},
+		},
+		{
+			name: "changed files",
+			statusOutput: `# branch.oid 0123456789abcdef
+# branch.head master
+1 M. N... 100644 100644 100644 0123 4567 staged.txt
+1 .M N... 100644 100644 100644 0123 4567 unstaged.txt
+1 MM N... 100644 100644 100644 0123 4567 both.txt
+2 R. N... 100644 100644 100644 0123 4567 R100 renamed.txt	original.txt
+u UU N... 100644 100644 100644 100644 0123 4567 89ab conflict.txt
+? untracked.txt
+! ignored.txt`,
+			want: RepoStatus{Status: StatusDirty, Ref: "master", Staged: 3, Unstaged: 3, Untracked: 1},
+		},
+		{
+			name:         "untracked files only",
+			statusOutput: "# branch.oid (initial)\n# branch.head master\n? new.txt",
+			want:         RepoStatus{Status: StatusDirty, Ref: "master", Untracked: 1},
+		},
+		{
+			name:         "no head",
+			statusOutput: `? new.txt`,
+			wantErr:      true,
+		},
+		{
+			name:         "malformed ahead and behind",
+			statusOutput: "# branch.head master\n# branch.ab 2 3",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				backend := &execBackend{sh: &shell.NativeShell{}}
+				got, err := backend.parseStatus(tt.statusOutput)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("parseStatus() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("parseStatus() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_parseCommits(t *testing.T) {
+	backend := &execBackend{sh: &shell.NativeShell{}}
+
+	got, err := backend.parseCommits(
+		"abc\x1fAuthor Name\x1fauthor@example.com\x1f2024-01-02T03:04:05+03:00\x1f" +
+			"Committer Name\x1fcommitter@example.com\x1f2024-01-03T03:04:05Z\x1fSubject\x1fBody\n\x1e\n",
+	)
+	if err != nil {
+		t.Fatalf("parseCommits() error = %v", err)
+	}
+	want := []Commit{
+		{
+			Id:         "abc",
+			AuthorDate: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3*60*60)),
+			Author:     CommitUser{Name: "Author Name", Email: "author@example.com"},
+			CommitDate: time.Date(2024, 1, 3, 3, 4, 5, 0, time.UTC),
+			Committer:  CommitUser{Name: "Committer Name", Email: "committer@example.com"},
+			Subject:    "Subject",
+			Body:       "Body",
+		},
+	}
+	if len(got) != 1 || got[0].Id != want[0].Id || !got[0].AuthorDate.Equal(want[0].AuthorDate) ||
+		!got[0].CommitDate.Equal(want[0].CommitDate) || got[0].Author != want[0].Author ||
+		got[0].Committer != want[0].Committer || got[0].Subject != want[0].Subject || got[0].Body != want[0].Body {
+		t.Errorf("parseCommits() = %v, want %v", got, want)
+	}
+
+	got, err = backend.parseCommits("")
+	if err != nil || len(got) != 0 {
+		t.Errorf("parseCommits() of empty output = %v, %v, want no commits", got, err)
+	}
+
+	_, err = backend.parseCommits("abc\x1fmalformed\x1e")
+	if err == nil {
+		t.Errorf("parseCommits() of malformed output expected to fail")
+	}
+}
+
+func TestParseRefRange(t *testing.T) {
+	tests := []struct {
+		refRange    string
+		wantRef     string
+		wantExclude string
+	}{
+		{refRange: "", wantRef: "HEAD", wantExclude: ""},
+		{refRange: "main", wantRef: "main", wantExclude: ""},
+		{refRange: "v1.0.0..main", wantRef: "main", wantExclude: "v1.0.0"},
+		{refRange: "v1.0.0..", wantRef: "HEAD", wantExclude: "v1.0.0"},
+		{refRange: "..main", wantRef: "main", wantExclude: "HEAD"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.refRange, func(t *testing.T) {
+				ref, exclude, err := ParseRefRange(tt.refRange)
+				if err != nil {
+					t.Errorf("ParseRefRange() error = %v", err)
+				}
+				if ref != tt.wantRef || exclude != tt.wantExclude {
+					t.Errorf("ParseRefRange() = %v, %v, want %v, %v", ref, exclude, tt.wantRef, tt.wantExclude)
+				}
+			},
+		)
+	}
+
+	_, _, err := ParseRefRange("main...feature")
+	if err == nil {
+		t.Errorf("ParseRefRange() expected error for three-dot range")
+	}
+}
@@ -0,0 +1,119 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitService_Worktrees(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+	reviewPath := filepath.Join(t.TempDir(), "review")
+	freshPath := filepath.Join(t.TempDir(), "fresh")
+
+	result, err := gitService.AddWorktree(repo, reviewPath, "work", false, "")
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeAdded, result)
+	assert.FileExists(t, filepath.Join(reviewPath, "work.txt"))
+
+	result, err = gitService.AddWorktree(repo, reviewPath, "work", false, "")
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeExists, result)
+
+	result, err = gitService.AddWorktree(repo, freshPath, "fresh", true, "feature")
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeAdded, result)
+
+	result, err = gitService.AddWorktree(repo, filepath.Join(t.TempDir(), "busy"), "work", false, "")
+	assert.Error(t, err)
+	assert.Equal(t, WorktreeError, result)
+
+	worktrees, err := gitService.GetWorktrees(repo)
+	require.NoError(t, err)
+	if assert.Len(t, worktrees, 3) {
+		assert.Equal(t, Worktree{Path: repo.Path, Head: revParse(t, sh, repo, "main"), Branch: "main", Main: true}, worktrees[0])
+		assert.Equal(t, Worktree{Path: reviewPath, Head: revParse(t, sh, repo, "work"), Branch: "work"}, worktrees[1])
+		assert.Equal(t, Worktree{Path: freshPath, Head: revParse(t, sh, repo, "feature"), Branch: "fresh"}, worktrees[2])
+	}
+
+	writeFile(t, &model.Repo{Path: reviewPath}, "work.txt", "changed")
+	result, err = gitService.RemoveWorktree(repo, reviewPath, false)
+	assert.Error(t, err)
+	assert.Equal(t, WorktreeError, result)
+
+	result, err = gitService.RemoveWorktree(repo, reviewPath, true)
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeRemoved, result)
+	assert.NoDirExists(t, reviewPath)
+
+	result, err = gitService.RemoveWorktree(repo, reviewPath, false)
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeNotFound, result)
+
+	result, err = gitService.RemoveWorktree(repo, repo.Path, true)
+	assert.Error(t, err)
+	assert.Equal(t, WorktreeError, result)
+}
+
+func TestGitService_Worktrees_SymbolicLink(t *testing.T) {
+	sh := &shell.NativeShell{}
+	repo := prepareBackendRepo(t, sh)
+	gitService := NewGitService(sh, config.GitBackendExec)
+	worktreesDir := t.TempDir()
+	linkDir := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(worktreesDir, linkDir))
+
+	result, err := gitService.AddWorktree(repo, filepath.Join(linkDir, "review"), "work", false, "")
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeAdded, result)
+
+	result, err = gitService.AddWorktree(repo, filepath.Join(worktreesDir, "review"), "work", false, "")
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeExists, result)
+
+	result, err = gitService.AddWorktree(repo, filepath.Join(linkDir, "review"), "work", false, "")
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeExists, result)
+
+	result, err = gitService.RemoveWorktree(repo, filepath.Join(linkDir, "review"), false)
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeRemoved, result)
+	assert.NoDirExists(t, filepath.Join(worktreesDir, "review"))
+}
+
+func Test_normalizeWorktreePath(t *testing.T) {
+	assert.Equal(t, "/repos/.worktrees/repo/review", normalizeWorktreePath("/repos/repo", "../.worktrees/repo/review/"))
+	assert.Equal(t, "/repos/.worktrees/repo/review", normalizeWorktreePath("/repos/repo", "/repos/.worktrees/repo/review"))
+}
+
+func Test_parseWorktrees(t *testing.T) {
+	worktrees, err := parseWorktrees(`worktree /repos/repo
+HEAD 1234567890abcdef1234567890abcdef12345678
+branch refs/heads/main
+
+worktree /repos/.worktrees/repo/review
+HEAD abcdef1234567890abcdef1234567890abcdef12
+detached
+locked
+
+`)
+	require.NoError(t, err)
+	assert.Equal(
+		t, []Worktree{
+			{Path: "/repos/repo", Head: "1234567890abcdef1234567890abcdef12345678", Branch: "main", Main: true},
+			{Path: "/repos/.worktrees/repo/review", Head: "abcdef1234567890abcdef1234567890abcdef12", Locked: true},
+		}, worktrees,
+	)
+	assert.Equal(t, "/repos/.worktrees/repo/review: detached at abcdef1", worktrees[1].String())
+
+	_, err = parseWorktrees("HEAD 1234567")
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+package gitops
+
+// IntegrateResult describes how the current branch was updated with changes of another ref
+type IntegrateResult string
+
+const (
+	IntegrateUpToDate      IntegrateResult = "up-to-date"
+	IntegrateFastForwarded IntegrateResult = "fast-forwarded"
+	IntegrateRebased       IntegrateResult = "rebased"
+	IntegrateMerged        IntegrateResult = "merged"
+	IntegrateConflict      IntegrateResult = "conflict"
+	IntegrateError         IntegrateResult = "error"
+)
+
+func (r *IntegrateResult) String() string {
+	return string(*r)
+}
@@ -0,0 +1,692 @@
+package gitops
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/samber/lo"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// nativeBackend reads repositories in process with go-git, without spawning git processes
+type nativeBackend struct {
+	// ancestorsCache keeps ancestors of the commits branches were compared with, so that the history is walked
+	// once per ref. Commits are addressed by content, so the cache is valid for any repository
+	ancestorsCache map[plumbing.Hash]map[plumbing.Hash]struct{}
+	mutex          sync.Mutex
+}
+
+func (b *nativeBackend) Status(repo *model.Repo) (RepoStatus, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return RepoStatus{}, err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return RepoStatus{}, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	worktreeStatus, err := worktree.Status()
+	if err != nil {
+		return RepoStatus{}, fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	result := RepoStatus{Status: StatusClean}
+	for _, fileStatus := range worktreeStatus {
+		switch {
+		case fileStatus.Worktree == git.Untracked:
+			result.Untracked++
+		case fileStatus.Staging == git.UpdatedButUnmerged || fileStatus.Worktree == git.UpdatedButUnmerged:
+			result.Unstaged++
+		default:
+			if fileStatus.Staging != git.Unmodified {
+				result.Staged++
+			}
+			if fileStatus.Worktree != git.Unmodified {
+				result.Unstaged++
+			}
+		}
+	}
+	if result.Staged > 0 || result.Unstaged > 0 || result.Untracked > 0 {
+		result.Status = StatusDirty
+	}
+
+	head, err := repository.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return RepoStatus{}, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	if head.Type() != plumbing.SymbolicReference {
+		result.Detached = true
+		result.Ref = head.Hash().String()[:shortCommitIdLength]
+		return result, nil
+	}
+
+	branchName := head.Target().Short()
+	result.Ref = branchName
+
+	upstreamName, upstreamReference, err := b.upstream(repository, branchName)
+	if err != nil {
+		return RepoStatus{}, err
+	}
+	result.Upstream = upstreamName
+	if upstreamName == "" {
+		return result, nil
+	}
+
+	headHash, err := b.resolveHash(repository, head.Target())
+	if err != nil {
+		return RepoStatus{}, err
+	}
+	upstreamHash, err := b.resolveHash(repository, upstreamReference)
+	if err != nil {
+		return RepoStatus{}, err
+	}
+	if headHash.IsZero() || upstreamHash.IsZero() {
+		// either the branch doesn't have commits yet, or the upstream branch is gone
+		return result, nil
+	}
+
+	ahead, behind, err := b.leftRight(repository, headHash, upstreamHash)
+	if err != nil {
+		return RepoStatus{}, err
+	}
+	result.Ahead = len(ahead)
+	result.Behind = len(behind)
+
+	return result, nil
+}
+
+func (b *nativeBackend) Branches(repo *model.Repo) ([]Branch, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	branchReferences, err := b.branchReferences(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.toBranches(branchReferences)
+}
+
+func (b *nativeBackend) MergedBranches(repo *model.Repo, ref string) ([]Branch, error) {
+	return b.filterMerged(repo, ref, true)
+}
+
+func (b *nativeBackend) UnmergedBranches(repo *model.Repo, ref string) ([]Branch, error) {
+	return b.filterMerged(repo, ref, false)
+}
+
+func (b *nativeBackend) Tags(repo *model.Repo) ([]Tag, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIterator, err := repository.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	var result []Tag
+	err = tagIterator.ForEach(
+		func(reference *plumbing.Reference) error {
+			tag, err := parseTag(reference.Name().String(), "")
+			if err != nil {
+				return err
+			}
+			result = append(result, *tag)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	slices.SortFunc(
+		result, func(a Tag, b Tag) int {
+			return strings.Compare(a.Name, b.Name)
+		},
+	)
+
+	return result, nil
+}
+
+func (b *nativeBackend) Upstreams(repo *model.Repo) (map[string]string, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	branchIterator, err := repository.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %w", err)
+	}
+
+	result := map[string]string{}
+	err = branchIterator.ForEach(
+		func(reference *plumbing.Reference) error {
+			upstreamName, _, err := b.upstream(repository, reference.Name().Short())
+			if err != nil {
+				return err
+			}
+			if upstreamName != "" {
+				result[reference.Name().Short()] = upstreamName
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upstream branches: %w", err)
+	}
+
+	return result, nil
+}
+
+func (b *nativeBackend) BranchCommits(repo *model.Repo) (map[string]Commit, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	branchReferences, err := b.branchReferences(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]Commit{}
+	for _, reference := range branchReferences {
+		if reference.Type() != plumbing.HashReference {
+			continue
+		}
+
+		commit, err := repository.CommitObject(reference.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to find commit of %v: %w", reference.Name(), err)
+		}
+		result[reference.Name().String()] = b.toCommit(commit)
+	}
+
+	return result, nil
+}
+
+func (b *nativeBackend) AheadBehind(repo *model.Repo, branch string, ref string) (int, int, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	branchHash, err := repository.ResolveRevision(plumbing.Revision(branch))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve %v: %w", branch, err)
+	}
+	refHash, err := repository.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve %v: %w", ref, err)
+	}
+
+	ahead, behind, err := b.leftRight(repository, *branchHash, *refHash)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(ahead), len(behind), nil
+}
+
+func (b *nativeBackend) Submodules(repo *model.Repo) ([]Submodule, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submodules: %w", err)
+	}
+
+	var result []Submodule
+	for _, submodule := range submodules {
+		status, err := submodule.Status()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get submodule status: %w", err)
+		}
+
+		state := SubmoduleUpToDate
+		if status.Current.IsZero() {
+			state = SubmoduleNotInitialized
+		} else if !status.IsClean() {
+			state = SubmoduleOutdated
+		}
+		result = append(result, Submodule{Path: status.Path, State: state})
+	}
+
+	slices.SortFunc(
+		result, func(a Submodule, b Submodule) int {
+			return strings.Compare(a.Path, b.Path)
+		},
+	)
+
+	return result, nil
+}
+
+func (b *nativeBackend) Log(repo *model.Repo, options LogOptions) ([]Commit, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	refHash, err := repository.ResolveRevision(plumbing.Revision(options.Ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %v: %w", options.Ref, err)
+	}
+
+	// included commits are the ones reachable from the ref but not from the excluded one,
+	// the walk stops at the oldest of them
+	var included map[plumbing.Hash]struct{}
+	var oldestIncluded time.Time
+	if options.Exclude != "" {
+		excludeHash, err := repository.ResolveRevision(plumbing.Revision(options.Exclude))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %v: %w", options.Exclude, err)
+		}
+
+		included, _, err = b.leftRight(repository, *refHash, *excludeHash)
+		if err != nil {
+			return nil, err
+		}
+		if len(included) == 0 {
+			return nil, nil
+		}
+
+		oldestIncluded, err = b.oldestCommitTime(repository, included)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var authors []*regexp.Regexp
+	for _, author := range options.Authors {
+		reg, err := regexp.Compile(author)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, reg)
+	}
+
+	logOptions := &git.LogOptions{From: *refHash, Order: git.LogOrderCommitterTime}
+	if !options.Since.IsZero() {
+		logOptions.Since = &options.Since
+	}
+	if !options.Until.IsZero() {
+		logOptions.Until = &options.Until
+	}
+	if len(options.Paths) > 0 {
+		logOptions.PathFilter = func(path string) bool {
+			return lo.SomeBy(
+				options.Paths, func(item string) bool {
+					item = strings.TrimSuffix(item, "/")
+					return path == item || strings.HasPrefix(path, item+"/")
+				},
+			)
+		}
+	}
+
+	commitIterator, err := repository.Log(logOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	var result []Commit
+	err = commitIterator.ForEach(
+		func(commit *object.Commit) error {
+			if included != nil {
+				if _, exists := included[commit.Hash]; !exists {
+					if commit.Committer.When.Before(oldestIncluded) {
+						return storer.ErrStop
+					}
+					return nil
+				}
+			}
+
+			author := CommitUser{Name: commit.Author.Name, Email: commit.Author.Email}
+			if len(authors) > 0 && !lo.SomeBy(
+				authors, func(item *regexp.Regexp) bool {
+					return item.MatchString(author.String())
+				},
+			) {
+				return nil
+			}
+
+			result = append(result, b.toCommit(commit))
+			if options.MaxCount > 0 && len(result) >= options.MaxCount {
+				return storer.ErrStop
+			}
+			return nil
+		},
+	)
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	return result, nil
+}
+
+func (b *nativeBackend) open(repo *model.Repo) (*git.Repository, error) {
+	// the common directory is required to open linked worktrees
+	repository, err := git.PlainOpenWithOptions(repo.Path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository %v: %w", repo.Path, err)
+	}
+
+	return repository, nil
+}
+
+// upstream returns the short name and the reference of the branch the local one tracks.
+// The name is empty if there is no upstream
+func (b *nativeBackend) upstream(repository *git.Repository, branchName string) (
+	string, plumbing.ReferenceName, error,
+) {
+	repositoryConfig, err := repository.Config()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read repository config: %w", err)
+	}
+
+	branchConfig, exists := repositoryConfig.Branches[branchName]
+	if !exists || branchConfig.Remote == "" || branchConfig.Merge == "" {
+		return "", "", nil
+	}
+
+	if branchConfig.Remote == "." {
+		return branchConfig.Merge.Short(), branchConfig.Merge, nil
+	}
+
+	mergeBranchName := strings.TrimPrefix(branchConfig.Merge.String(), RefHeadPrefix)
+	return branchConfig.Remote + "/" + mergeBranchName,
+		plumbing.NewRemoteReferenceName(branchConfig.Remote, mergeBranchName), nil
+}
+
+// resolveHash returns the commit the reference points to, or a zero hash if the reference doesn't exist
+func (b *nativeBackend) resolveHash(repository *git.Repository, name plumbing.ReferenceName) (plumbing.Hash, error) {
+	reference, err := repository.Reference(name, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to resolve %v: %w", name, err)
+	}
+
+	return reference.Hash(), nil
+}
+
+// ancestors returns hashes of the commit and all its ancestors.
+// The result is shared between calls and must not be modified
+func (b *nativeBackend) ancestors(repository *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]struct{}, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if cached, ok := b.ancestorsCache[hash]; ok {
+		return cached, nil
+	}
+
+	commit, err := repository.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find commit %v: %w", hash, err)
+	}
+
+	result := map[plumbing.Hash]struct{}{}
+	err = object.NewCommitPreorderIter(commit, nil, nil).ForEach(
+		func(commit *object.Commit) error {
+			result[commit.Hash] = struct{}{}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk commits of %v: %w", hash, err)
+	}
+
+	if b.ancestorsCache == nil {
+		b.ancestorsCache = map[plumbing.Hash]map[plumbing.Hash]struct{}{}
+	}
+	b.ancestorsCache[hash] = result
+	return result, nil
+}
+
+const (
+	sideLeft  = 1
+	sideRight = 2
+	sideBoth  = sideLeft | sideRight
+)
+
+// commitQueue is a priority queue of commits, the most recently committed first
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int {
+	return len(q)
+}
+
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+
+func (q commitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *commitQueue) Push(x any) {
+	*q = append(*q, x.(*object.Commit))
+}
+
+func (q *commitQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// leftRight returns commits reachable from the left commit only and from the right commit only,
+// the same as "git rev-list --left-right left...right".
+// Commits are painted with the sides they are reachable from, from the most recent to the oldest ones.
+// The walk stops when all the queued commits are reachable from both sides, that is around the merge base.
+// A commit is queued once at a time, it passes the sides painted while it waits to its parents when it's taken
+func (b *nativeBackend) leftRight(repository *git.Repository, left plumbing.Hash, right plumbing.Hash) (
+	map[plumbing.Hash]struct{}, map[plumbing.Hash]struct{}, error,
+) {
+	sides := map[plumbing.Hash]int{}
+	queued := map[plumbing.Hash]bool{}
+	// unsettled is the number of queued commits that are not reachable from both sides yet
+	unsettled := 0
+	queue := &commitQueue{}
+	paint := func(hash plumbing.Hash, side int) error {
+		if sides[hash]&side == side {
+			return nil
+		}
+
+		sides[hash] |= side
+		if queued[hash] {
+			if sides[hash] == sideBoth {
+				unsettled--
+			}
+			return nil
+		}
+
+		commit, err := repository.CommitObject(hash)
+		if err != nil {
+			return fmt.Errorf("failed to find commit %v: %w", hash, err)
+		}
+
+		heap.Push(queue, commit)
+		queued[hash] = true
+		if sides[hash] != sideBoth {
+			unsettled++
+		}
+		return nil
+	}
+
+	err := paint(left, sideLeft)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = paint(right, sideRight)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for unsettled > 0 {
+		commit := heap.Pop(queue).(*object.Commit)
+		queued[commit.Hash] = false
+		if sides[commit.Hash] != sideBoth {
+			unsettled--
+		}
+		for _, parent := range commit.ParentHashes {
+			err := paint(parent, sides[commit.Hash])
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	leftOnly := map[plumbing.Hash]struct{}{}
+	rightOnly := map[plumbing.Hash]struct{}{}
+	for hash, side := range sides {
+		switch side {
+		case sideLeft:
+			leftOnly[hash] = struct{}{}
+		case sideRight:
+			rightOnly[hash] = struct{}{}
+		}
+	}
+
+	return leftOnly, rightOnly, nil
+}
+
+// oldestCommitTime returns the earliest commit time of the commits, zero time if there are none
+func (b *nativeBackend) oldestCommitTime(repository *git.Repository, hashes map[plumbing.Hash]struct{}) (
+	time.Time, error,
+) {
+	var result time.Time
+	for hash := range hashes {
+		commit, err := repository.CommitObject(hash)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to find commit %v: %w", hash, err)
+		}
+
+		if result.IsZero() || commit.Committer.When.Before(result) {
+			result = commit.Committer.When
+		}
+	}
+
+	return result, nil
+}
+
+func (b *nativeBackend) toCommit(commit *object.Commit) Commit {
+	subject, body := parseCommitMessage(commit.Message)
+	return Commit{
+		Id:         commit.Hash.String(),
+		AuthorDate: commit.Author.When,
+		Author:     CommitUser{Name: commit.Author.Name, Email: commit.Author.Email},
+		CommitDate: commit.Committer.When,
+		Committer:  CommitUser{Name: commit.Committer.Name, Email: commit.Committer.Email},
+		Subject:    subject,
+		Body:       body,
+	}
+}
+
+// branchReferences returns local and remote branch references sorted by name, the same way git does
+func (b *nativeBackend) branchReferences(repository *git.Repository) ([]*plumbing.Reference, error) {
+	referenceIterator, err := repository.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %w", err)
+	}
+
+	var result []*plumbing.Reference
+	err = referenceIterator.ForEach(
+		func(reference *plumbing.Reference) error {
+			if reference.Name().IsBranch() || reference.Name().IsRemote() {
+				result = append(result, reference)
+			}
+			return nil
+		},
+	)
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return nil, fmt.Errorf("failed to get branches: %w", err)
+	}
+
+	slices.SortFunc(
+		result, func(a *plumbing.Reference, b *plumbing.Reference) int {
+			return strings.Compare(a.Name().String(), b.Name().String())
+		},
+	)
+
+	return result, nil
+}
+
+func (b *nativeBackend) toBranches(references []*plumbing.Reference) ([]Branch, error) {
+	var result []Branch
+	for _, reference := range references {
+		branch, err := parseBranch(reference.Name().String())
+		if err != nil {
+			return nil, err
+		}
+
+		if branch.Name == Head {
+			continue
+		}
+		result = append(result, *branch)
+	}
+
+	return result, nil
+}
+
+func (b *nativeBackend) filterMerged(repo *model.Repo, ref string, merged bool) ([]Branch, error) {
+	repository, err := b.open(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	refHash, err := repository.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %v: %w", ref, err)
+	}
+
+	refAncestors, err := b.ancestors(repository, *refHash)
+	if err != nil {
+		return nil, err
+	}
+
+	branchReferences, err := b.branchReferences(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []*plumbing.Reference
+	for _, reference := range branchReferences {
+		branchHash, err := b.resolveHash(repository, reference.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		_, isMerged := refAncestors[branchHash]
+		if isMerged == merged {
+			filtered = append(filtered, reference)
+		}
+	}
+
+	return b.toBranches(filtered)
+}
@@ -0,0 +1,152 @@
+package gitops
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/pkg/props"
+	"github.com/pkg/errors"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+type ReleaseOptions struct {
+	// TagPrefix is the prefix of the version tags, like "v"
+	TagPrefix string
+	// VersionFile is the file in the repository to write the new version to. Ignored if empty
+	VersionFile string
+	// VersionPath is the property path of the version in VersionFile
+	VersionPath string
+}
+
+type ReleaseResult struct {
+	OldVersion string
+	NewVersion string
+	Reason     string
+	// VersionFile is the file the new version is written to. Empty if the version is only tagged
+	VersionFile string
+}
+
+// Release finds the latest version tag reachable from the current commit, calculates the next version from
+// the Conventional Commits made since then and creates an annotated tag of it.
+// If the version file is set, the new version is written to it and committed before tagging.
+// The commit is reset if the tag can't be created
+func (g *GitService) Release(repo *model.Repo, options ReleaseOptions) (ReleaseResult, error) {
+	if options.VersionFile != "" {
+		status, err := g.Status(repo)
+		if err != nil {
+			return ReleaseResult{}, err
+		}
+		if status.Staged > 0 || status.Unstaged > 0 {
+			return ReleaseResult{}, errors.New("the repository has uncommitted changes")
+		}
+	}
+
+	oldVersion, oldTag, err := g.getLatestVersion(repo, options.TagPrefix)
+	if err != nil {
+		return ReleaseResult{}, err
+	}
+
+	result := ReleaseResult{}
+	logOptions := LogOptions{Ref: Head}
+	if oldTag != "" {
+		result.OldVersion = oldVersion.String()
+		logOptions.Exclude = RefTagPrefix + oldTag
+	}
+
+	commits, err := g.GetCommits(repo, logOptions)
+	if err != nil {
+		return ReleaseResult{}, err
+	}
+
+	bump, reason := GetVersionBump(commits)
+	result.Reason = reason
+	if bump == BumpNone {
+		return result, nil
+	}
+
+	newVersion := oldVersion.Bump(bump)
+	result.NewVersion = newVersion.String()
+	newTag := options.TagPrefix + newVersion.String()
+	message := "Release " + newTag
+
+	existingTags, err := g.GetTags(repo, config.GitModeLocal, regexp.QuoteMeta(newTag))
+	if err != nil {
+		return ReleaseResult{}, err
+	}
+	if len(existingTags) > 0 {
+		return ReleaseResult{}, fmt.Errorf("tag %v already exists", newTag)
+	}
+
+	commitResult := CommitNothing
+	if options.VersionFile != "" {
+		err = props.SetPropertyInFile(
+			filepath.Join(repo.Path, options.VersionFile), options.VersionPath, newVersion.String(),
+		)
+		if err != nil {
+			return ReleaseResult{}, fmt.Errorf("failed to write version: %w", err)
+		}
+
+		commitResult, err = g.Commit(repo, CommitOptions{Pattern: options.VersionFile, Message: message})
+		if err != nil {
+			return ReleaseResult{}, err
+		}
+		result.VersionFile = options.VersionFile
+	}
+
+	_, err = g.CreateTag(repo, newTag, "", message)
+	if err != nil {
+		if commitResult == CommitCommitted {
+			output, resetErr := g.sh.RunCommand(repo.Path, "git", "reset", "--keep", "HEAD~1")
+			if resetErr != nil {
+				return ReleaseResult{}, fmt.Errorf(
+					"%w, failed to reset the version commit: %v, %w", err, output, resetErr,
+				)
+			}
+		}
+		return ReleaseResult{}, err
+	}
+
+	return result, nil
+}
+
+// getLatestVersion returns the highest version among the tags reachable from the current commit and its tag.
+// The tag is empty if there are no version tags
+func (g *GitService) getLatestVersion(repo *model.Repo, tagPrefix string) (Version, string, error) {
+	tags, err := g.GetTags(repo, config.GitModeLocal, ".*")
+	if err != nil {
+		return Version{}, "", err
+	}
+
+	type versionTag struct {
+		version Version
+		tag     string
+	}
+
+	var versionTags []versionTag
+	for _, tag := range tags {
+		version, ok := ParseVersion(tag.Name, tagPrefix)
+		if ok {
+			versionTags = append(versionTags, versionTag{version, tag.Name})
+		}
+	}
+
+	slices.SortFunc(
+		versionTags, func(a versionTag, b versionTag) int {
+			return b.version.Compare(a.version)
+		},
+	)
+
+	for _, versionTag := range versionTags {
+		reachable, err := g.isAncestor(repo, RefTagPrefix+versionTag.tag, Head)
+		if err != nil {
+			return Version{}, "", err
+		}
+		if reachable {
+			return versionTag.version, versionTag.tag, nil
+		}
+	}
+
+	return Version{}, "", nil
+}
@@ -0,0 +1,50 @@
+package gitops
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+const (
+	// AutostashMessage is the message of the stash entries that keep changes during an operation
+	AutostashMessage = "bulker autostash"
+	// stashFormat prints stash entry fields in the same way as commitFormat does
+	stashFormat = "%gd%x1f%gs%x1f%cI%x1e"
+)
+
+type Stash struct {
+	Ref     string
+	Message string
+	Date    time.Time
+}
+
+func (s *Stash) String() string {
+	return fmt.Sprintf("%v: %v", s.Ref, s.Message)
+}
+
+// parseStashes parses `git stash list` output printed with stashFormat
+func parseStashes(consoleOutputString string) ([]Stash, error) {
+	var stashes []Stash
+	for _, record := range strings.Split(consoleOutputString, commitRecordSeparator) {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, commitFieldSeparator)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("failed to parse stash from console output: %v", record)
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse stash date")
+		}
+
+		stashes = append(stashes, Stash{Ref: fields[0], Message: fields[1], Date: date})
+	}
+
+	return stashes, nil
+}
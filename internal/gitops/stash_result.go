@@ -0,0 +1,17 @@
+package gitops
+
+type StashResult string
+
+const (
+	StashCreated   StashResult = "created"
+	StashRestored  StashResult = "restored"
+	StashDropped   StashResult = "dropped"
+	StashNoChanges StashResult = "no changes"
+	StashNotFound  StashResult = "not found"
+	StashConflict  StashResult = "conflict"
+	StashError     StashResult = "error"
+)
+
+func (r *StashResult) String() string {
+	return string(*r)
+}
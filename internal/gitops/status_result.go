@@ -12,3 +12,26 @@ const (
 func (r *StatusResult) String() string {
 	return string(*r)
 }
+
+// RepoStatus describes the working tree of a repository and how its branch tracks the upstream one
+type RepoStatus struct {
+	Status StatusResult
+	// Ref is the current branch name, or the abbreviated commit hash in detached HEAD state
+	Ref string
+	// Detached is true if HEAD points to a commit rather than a branch
+	Detached bool
+	// Upstream is the branch the current one tracks. Empty if there is no upstream
+	Upstream string
+	// Ahead is the number of commits that are not pushed to the upstream
+	Ahead int
+	// Behind is the number of upstream commits that are not pulled yet
+	Behind int
+	// Staged is the number of files with changes added to the index
+	Staged int
+	// Unstaged is the number of tracked files with changes not added to the index, including conflicting ones
+	Unstaged int
+	// Untracked is the number of files that are not tracked by git
+	Untracked int
+	// Submodules is the number of submodules that are not initialized or not checked out at the recorded commit
+	Submodules int
+}
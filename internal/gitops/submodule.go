@@ -0,0 +1,85 @@
+package gitops
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"path/filepath"
+	"strings"
+)
+
+// gitModulesFileName is the file that lists submodules of a repository
+const gitModulesFileName = ".gitmodules"
+
+type SubmoduleState string
+
+const (
+	SubmoduleUpToDate       SubmoduleState = "up-to-date"
+	SubmoduleNotInitialized SubmoduleState = "not initialized"
+	SubmoduleOutdated       SubmoduleState = "outdated"
+	SubmoduleConflict       SubmoduleState = "conflict"
+)
+
+func (s *SubmoduleState) String() string {
+	return string(*s)
+}
+
+type Submodule struct {
+	// Path is the directory of the submodule relative to the repository
+	Path  string
+	State SubmoduleState
+}
+
+func (s *Submodule) String() string {
+	return fmt.Sprintf("%v: %v", s.Path, s.State)
+}
+
+// Drifted reports whether the submodule is not checked out at the commit recorded in the repository
+func (s *Submodule) Drifted() bool {
+	return s.State != SubmoduleUpToDate
+}
+
+// parseSubmodules parses `git submodule status` output.
+// Each line starts with a state marker followed by the commit id, the path and an optional description
+func parseSubmodules(consoleOutputString string) ([]Submodule, error) {
+	var submodules []Submodule
+	for _, line := range strings.Split(consoleOutputString, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// the path may contain spaces, so it is everything between the hash and the optional " (describe)" suffix
+		_, path, found := strings.Cut(line[1:], " ")
+		if strings.HasSuffix(path, ")") {
+			if index := strings.LastIndex(path, " ("); index >= 0 {
+				path = path[:index]
+			}
+		}
+		if !found || path == "" {
+			return nil, fmt.Errorf("failed to parse submodule from console output: %v", line)
+		}
+
+		var state SubmoduleState
+		switch line[0] {
+		case ' ':
+			state = SubmoduleUpToDate
+		case '-':
+			state = SubmoduleNotInitialized
+		case '+':
+			state = SubmoduleOutdated
+		case 'U':
+			state = SubmoduleConflict
+		default:
+			return nil, fmt.Errorf("failed to parse submodule state from console output: %v", line)
+		}
+
+		submodules = append(submodules, Submodule{Path: path, State: state})
+	}
+
+	return submodules, nil
+}
+
+// hasSubmodules checks whether the repository declares any submodule
+func hasSubmodules(repo *model.Repo) (bool, error) {
+	return utils.Exists(filepath.Join(repo.Path, gitModulesFileName))
+}
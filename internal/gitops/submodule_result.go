@@ -0,0 +1,13 @@
+package gitops
+
+type SubmoduleResult string
+
+const (
+	SubmodulesSynced SubmoduleResult = "synced"
+	SubmodulesNone   SubmoduleResult = "no submodules"
+	SubmodulesError  SubmoduleResult = "error"
+)
+
+func (r *SubmoduleResult) String() string {
+	return string(*r)
+}
@@ -0,0 +1,44 @@
+package gitops
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"strings"
+)
+
+// Tag is a tag of the local repository, or a tag of the remote repository if Remote is set
+type Tag struct {
+	Name   string
+	Remote string
+}
+
+func (t *Tag) String() string {
+	return RefTagPrefix + t.Name
+}
+
+func (t *Tag) Short() string {
+	if t.Remote == "" {
+		return t.Name
+	}
+	return fmt.Sprintf("%s/%s", t.Remote, t.Name)
+}
+
+func (t *Tag) IsLocal() bool {
+	return t.Remote == ""
+}
+
+func (t *Tag) GetGitMode() config.GitMode {
+	if t.IsLocal() {
+		return config.GitModeLocal
+	}
+
+	return config.GitModeRemote
+}
+
+func parseTag(fullTagName string, remote string) (*Tag, error) {
+	if !strings.HasPrefix(fullTagName, RefTagPrefix) {
+		return nil, fmt.Errorf("unsupported tag name: %v", fullTagName)
+	}
+
+	return &Tag{Name: strings.TrimPrefix(fullTagName, RefTagPrefix), Remote: remote}, nil
+}
@@ -0,0 +1,14 @@
+package gitops
+
+type TagResult string
+
+const (
+	TagCreated TagResult = "created"
+	TagPushed  TagResult = "pushed"
+	TagRemoved TagResult = "removed"
+	TagError   TagResult = "error"
+)
+
+func (r *TagResult) String() string {
+	return string(*r)
+}
@@ -0,0 +1,163 @@
+package gitops
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	versionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)$`)
+	// conventionalCommitRegexp matches a subject like "feat(parser)!: description"
+	conventionalCommitRegexp = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?: `)
+)
+
+// Version is a semantic version without pre-release and build metadata
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v *Version) String() string {
+	return fmt.Sprintf("%v.%v.%v", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns a negative number if the version is lower than `other`, zero if they are equal,
+// and a positive number otherwise
+func (v *Version) Compare(other Version) int {
+	if v.Major != other.Major {
+		return v.Major - other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor - other.Minor
+	}
+	return v.Patch - other.Patch
+}
+
+// Bump returns the next version according to the level
+func (v *Version) Bump(bump VersionBump) Version {
+	switch bump {
+	case BumpMajor:
+		return Version{Major: v.Major + 1}
+	case BumpMinor:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	case BumpPatch:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	default:
+		return *v
+	}
+}
+
+// ParseVersion parses a version like `1.2.3` that is optionally prefixed with `prefix`
+func ParseVersion(value string, prefix string) (Version, bool) {
+	if !strings.HasPrefix(value, prefix) {
+		return Version{}, false
+	}
+
+	matches := versionRegexp.FindStringSubmatch(strings.TrimPrefix(value, prefix))
+	if matches == nil {
+		return Version{}, false
+	}
+
+	var numbers [3]int
+	for i := range numbers {
+		number, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return Version{}, false
+		}
+		numbers[i] = number
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, true
+}
+
+// VersionBump is the part of a semantic version that changes
+type VersionBump int
+
+const (
+	BumpNone VersionBump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b VersionBump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// GetVersionBump finds the version bump required by the Conventional Commits.
+// Breaking changes require a major bump, features require a minor one, fixes and performance improvements require
+// a patch one. The reason lists the number of such commits
+func GetVersionBump(commits []Commit) (VersionBump, string) {
+	var breaking, features, fixes int
+	for _, commit := range commits {
+		matches := conventionalCommitRegexp.FindStringSubmatch(commit.Subject)
+		if matches == nil {
+			continue
+		}
+
+		if matches[2] == "!" || strings.Contains(commit.Body, "BREAKING CHANGE:") ||
+			strings.Contains(commit.Body, "BREAKING-CHANGE:") {
+			breaking++
+			continue
+		}
+
+		switch matches[1] {
+		case "feat":
+			features++
+		case "fix", "perf":
+			fixes++
+		}
+	}
+
+	var reasons []string
+	if breaking > 0 {
+		reasons = append(reasons, pluralize(breaking, "breaking change"))
+	}
+	if features > 0 {
+		reasons = append(reasons, pluralize(features, "feature"))
+	}
+	if fixes > 0 {
+		reasons = append(reasons, pluralize(fixes, "fix"))
+	}
+
+	bump := BumpNone
+	switch {
+	case breaking > 0:
+		bump = BumpMajor
+	case features > 0:
+		bump = BumpMinor
+	case fixes > 0:
+		bump = BumpPatch
+	}
+
+	if len(commits) == 0 {
+		return bump, "no commits since the latest version"
+	}
+	if bump == BumpNone {
+		return bump, fmt.Sprintf("no releasable changes in %v", pluralize(len(commits), "commit"))
+	}
+
+	return bump, fmt.Sprintf("%v: %v", bump, strings.Join(reasons, ", "))
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%v %v", count, noun)
+	}
+	if strings.HasSuffix(noun, "x") {
+		return fmt.Sprintf("%v %ves", count, noun)
+	}
+	return fmt.Sprintf("%v %vs", count, noun)
+}
@@ -0,0 +1,91 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		value  string
+		prefix string
+		want   Version
+		wantOk bool
+	}{
+		{value: "v1.2.3", prefix: "v", want: Version{1, 2, 3}, wantOk: true},
+		{value: "1.2.30", prefix: "", want: Version{1, 2, 30}, wantOk: true},
+		{value: "1.2.3", prefix: "v", wantOk: false},
+		{value: "v1.2", prefix: "v", wantOk: false},
+		{value: "v1.2.3-rc.1", prefix: "v", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.value, func(t *testing.T) {
+				got, ok := ParseVersion(tt.value, tt.prefix)
+				assert.Equal(t, tt.wantOk, ok)
+				assert.Equal(t, tt.want, got)
+			},
+		)
+	}
+}
+
+func TestGetVersionBump(t *testing.T) {
+	tests := []struct {
+		name       string
+		commits    []Commit
+		wantBump   VersionBump
+		wantReason string
+	}{
+		{
+			name:       "no commits",
+			wantBump:   BumpNone,
+			wantReason: "no commits since the latest version",
+		},
+		{
+			name:       "not releasable",
+			commits:    []Commit{{Subject: "chore: update dependencies"}, {Subject: "Merge branch 'main'"}},
+			wantBump:   BumpNone,
+			wantReason: "no releasable changes in 2 commits",
+		},
+		{
+			name:       "fixes",
+			commits:    []Commit{{Subject: "fix: null pointer"}, {Subject: "perf(db): cache queries"}},
+			wantBump:   BumpPatch,
+			wantReason: "patch: 2 fixes",
+		},
+		{
+			name:       "features",
+			commits:    []Commit{{Subject: "feat(api): new endpoint"}, {Subject: "fix: typo"}},
+			wantBump:   BumpMinor,
+			wantReason: "minor: 1 feature, 1 fix",
+		},
+		{
+			name: "breaking changes",
+			commits: []Commit{
+				{Subject: "feat!: drop old endpoint"},
+				{Subject: "refactor: rename field", Body: "BREAKING CHANGE: the field is renamed"},
+				{Subject: "feat: new endpoint"},
+			},
+			wantBump:   BumpMajor,
+			wantReason: "major: 2 breaking changes, 1 feature",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				bump, reason := GetVersionBump(tt.commits)
+				assert.Equal(t, tt.wantBump, bump)
+				assert.Equal(t, tt.wantReason, reason)
+			},
+		)
+	}
+}
+
+func TestVersion_Bump(t *testing.T) {
+	version := Version{1, 2, 3}
+	assert.Equal(t, Version{2, 0, 0}, version.Bump(BumpMajor))
+	assert.Equal(t, Version{1, 3, 0}, version.Bump(BumpMinor))
+	assert.Equal(t, Version{1, 2, 4}, version.Bump(BumpPatch))
+	assert.Equal(t, Version{1, 2, 3}, version.Bump(BumpNone))
+}
@@ -0,0 +1,90 @@
+package gitops
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type Worktree struct {
+	// Path is the absolute directory of the worktree
+	Path string
+	// Head is the id of the checked out commit
+	Head string
+	// Branch is the checked out branch name, empty if the head is detached
+	Branch string
+	// Main is set for the worktree the repository is cloned to
+	Main bool
+	// Locked is set if the worktree is protected from being pruned or removed
+	Locked bool
+}
+
+func (w *Worktree) String() string {
+	if w.Branch == "" {
+		head := Commit{Id: w.Head}
+		return fmt.Sprintf("%v: detached at %v", w.Path, head.ShortId())
+	}
+
+	return fmt.Sprintf("%v: %v", w.Path, w.Branch)
+}
+
+// parseWorktrees parses `git worktree list --porcelain` output.
+// The records are separated by an empty line, the first record is the main worktree
+func parseWorktrees(consoleOutputString string) ([]Worktree, error) {
+	var worktrees []Worktree
+	for _, record := range strings.Split(consoleOutputString, "\n\n") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		worktree := Worktree{Main: len(worktrees) == 0}
+		for _, line := range strings.Split(record, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				worktree.Path = filepath.Clean(value)
+			case "HEAD":
+				worktree.Head = value
+			case "branch":
+				worktree.Branch = strings.TrimPrefix(value, RefHeadPrefix)
+			case "locked":
+				worktree.Locked = true
+			}
+		}
+
+		if worktree.Path == "" {
+			return nil, fmt.Errorf("failed to parse worktree from console output: %v", record)
+		}
+
+		worktrees = append(worktrees, worktree)
+	}
+
+	return worktrees, nil
+}
+
+// normalizeWorktreePath makes the path absolute, resolving it against the repository directory like git does,
+// and resolves symbolic links, so that the path given by a user matches the one git reports.
+// Symbolic links of a path that doesn't exist are resolved in its parent directory
+func normalizeWorktreePath(repoPath string, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoPath, path)
+	}
+
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	resolvedPath, err := filepath.EvalSymlinks(absolutePath)
+	if err == nil {
+		return resolvedPath
+	}
+
+	resolvedParent, err := filepath.EvalSymlinks(filepath.Dir(absolutePath))
+	if err == nil {
+		return filepath.Join(resolvedParent, filepath.Base(absolutePath))
+	}
+
+	return absolutePath
+}
@@ -0,0 +1,15 @@
+package gitops
+
+type WorktreeResult string
+
+const (
+	WorktreeAdded    WorktreeResult = "added"
+	WorktreeExists   WorktreeResult = "already exists"
+	WorktreeRemoved  WorktreeResult = "removed"
+	WorktreeNotFound WorktreeResult = "not found"
+	WorktreeError    WorktreeResult = "error"
+)
+
+func (r *WorktreeResult) String() string {
+	return string(*r)
+}
@@ -0,0 +1,91 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// AuditCheck is a comparison of a repository setting with the policy
+type AuditCheck struct {
+	// Setting is the key of the policy
+	Setting  string
+	Actual   string
+	Expected string
+	Result   AuditResult
+}
+
+// Audit compares the repository settings with the policy and fixes the violations if apply is set.
+// Branch protection is checked for the default branch, the one set by the policy if it's fixed
+func Audit(ctx context.Context, client Client, policy *Policy, apply bool) ([]AuditCheck, error) {
+	var result []AuditCheck
+
+	defaultBranch, err := client.GetDefaultBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.DefaultBranch != "" {
+		check := AuditCheck{"defaultBranch", defaultBranch, policy.DefaultBranch, AuditOk}
+		if defaultBranch != policy.DefaultBranch {
+			check.Result = AuditViolation
+			if apply {
+				err = client.SetDefaultBranch(ctx, policy.DefaultBranch)
+				if err != nil {
+					return nil, err
+				}
+
+				check.Result = AuditFixed
+				defaultBranch = policy.DefaultBranch
+			}
+		}
+		result = append(result, check)
+	}
+
+	if !policy.BranchProtection && policy.RequiredReviews == 0 {
+		return result, nil
+	}
+
+	protection, err := client.GetBranchProtection(ctx, defaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []AuditCheck
+	protectionCheck := AuditCheck{"branchProtection", strconv.FormatBool(protection != nil), "true", AuditOk}
+	if protection == nil {
+		protectionCheck.Result = AuditViolation
+		protection = &BranchProtection{}
+	}
+	checks = append(checks, protectionCheck)
+
+	if policy.RequiredReviews > 0 {
+		reviewsCheck := AuditCheck{
+			"requiredReviews", strconv.Itoa(protection.RequiredReviews), strconv.Itoa(policy.RequiredReviews), AuditOk,
+		}
+		if protection.RequiredReviews < policy.RequiredReviews {
+			reviewsCheck.Result = AuditViolation
+			protection.RequiredReviews = policy.RequiredReviews
+		}
+		checks = append(checks, reviewsCheck)
+	}
+
+	violated := false
+	for _, check := range checks {
+		violated = violated || check.Result == AuditViolation
+	}
+	if violated && apply {
+		err = client.SetBranchProtection(ctx, defaultBranch, *protection)
+		if err != nil {
+			return nil, fmt.Errorf("failed to protect branch %v: %w", defaultBranch, err)
+		}
+
+		for i := range checks {
+			if checks[i].Result == AuditViolation {
+				checks[i].Result = AuditFixed
+			}
+		}
+	}
+
+	return append(result, checks...), nil
+}
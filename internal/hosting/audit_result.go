@@ -0,0 +1,13 @@
+package hosting
+
+type AuditResult string
+
+const (
+	AuditOk        AuditResult = "ok"
+	AuditViolation AuditResult = "violation"
+	AuditFixed     AuditResult = "fixed"
+)
+
+func (r *AuditResult) String() string {
+	return string(*r)
+}
@@ -0,0 +1,116 @@
+package hosting
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSettingsClient keeps repository settings in memory
+type fakeSettingsClient struct {
+	Client
+	defaultBranch string
+	protections   map[string]BranchProtection
+}
+
+func (c *fakeSettingsClient) GetDefaultBranch(ctx context.Context) (string, error) {
+	return c.defaultBranch, nil
+}
+
+func (c *fakeSettingsClient) SetDefaultBranch(ctx context.Context, branch string) error {
+	c.defaultBranch = branch
+	return nil
+}
+
+func (c *fakeSettingsClient) GetBranchProtection(ctx context.Context, branch string) (*BranchProtection, error) {
+	protection, found := c.protections[branch]
+	if !found {
+		return nil, nil
+	}
+
+	return &protection, nil
+}
+
+func (c *fakeSettingsClient) SetBranchProtection(
+	ctx context.Context, branch string, protection BranchProtection,
+) error {
+	c.protections[branch] = protection
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	policy := &Policy{DefaultBranch: "main", BranchProtection: true, RequiredReviews: 2}
+	client := &fakeSettingsClient{
+		defaultBranch: "master",
+		protections:   map[string]BranchProtection{"master": {RequiredReviews: 1}},
+	}
+
+	checks, err := Audit(context.Background(), client, policy, false)
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []AuditCheck{
+			{"defaultBranch", "master", "main", AuditViolation},
+			{"branchProtection", "true", "true", AuditOk},
+			{"requiredReviews", "1", "2", AuditViolation},
+		}, checks,
+	)
+
+	checks, err = Audit(context.Background(), client, policy, true)
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []AuditCheck{
+			{"defaultBranch", "master", "main", AuditFixed},
+			{"branchProtection", "false", "true", AuditFixed},
+			{"requiredReviews", "0", "2", AuditFixed},
+		}, checks,
+	)
+	assert.Equal(t, "main", client.defaultBranch)
+	assert.Equal(t, BranchProtection{RequiredReviews: 2}, client.protections["main"])
+
+	checks, err = Audit(context.Background(), client, policy, true)
+	assert.NoError(t, err)
+	for _, check := range checks {
+		assert.Equal(t, AuditOk, check.Result)
+	}
+}
+
+func TestAudit_KeepsMoreReviews(t *testing.T) {
+	policy := &Policy{BranchProtection: true, RequiredReviews: 1}
+	client := &fakeSettingsClient{
+		defaultBranch: "main",
+		protections:   map[string]BranchProtection{"main": {RequiredReviews: 3}},
+	}
+
+	checks, err := Audit(context.Background(), client, policy, true)
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []AuditCheck{
+			{"branchProtection", "true", "true", AuditOk},
+			{"requiredReviews", "3", "1", AuditOk},
+		}, checks,
+	)
+}
+
+func TestReadPolicy(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "policy.yaml")
+	err := os.WriteFile(fileName, []byte("defaultBranch: main\nrequiredReviews: 1\n"), os.ModePerm)
+	assert.NoError(t, err)
+
+	policy, err := ReadPolicy(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, &Policy{DefaultBranch: "main", RequiredReviews: 1}, policy)
+
+	err = os.WriteFile(fileName, []byte("requiredReview: 1\n"), os.ModePerm)
+	assert.NoError(t, err)
+
+	_, err = ReadPolicy(fileName)
+	assert.ErrorContains(t, err, "field requiredReview not found")
+
+	err = os.WriteFile(fileName, []byte("# nothing is checked\n"), os.ModePerm)
+	assert.NoError(t, err)
+
+	_, err = ReadPolicy(fileName)
+	assert.EqualError(t, err, "policy file "+fileName+" is empty")
+}
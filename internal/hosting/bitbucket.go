@@ -0,0 +1,333 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+type bitbucketClient struct {
+	api        *apiClient
+	repository Repository
+}
+
+// bitbucketAuthorization uses basic authentication for "username:app-password" tokens and bearer one otherwise
+func bitbucketAuthorization(token string) func(request *http.Request) {
+	username, password, basic := strings.Cut(token, ":")
+	if !basic {
+		return bearerAuthorization(token)
+	}
+
+	return func(request *http.Request) {
+		request.SetBasicAuth(username, password)
+	}
+}
+
+type bitbucketBranch struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+type bitbucketPullRequest struct {
+	Id     int             `json:"id"`
+	Title  string          `json:"title"`
+	State  string          `json:"state"`
+	Source bitbucketBranch `json:"source"`
+	Target bitbucketBranch `json:"destination"`
+	Author struct {
+		DisplayName string `json:"display_name"`
+	} `json:"author"`
+	Links struct {
+		Html struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+func (p *bitbucketPullRequest) toPullRequest() PullRequest {
+	var state PullRequestState
+	switch p.State {
+	case "OPEN":
+		state = PullRequestOpen
+	case "MERGED":
+		state = PullRequestMerged
+	default:
+		state = PullRequestClosed
+	}
+
+	return PullRequest{
+		Id:     p.Id,
+		Title:  p.Title,
+		State:  state,
+		Source: p.Source.Branch.Name,
+		Target: p.Target.Branch.Name,
+		Author: p.Author.DisplayName,
+		Url:    p.Links.Html.Href,
+	}
+}
+
+func (c *bitbucketClient) repoPath() string {
+	return "/repositories/" + c.repository.Path
+}
+
+func newBitbucketBranch(name string) bitbucketBranch {
+	var result bitbucketBranch
+	result.Branch.Name = name
+	return result
+}
+
+func (c *bitbucketClient) CreatePullRequest(ctx context.Context, options CreatePullRequestOptions) (*PullRequest, error) {
+	reviewers := []map[string]string{}
+	for _, reviewer := range options.Reviewers {
+		// UUIDs of Bitbucket are wrapped into curly braces
+		if strings.HasPrefix(reviewer, "{") {
+			reviewers = append(reviewers, map[string]string{"uuid": reviewer})
+		} else {
+			reviewers = append(reviewers, map[string]string{"account_id": reviewer})
+		}
+	}
+
+	request := map[string]interface{}{
+		"title":       options.Title,
+		"description": options.Body,
+		"source":      newBitbucketBranch(options.Source),
+		"destination": newBitbucketBranch(options.Target),
+		"reviewers":   reviewers,
+	}
+	var response bitbucketPullRequest
+	err := c.api.do(ctx, http.MethodPost, c.repoPath()+"/pullrequests", nil, request, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	result := response.toPullRequest()
+	return &result, nil
+}
+
+// bitbucketPullRequests is a page of pull requests with the URL of the next page, empty on the last one
+type bitbucketPullRequests struct {
+	Values []bitbucketPullRequest `json:"values"`
+	Next   string                 `json:"next"`
+}
+
+func (c *bitbucketClient) GetPullRequests(ctx context.Context, filter PullRequestFilter) ([]PullRequest, error) {
+	query := url.Values{"pagelen": {"50"}}
+	switch filter.State {
+	case PullRequestOpen:
+		query["state"] = []string{"OPEN"}
+	case PullRequestMerged:
+		query["state"] = []string{"MERGED"}
+	default:
+		query["state"] = []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"}
+	}
+	if filter.Source != "" {
+		query.Set("q", fmt.Sprintf(`source.branch.name = "%v"`, filter.Source))
+	}
+
+	pages, err := getPages(
+		ctx, c.api, c.repoPath()+"/pullrequests", query, func(_ http.Header, page bitbucketPullRequests) string {
+			return page.Next
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull requests: %w", err)
+	}
+
+	var result []PullRequest
+	for _, page := range pages {
+		for _, item := range page.Values {
+			pullRequest := item.toPullRequest()
+			if filter.matches(pullRequest) {
+				result = append(result, pullRequest)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+type bitbucketPipeline struct {
+	BuildNumber int `json:"build_number"`
+	State       struct {
+		Name   string `json:"name"`
+		Result struct {
+			Name string `json:"name"`
+		} `json:"result"`
+	} `json:"state"`
+	DurationInSeconds int `json:"duration_in_seconds"`
+}
+
+func (p *bitbucketPipeline) state() BuildState {
+	switch p.State.Name {
+	case "COMPLETED":
+		switch p.State.Result.Name {
+		case "SUCCESSFUL":
+			return BuildSuccess
+		case "STOPPED":
+			return BuildCanceled
+		default:
+			return BuildFailed
+		}
+	case "IN_PROGRESS":
+		return BuildRunning
+	default:
+		return BuildPending
+	}
+}
+
+func (c *bitbucketClient) GetBuild(ctx context.Context, ref string) (*Build, error) {
+	query := url.Values{"target.ref_name": {ref}, "sort": {"-created_on"}, "pagelen": {"1"}}
+	if isCommitHash(ref) {
+		query.Del("target.ref_name")
+		query.Set("target.commit.hash", ref)
+	}
+	var response struct {
+		Values []bitbucketPipeline `json:"values"`
+	}
+	err := c.api.do(ctx, http.MethodGet, c.repoPath()+"/pipelines", query, nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipelines: %w", err)
+	}
+
+	if len(response.Values) == 0 {
+		return &Build{State: BuildNone}, nil
+	}
+
+	pipeline := response.Values[0]
+	return &Build{
+		State: pipeline.state(),
+		// the API doesn't return web links of pipelines
+		Url:      fmt.Sprintf("https://bitbucket.org/%v/pipelines/results/%v", c.repository.Path, pipeline.BuildNumber),
+		Duration: time.Duration(pipeline.DurationInSeconds) * time.Second,
+	}, nil
+}
+
+func (c *bitbucketClient) GetDefaultBranch(ctx context.Context) (string, error) {
+	var response struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	err := c.api.do(ctx, http.MethodGet, c.repoPath(), nil, nil, &response)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	return response.MainBranch.Name, nil
+}
+
+func (c *bitbucketClient) SetDefaultBranch(ctx context.Context, branch string) error {
+	request := map[string]interface{}{"mainbranch": map[string]string{"name": branch}}
+	err := c.api.do(ctx, http.MethodPut, c.repoPath(), nil, request, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	return nil
+}
+
+const bitbucketRequireApprovals = "require_approvals_to_merge"
+
+// bitbucketProtectionKinds are the restrictions that make a branch protected
+var bitbucketProtectionKinds = []string{"push", "force", "delete"}
+
+type bitbucketBranchRestriction struct {
+	Id              int    `json:"id,omitempty"`
+	Kind            string `json:"kind"`
+	BranchMatchKind string `json:"branch_match_kind"`
+	Pattern         string `json:"pattern"`
+	Value           *int   `json:"value,omitempty"`
+}
+
+func (c *bitbucketClient) getBranchRestrictions(
+	ctx context.Context, branch string,
+) ([]bitbucketBranchRestriction, error) {
+	var response struct {
+		Values []bitbucketBranchRestriction `json:"values"`
+	}
+	query := url.Values{"pattern": {branch}, "pagelen": {"100"}}
+	err := c.api.do(ctx, http.MethodGet, c.repoPath()+"/branch-restrictions", query, nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch restrictions: %w", err)
+	}
+
+	return response.Values, nil
+}
+
+func (c *bitbucketClient) GetBranchProtection(ctx context.Context, branch string) (*BranchProtection, error) {
+	restrictions, err := c.getBranchRestrictions(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	protected := false
+	result := &BranchProtection{}
+	for _, restriction := range restrictions {
+		if slices.Contains(bitbucketProtectionKinds, restriction.Kind) {
+			protected = true
+		}
+		if restriction.Kind == bitbucketRequireApprovals && restriction.Value != nil {
+			result.RequiredReviews = *restriction.Value
+		}
+	}
+
+	if !protected {
+		return nil, nil
+	}
+
+	return result, nil
+}
+
+// SetBranchProtection forbids force pushes and deletion of the branch if it isn't protected,
+// and creates or updates the restriction of required approvals
+func (c *bitbucketClient) SetBranchProtection(ctx context.Context, branch string, protection BranchProtection) error {
+	restrictions, err := c.getBranchRestrictions(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	var changes []bitbucketBranchRestriction
+	protected := slices.ContainsFunc(
+		restrictions, func(restriction bitbucketBranchRestriction) bool {
+			return slices.Contains(bitbucketProtectionKinds, restriction.Kind)
+		},
+	)
+	if !protected {
+		changes = append(
+			changes,
+			bitbucketBranchRestriction{Kind: "force", BranchMatchKind: "glob", Pattern: branch},
+			bitbucketBranchRestriction{Kind: "delete", BranchMatchKind: "glob", Pattern: branch},
+		)
+	}
+
+	if protection.RequiredReviews > 0 {
+		approvals := bitbucketBranchRestriction{Kind: bitbucketRequireApprovals, BranchMatchKind: "glob", Pattern: branch}
+		for _, restriction := range restrictions {
+			if restriction.Kind == bitbucketRequireApprovals {
+				approvals = restriction
+			}
+		}
+		if approvals.Value == nil || *approvals.Value != protection.RequiredReviews {
+			approvals.Value = &protection.RequiredReviews
+			changes = append(changes, approvals)
+		}
+	}
+
+	for _, change := range changes {
+		method, path := http.MethodPost, c.repoPath()+"/branch-restrictions"
+		if change.Id != 0 {
+			method, path = http.MethodPut, fmt.Sprintf("%v/%v", path, change.Id)
+		}
+		err = c.api.do(ctx, method, path, nil, change, nil)
+		if err != nil {
+			return fmt.Errorf("failed to update branch restriction %v: %w", change.Kind, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package hosting
+
+import (
+	"regexp"
+	"time"
+)
+
+type BuildState string
+
+const (
+	BuildSuccess  BuildState = "success"
+	BuildFailed   BuildState = "failed"
+	BuildCanceled BuildState = "canceled"
+	BuildRunning  BuildState = "running"
+	BuildPending  BuildState = "pending"
+	// BuildNone means there are no builds for the ref
+	BuildNone BuildState = "none"
+)
+
+func (s *BuildState) String() string {
+	return string(*s)
+}
+
+// buildStatePriority orders states to choose one for several checks of a commit. The greatest one is chosen
+var buildStatePriority = map[BuildState]int{
+	BuildNone:     0,
+	BuildSuccess:  1,
+	BuildPending:  2,
+	BuildRunning:  3,
+	BuildCanceled: 4,
+	BuildFailed:   5,
+}
+
+// Build is a summary of GitHub checks, a GitLab pipeline or a Bitbucket pipeline of a ref
+type Build struct {
+	State BuildState
+	// Url is the web page of the build
+	Url string
+	// Duration is the time the build took, or has taken so far if it's still running
+	Duration time.Duration
+}
+
+var commitHashRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// isCommitHash checks if the ref is a full commit hash, that providers look builds up by differently from branches
+func isCommitHash(ref string) bool {
+	return commitHashRegexp.MatchString(ref)
+}
+
+// durationSince returns the duration of a build that started at the given time, zero if it hasn't started yet
+func durationSince(started *time.Time, completed *time.Time) time.Duration {
+	if started == nil {
+		return 0
+	}
+
+	if completed == nil {
+		return time.Since(*started)
+	}
+
+	return completed.Sub(*started)
+}
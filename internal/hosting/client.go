@@ -0,0 +1,250 @@
+package hosting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Client manages a repository with the REST API of its hosting
+type Client interface {
+	// CreatePullRequest returns the created pull request along with the error if it's created,
+	// but the reviewers aren't requested
+	CreatePullRequest(ctx context.Context, options CreatePullRequestOptions) (*PullRequest, error)
+	GetPullRequests(ctx context.Context, filter PullRequestFilter) ([]PullRequest, error)
+	// GetBuild returns the status of the latest build of a branch, a tag or a commit referred to by its full hash
+	GetBuild(ctx context.Context, ref string) (*Build, error)
+	GetDefaultBranch(ctx context.Context) (string, error)
+	SetDefaultBranch(ctx context.Context, branch string) error
+	// GetBranchProtection returns nil if the branch isn't protected
+	GetBranchProtection(ctx context.Context, branch string) (*BranchProtection, error)
+	// SetBranchProtection protects the branch if it isn't protected yet and updates the required reviews
+	SetBranchProtection(ctx context.Context, branch string, protection BranchProtection) error
+}
+
+// NewClient creates a client for the hosting of the repository with the given git URL.
+// The provider is detected by the host of the URL unless it's configured explicitly.
+// The public API of the provider is used unless a base URL is configured
+func NewClient(conf *config.Config, repoUrl string) (Client, error) {
+	repository, err := ParseRepository(repoUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := conf.HostingProvider
+	if provider == config.HostingProviderDetect {
+		provider, err = detectProvider(repository.Host)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	api := &apiClient{
+		baseUrl:    strings.TrimSuffix(conf.HostingApiUrl, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second, CheckRedirect: dropTokenOnRedirect},
+	}
+
+	switch provider {
+	case config.HostingProviderGithub:
+		api.setDefaultBaseUrl(githubApiUrl(repository.Host))
+		api.authorize = bearerAuthorization(conf.HostingToken)
+		return &githubClient{api, repository}, nil
+	case config.HostingProviderGitlab:
+		api.setDefaultBaseUrl(fmt.Sprintf("https://%v/api/v4", repository.Host))
+		api.authorize = func(request *http.Request) {
+			if conf.HostingToken != "" {
+				request.Header.Set("PRIVATE-TOKEN", conf.HostingToken)
+			}
+		}
+		return &gitlabClient{api, repository}, nil
+	case config.HostingProviderBitbucket:
+		api.setDefaultBaseUrl("https://api.bitbucket.org/2.0")
+		api.authorize = bitbucketAuthorization(conf.HostingToken)
+		return &bitbucketClient{api, repository}, nil
+	default:
+		return nil, fmt.Errorf("unsupported hosting provider: %v", provider)
+	}
+}
+
+func detectProvider(host string) (config.HostingProvider, error) {
+	switch {
+	case strings.Contains(host, "github"):
+		return config.HostingProviderGithub, nil
+	case strings.Contains(host, "gitlab"):
+		return config.HostingProviderGitlab, nil
+	case strings.Contains(host, "bitbucket"):
+		return config.HostingProviderBitbucket, nil
+	default:
+		return "", fmt.Errorf("failed to detect hosting provider of %v, configure it with --hosting-provider", host)
+	}
+}
+
+func bearerAuthorization(token string) func(request *http.Request) {
+	return func(request *http.Request) {
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+}
+
+// dropTokenOnRedirect doesn't send the token to other hosts. The HTTP client drops the Authorization header
+// only if the host name differs, but it keeps the custom header of GitLab and ignores the port
+func dropTokenOnRedirect(request *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+
+	if request.URL.Host != via[0].URL.Host {
+		request.Header.Del("Authorization")
+		request.Header.Del("PRIVATE-TOKEN")
+	}
+
+	return nil
+}
+
+// apiClient sends JSON requests to a REST API
+type apiClient struct {
+	baseUrl    string
+	httpClient *http.Client
+	authorize  func(request *http.Request)
+}
+
+func (c *apiClient) setDefaultBaseUrl(baseUrl string) {
+	if c.baseUrl == "" {
+		c.baseUrl = baseUrl
+	}
+}
+
+// do sends the request body encoded to JSON and decodes the response to the response body if it's not nil.
+// The path is expected to be escaped already
+func (c *apiClient) do(
+	ctx context.Context, method string, path string, query url.Values, requestBody interface{},
+	responseBody interface{},
+) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	_, err := c.send(ctx, method, path, requestBody, responseBody)
+	return err
+}
+
+// getPages gets the list page by page, until the next page function returns no URL of the next page.
+// A page is decoded to a value of type T, that is a slice of items or an object the provider wraps them into
+func getPages[T any](
+	ctx context.Context, api *apiClient, path string, query url.Values, nextPage func(header http.Header, page T) string,
+) ([]T, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var result []T
+	for path != "" {
+		var page T
+		header, err := api.send(ctx, http.MethodGet, path, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, page)
+
+		next := nextPage(header, page)
+		if next == "" {
+			break
+		}
+		// the token must not be sent anywhere else than the API
+		if !strings.HasPrefix(next, api.baseUrl+"/") {
+			return nil, fmt.Errorf("unexpected URL of the next page: %v", next)
+		}
+		path = strings.TrimPrefix(next, api.baseUrl)
+	}
+
+	return result, nil
+}
+
+// linkNextPage returns the URL of the next page from the Link header, that GitHub and GitLab send
+func linkNextPage(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, found := strings.Cut(link, ";")
+		if !found {
+			continue
+		}
+
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+
+	return ""
+}
+
+// send sends the request to the path with the query and returns headers of the response
+func (c *apiClient) send(
+	ctx context.Context, method string, path string, requestBody interface{}, responseBody interface{},
+) (http.Header, error) {
+	requestUrl := c.baseUrl + path
+	// errors refer to the path without the query
+	path, _, _ = strings.Cut(path, "?")
+
+	var body io.Reader
+	if requestBody != nil {
+		encoded, err := json.Marshal(requestBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestUrl, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	request.Header.Set("Accept", "application/json")
+	if requestBody != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.authorize != nil {
+		c.authorize(request)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	responseBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%v %v: %w", method, path, ErrNotFound)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf(
+			"%v %v failed: %v, %v", method, path, response.Status, strings.TrimSpace(string(responseBytes)),
+		)
+	}
+
+	if responseBody != nil && len(responseBytes) > 0 {
+		err = json.Unmarshal(responseBytes, responseBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response of %v %v: %w", method, path, err)
+		}
+	}
+
+	return response.Header, nil
+}
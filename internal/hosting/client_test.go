@@ -0,0 +1,84 @@
+package hosting
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClient_DetectProvider(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected Client
+	}{
+		{"git@github.com:owner/repo.git", &githubClient{}},
+		{"https://gitlab.example.com/group/repo.git", &gitlabClient{}},
+		{"https://bitbucket.org/workspace/repo.git", &bitbucketClient{}},
+	}
+	for _, test := range cases {
+		t.Run(
+			test.url, func(t *testing.T) {
+				client, err := NewClient(&config.Config{}, test.url)
+				assert.NoError(t, err)
+				assert.IsType(t, test.expected, client)
+			},
+		)
+	}
+
+	_, err := NewClient(&config.Config{}, "https://example.com/owner/repo.git")
+	assert.ErrorContains(t, err, "failed to detect hosting provider of example.com")
+
+	client, err := NewClient(
+		&config.Config{HostingProvider: config.HostingProviderGitlab}, "https://example.com/owner/repo.git",
+	)
+	assert.NoError(t, err)
+	assert.IsType(t, &gitlabClient{}, client)
+}
+
+func TestNewClient_Error(t *testing.T) {
+	server, _ := tests.StartHostingServer(t, map[string]string{})
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "https://github.com/owner/repo.git")
+	assert.NoError(t, err)
+
+	_, err = client.GetPullRequests(context.Background(), PullRequestFilter{State: PullRequestOpen})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNewClient_RedirectToOtherHost(t *testing.T) {
+	target, requests := tests.StartHostingServer(
+		t, map[string]string{"GET /projects/group%2Frepo": `{"default_branch": "main"}`},
+	)
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				http.Redirect(writer, request, target.URL+request.URL.RequestURI(), http.StatusFound)
+			},
+		),
+	)
+	t.Cleanup(server.Close)
+	client, err := NewClient(
+		&config.Config{HostingApiUrl: server.URL, HostingToken: "secret"}, "https://gitlab.com/group/repo.git",
+	)
+	assert.NoError(t, err)
+
+	defaultBranch, err := client.GetDefaultBranch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "main", defaultBranch)
+	assert.Equal(t, []tests.HostingRequest{{Method: "GET", Path: "/projects/group%2Frepo"}}, *requests)
+}
+
+func TestLinkNextPage(t *testing.T) {
+	header := http.Header{}
+	assert.Equal(t, "", linkNextPage(header))
+
+	header.Set(
+		"Link", `<https://api.github.com/repositories/1/pulls?page=1>; rel="prev", `+
+			`<https://api.github.com/repositories/1/pulls?page=3>; rel="next", `+
+			`<https://api.github.com/repositories/1/pulls?page=5>; rel="last"`,
+	)
+	assert.Equal(t, "https://api.github.com/repositories/1/pulls?page=3", linkNextPage(header))
+}
@@ -0,0 +1,341 @@
+package hosting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+type githubClient struct {
+	api        *apiClient
+	repository Repository
+}
+
+// githubApiUrl returns the public API for github.com and the API of GitHub Enterprise Server otherwise
+func githubApiUrl(host string) string {
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+
+	return fmt.Sprintf("https://%v/api/v3", host)
+}
+
+type githubPullRequest struct {
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	State    string  `json:"state"`
+	MergedAt *string `json:"merged_at"`
+	HtmlUrl  string  `json:"html_url"`
+	Head     struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (p *githubPullRequest) toPullRequest() PullRequest {
+	state := PullRequestOpen
+	if p.MergedAt != nil {
+		state = PullRequestMerged
+	} else if p.State == "closed" {
+		state = PullRequestClosed
+	}
+
+	return PullRequest{
+		Id:     p.Number,
+		Title:  p.Title,
+		State:  state,
+		Source: p.Head.Ref,
+		Target: p.Base.Ref,
+		Author: p.User.Login,
+		Url:    p.HtmlUrl,
+	}
+}
+
+func (c *githubClient) repoPath() string {
+	return "/repos/" + c.repository.Path
+}
+
+func (c *githubClient) CreatePullRequest(ctx context.Context, options CreatePullRequestOptions) (*PullRequest, error) {
+	request := map[string]string{
+		"title": options.Title,
+		"body":  options.Body,
+		"head":  options.Source,
+		"base":  options.Target,
+	}
+	var response githubPullRequest
+	err := c.api.do(ctx, http.MethodPost, c.repoPath()+"/pulls", nil, request, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	result := response.toPullRequest()
+	if len(options.Reviewers) > 0 {
+		path := fmt.Sprintf("%v/pulls/%v/requested_reviewers", c.repoPath(), response.Number)
+		err = c.api.do(ctx, http.MethodPost, path, nil, map[string][]string{"reviewers": options.Reviewers}, nil)
+		if err != nil {
+			return &result, fmt.Errorf("failed to request reviewers: %w", err)
+		}
+	}
+
+	return &result, nil
+}
+
+func (c *githubClient) GetPullRequests(ctx context.Context, filter PullRequestFilter) ([]PullRequest, error) {
+	query := url.Values{"per_page": {"100"}}
+	if filter.State == PullRequestOpen {
+		query.Set("state", "open")
+	} else {
+		query.Set("state", "all")
+	}
+	if filter.Source != "" {
+		query.Set("head", c.repository.Owner()+":"+filter.Source)
+	}
+
+	pages, err := getPages(
+		ctx, c.api, c.repoPath()+"/pulls", query, func(header http.Header, _ []githubPullRequest) string {
+			return linkNextPage(header)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull requests: %w", err)
+	}
+
+	var result []PullRequest
+	for _, item := range slices.Concat(pages...) {
+		pullRequest := item.toPullRequest()
+		if filter.matches(pullRequest) {
+			result = append(result, pullRequest)
+		}
+	}
+
+	return result, nil
+}
+
+type githubCheckRun struct {
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	HtmlUrl     string     `json:"html_url"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+func (r *githubCheckRun) toCheck() githubCheck {
+	result := githubCheck{url: r.HtmlUrl, started: r.StartedAt, completed: r.CompletedAt}
+	switch r.Status {
+	case "completed":
+		switch r.Conclusion {
+		case "success", "neutral", "skipped":
+			result.state = BuildSuccess
+		case "cancelled":
+			result.state = BuildCanceled
+		default:
+			result.state = BuildFailed
+		}
+	case "in_progress":
+		result.state = BuildRunning
+	default:
+		result.state = BuildPending
+	}
+
+	return result
+}
+
+type githubCheckRuns struct {
+	CheckRuns []githubCheckRun `json:"check_runs"`
+}
+
+// githubCommitStatus is reported by the integrations that use the commit statuses API instead of the checks one
+type githubCommitStatus struct {
+	State     string     `json:"state"`
+	TargetUrl string     `json:"target_url"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+func (s *githubCommitStatus) toCheck() githubCheck {
+	result := githubCheck{url: s.TargetUrl, started: s.CreatedAt, completed: s.UpdatedAt}
+	switch s.State {
+	case "success":
+		result.state = BuildSuccess
+	case "pending":
+		result.state = BuildPending
+	default:
+		result.state = BuildFailed
+	}
+
+	return result
+}
+
+type githubCombinedStatus struct {
+	Statuses []githubCommitStatus `json:"statuses"`
+}
+
+// githubCheck is either a check run or a commit status
+type githubCheck struct {
+	state     BuildState
+	url       string
+	started   *time.Time
+	completed *time.Time
+}
+
+// GetBuild combines all check runs and commit statuses of the commit. The build fails if any check fails,
+// and it lasts from the start of the first check to the completion of the last one
+func (c *githubClient) GetBuild(ctx context.Context, ref string) (*Build, error) {
+	commitPath := fmt.Sprintf("%v/commits/%v", c.repoPath(), url.PathEscape(ref))
+	query := url.Values{"per_page": {"100"}}
+
+	checkRunPages, err := getPages(
+		ctx, c.api, commitPath+"/check-runs", query, func(header http.Header, _ githubCheckRuns) string {
+			return linkNextPage(header)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check runs: %w", err)
+	}
+
+	statusPages, err := getPages(
+		ctx, c.api, commitPath+"/status", query, func(header http.Header, _ githubCombinedStatus) string {
+			return linkNextPage(header)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit statuses: %w", err)
+	}
+
+	var checks []githubCheck
+	for _, page := range checkRunPages {
+		for _, run := range page.CheckRuns {
+			checks = append(checks, run.toCheck())
+		}
+	}
+	for _, page := range statusPages {
+		for _, status := range page.Statuses {
+			checks = append(checks, status.toCheck())
+		}
+	}
+
+	result := &Build{State: BuildNone}
+	var started, completed *time.Time
+	for _, check := range checks {
+		if buildStatePriority[check.state] > buildStatePriority[result.State] {
+			result.State = check.state
+			result.Url = check.url
+		}
+		if check.started != nil && (started == nil || check.started.Before(*started)) {
+			started = check.started
+		}
+		if check.completed != nil && (completed == nil || check.completed.After(*completed)) {
+			completed = check.completed
+		}
+	}
+
+	if result.State == BuildRunning || result.State == BuildPending {
+		completed = nil
+	}
+	result.Duration = durationSince(started, completed)
+
+	return result, nil
+}
+
+func (c *githubClient) GetDefaultBranch(ctx context.Context) (string, error) {
+	var response struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	err := c.api.do(ctx, http.MethodGet, c.repoPath(), nil, nil, &response)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	return response.DefaultBranch, nil
+}
+
+func (c *githubClient) SetDefaultBranch(ctx context.Context, branch string) error {
+	err := c.api.do(ctx, http.MethodPatch, c.repoPath(), nil, map[string]string{"default_branch": branch}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	return nil
+}
+
+func (c *githubClient) branchProtectionPath(branch string) string {
+	return fmt.Sprintf("%v/branches/%v/protection", c.repoPath(), url.PathEscape(branch))
+}
+
+// GetBranchProtection reads the protection that only admins of the repository can read.
+// GitHub responds with 404 status both if the branch isn't protected and if the protection can't be read,
+// so the branch itself, that is visible to everyone, tells the cases apart
+func (c *githubClient) GetBranchProtection(ctx context.Context, branch string) (*BranchProtection, error) {
+	var response struct {
+		RequiredPullRequestReviews *struct {
+			RequiredApprovingReviewCount int `json:"required_approving_review_count"`
+		} `json:"required_pull_request_reviews"`
+	}
+	err := c.api.do(ctx, http.MethodGet, c.branchProtectionPath(branch), nil, nil, &response)
+	if errors.Is(err, ErrNotFound) {
+		var branchResponse struct {
+			Protected bool `json:"protected"`
+		}
+		path := fmt.Sprintf("%v/branches/%v", c.repoPath(), url.PathEscape(branch))
+		err = c.api.do(ctx, http.MethodGet, path, nil, nil, &branchResponse)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get branch: %w", err)
+		}
+
+		if branchResponse.Protected {
+			return nil, fmt.Errorf("branch %v is protected, but its protection can't be read without admin rights", branch)
+		}
+
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch protection: %w", err)
+	}
+
+	result := &BranchProtection{}
+	if response.RequiredPullRequestReviews != nil {
+		result.RequiredReviews = response.RequiredPullRequestReviews.RequiredApprovingReviewCount
+	}
+
+	return result, nil
+}
+
+// SetBranchProtection updates only the reviews of a protected branch, because replacing the whole protection
+// would drop its other settings, like required status checks
+func (c *githubClient) SetBranchProtection(ctx context.Context, branch string, protection BranchProtection) error {
+	current, err := c.GetBranchProtection(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	reviews := map[string]int{"required_approving_review_count": protection.RequiredReviews}
+	if current == nil {
+		request := map[string]interface{}{
+			"required_status_checks":        nil,
+			"enforce_admins":                nil,
+			"required_pull_request_reviews": nil,
+			"restrictions":                  nil,
+		}
+		if protection.RequiredReviews > 0 {
+			request["required_pull_request_reviews"] = reviews
+		}
+		err = c.api.do(ctx, http.MethodPut, c.branchProtectionPath(branch), nil, request, nil)
+	} else if current.RequiredReviews != protection.RequiredReviews {
+		path := c.branchProtectionPath(branch) + "/required_pull_request_reviews"
+		err = c.api.do(ctx, http.MethodPatch, path, nil, reviews, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update branch protection: %w", err)
+	}
+
+	return nil
+}
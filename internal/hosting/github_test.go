@@ -0,0 +1,278 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const githubTestPullRequests = `[
+	{"number": 2, "title": "Feature", "state": "open", "html_url": "https://github.com/owner/repo/pull/2",
+		"head": {"ref": "feature"}, "base": {"ref": "main"}, "user": {"login": "alice"}},
+	{"number": 1, "title": "Fix", "state": "closed", "merged_at": "2024-01-01T10:00:00Z",
+		"html_url": "https://github.com/owner/repo/pull/1",
+		"head": {"ref": "fix"}, "base": {"ref": "main"}, "user": {"login": "bob"}}
+]`
+
+func TestGithubClient_CreatePullRequest(t *testing.T) {
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"POST /repos/owner/repo/pulls": `{"number": 3, "title": "Title", "state": "open",
+				"html_url": "https://github.com/owner/repo/pull/3",
+				"head": {"ref": "feature"}, "base": {"ref": "main"}, "user": {"login": "alice"}}`,
+			"POST /repos/owner/repo/pulls/3/requested_reviewers": `{}`,
+		},
+	)
+	client, err := NewClient(
+		&config.Config{HostingApiUrl: server.URL, HostingToken: "secret"}, "git@github.com:owner/repo.git",
+	)
+	assert.NoError(t, err)
+
+	pullRequest, err := client.CreatePullRequest(
+		context.Background(), CreatePullRequestOptions{
+			Source: "feature", Target: "main", Title: "Title", Body: "Body", Reviewers: []string{"bob"},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &PullRequest{
+			Id: 3, Title: "Title", State: PullRequestOpen, Source: "feature", Target: "main", Author: "alice",
+			Url: "https://github.com/owner/repo/pull/3",
+		}, pullRequest,
+	)
+	assert.Equal(
+		t, []tests.HostingRequest{
+			{
+				Method: "POST", Path: "/repos/owner/repo/pulls", Authorization: "Bearer secret",
+				Body: map[string]interface{}{"title": "Title", "body": "Body", "head": "feature", "base": "main"},
+			},
+			{
+				Method: "POST", Path: "/repos/owner/repo/pulls/3/requested_reviewers", Authorization: "Bearer secret",
+				Body: map[string]interface{}{"reviewers": []interface{}{"bob"}},
+			},
+		}, *requests,
+	)
+}
+
+func TestGithubClient_CreatePullRequest_ReviewersError(t *testing.T) {
+	server, _ := tests.StartHostingServer(
+		t, map[string]string{
+			"POST /repos/owner/repo/pulls": `{"number": 3, "title": "Title", "state": "open",
+				"html_url": "https://github.com/owner/repo/pull/3"}`,
+		},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@github.com:owner/repo.git")
+	assert.NoError(t, err)
+
+	pullRequest, err := client.CreatePullRequest(
+		context.Background(), CreatePullRequestOptions{
+			Source: "feature", Target: "main", Title: "Title", Reviewers: []string{"bob"},
+		},
+	)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(
+		t, &PullRequest{Id: 3, Title: "Title", State: PullRequestOpen, Url: "https://github.com/owner/repo/pull/3"},
+		pullRequest,
+	)
+}
+
+func TestGithubClient_GetPullRequests(t *testing.T) {
+	server, requests := tests.StartHostingServer(t, map[string]string{"GET /repos/owner/repo/pulls": githubTestPullRequests})
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@github.com:owner/repo.git")
+	assert.NoError(t, err)
+
+	pullRequests, err := client.GetPullRequests(context.Background(), PullRequestFilter{State: PullRequestAll})
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []PullRequest{
+			{
+				Id: 2, Title: "Feature", State: PullRequestOpen, Source: "feature", Target: "main", Author: "alice",
+				Url: "https://github.com/owner/repo/pull/2",
+			},
+			{
+				Id: 1, Title: "Fix", State: PullRequestMerged, Source: "fix", Target: "main", Author: "bob",
+				Url: "https://github.com/owner/repo/pull/1",
+			},
+		}, pullRequests,
+	)
+
+	pullRequests, err = client.GetPullRequests(
+		context.Background(), PullRequestFilter{Source: "fix", State: PullRequestMerged},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 1)
+	assert.Equal(t, "head=owner%3Afix&per_page=100&state=all", (*requests)[1].Query)
+}
+
+func TestGithubClient_GetPullRequests_Pages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				response := `[{"number": 1, "title": "Fix", "state": "open"}]`
+				if request.URL.Query().Get("page") == "" {
+					writer.Header().Set("Link", fmt.Sprintf(`<%v/repos/owner/repo/pulls?page=2>; rel="next"`, server.URL))
+					response = `[{"number": 2, "title": "Feature", "state": "open"}]`
+				}
+				_, err := writer.Write([]byte(response))
+				assert.NoError(t, err)
+			},
+		),
+	)
+	t.Cleanup(server.Close)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@github.com:owner/repo.git")
+	assert.NoError(t, err)
+
+	pullRequests, err := client.GetPullRequests(context.Background(), PullRequestFilter{State: PullRequestOpen})
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []PullRequest{
+			{Id: 2, Title: "Feature", State: PullRequestOpen},
+			{Id: 1, Title: "Fix", State: PullRequestOpen},
+		}, pullRequests,
+	)
+}
+
+func TestGithubClient_GetBuild(t *testing.T) {
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"GET /repos/owner/repo/commits/feature%2Fone/check-runs": `{"check_runs": [
+				{"status": "in_progress", "html_url": "https://github.com/owner/repo/runs/1",
+					"started_at": "2024-01-01T10:00:00Z"},
+				{"status": "completed", "conclusion": "failure", "html_url": "https://github.com/owner/repo/runs/2",
+					"started_at": "2024-01-01T10:00:00Z", "completed_at": "2024-01-01T10:01:00Z"}
+			]}`,
+			"GET /repos/owner/repo/commits/main/check-runs": `{"check_runs": [
+				{"status": "in_progress", "html_url": "https://github.com/owner/repo/runs/3",
+					"started_at": "2024-01-01T10:00:00Z"}
+			]}`,
+			"GET /repos/owner/repo/commits/empty/check-runs":     `{"check_runs": []}`,
+			"GET /repos/owner/repo/commits/feature%2Fone/status": `{"statuses": []}`,
+			"GET /repos/owner/repo/commits/main/status":          `{"statuses": []}`,
+			"GET /repos/owner/repo/commits/empty/status":         `{"statuses": []}`,
+			"GET /repos/owner/repo/commits/jenkins/check-runs":   `{"check_runs": []}`,
+			"GET /repos/owner/repo/commits/jenkins/status": `{"statuses": [
+				{"state": "success", "target_url": "https://ci.example.com/job/1",
+					"created_at": "2024-01-01T10:00:00Z", "updated_at": "2024-01-01T10:01:00Z"},
+				{"state": "error", "target_url": "https://ci.example.com/job/2",
+					"created_at": "2024-01-01T10:00:00Z", "updated_at": "2024-01-01T10:02:00Z"}
+			]}`,
+		},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@github.com:owner/repo.git")
+	assert.NoError(t, err)
+
+	build, err := client.GetBuild(context.Background(), "feature/one")
+	assert.NoError(t, err)
+	assert.Equal(t, &Build{BuildFailed, "https://github.com/owner/repo/runs/2", time.Minute}, build)
+	assert.Equal(t, "per_page=100", (*requests)[0].Query)
+
+	build, err = client.GetBuild(context.Background(), "main")
+	assert.NoError(t, err)
+	assert.Equal(t, BuildRunning, build.State)
+	assert.Equal(t, "https://github.com/owner/repo/runs/3", build.Url)
+	assert.Greater(t, build.Duration, time.Hour)
+
+	build, err = client.GetBuild(context.Background(), "empty")
+	assert.NoError(t, err)
+	assert.Equal(t, &Build{State: BuildNone}, build)
+
+	build, err = client.GetBuild(context.Background(), "jenkins")
+	assert.NoError(t, err)
+	assert.Equal(t, &Build{BuildFailed, "https://ci.example.com/job/2", 2 * time.Minute}, build)
+}
+
+func TestGithubClient_GetBuild_Pages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				response := `{"statuses": []}`
+				if strings.HasSuffix(request.URL.Path, "/check-runs") {
+					response = `{"check_runs": [{"status": "completed", "conclusion": "failure",
+						"html_url": "https://github.com/owner/repo/runs/2"}]}`
+					if request.URL.Query().Get("page") == "" {
+						writer.Header().Set(
+							"Link", fmt.Sprintf(`<%v/repos/owner/repo/commits/main/check-runs?page=2>; rel="next"`, server.URL),
+						)
+						response = `{"check_runs": [{"status": "completed", "conclusion": "success",
+							"html_url": "https://github.com/owner/repo/runs/1"}]}`
+					}
+				}
+				_, err := writer.Write([]byte(response))
+				assert.NoError(t, err)
+			},
+		),
+	)
+	t.Cleanup(server.Close)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@github.com:owner/repo.git")
+	assert.NoError(t, err)
+
+	build, err := client.GetBuild(context.Background(), "main")
+	assert.NoError(t, err)
+	assert.Equal(t, &Build{State: BuildFailed, Url: "https://github.com/owner/repo/runs/2"}, build)
+}
+
+func TestGithubClient_SetBranchProtection(t *testing.T) {
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"GET /repos/owner/repo/branches/protected/protection": `{
+				"required_status_checks": {"contexts": ["build"]},
+				"required_pull_request_reviews": {"required_approving_review_count": 1}
+			}`,
+			"PATCH /repos/owner/repo/branches/protected/protection/required_pull_request_reviews": `{}`,
+			"PUT /repos/owner/repo/branches/main/protection":                                      `{}`,
+			"GET /repos/owner/repo/branches/main":                                                 `{"protected": false}`,
+		},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@github.com:owner/repo.git")
+	assert.NoError(t, err)
+
+	protection, err := client.GetBranchProtection(context.Background(), "main")
+	assert.NoError(t, err)
+	assert.Nil(t, protection)
+
+	err = client.SetBranchProtection(context.Background(), "main", BranchProtection{RequiredReviews: 2})
+	assert.NoError(t, err)
+	err = client.SetBranchProtection(context.Background(), "protected", BranchProtection{RequiredReviews: 2})
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t, []tests.HostingRequest{
+			{Method: "GET", Path: "/repos/owner/repo/branches/main/protection"},
+			{Method: "GET", Path: "/repos/owner/repo/branches/main"},
+			{Method: "GET", Path: "/repos/owner/repo/branches/main/protection"},
+			{Method: "GET", Path: "/repos/owner/repo/branches/main"},
+			{
+				Method: "PUT", Path: "/repos/owner/repo/branches/main/protection",
+				Body: map[string]interface{}{
+					"required_status_checks": nil, "enforce_admins": nil, "restrictions": nil,
+					"required_pull_request_reviews": map[string]interface{}{"required_approving_review_count": float64(2)},
+				},
+			},
+			{Method: "GET", Path: "/repos/owner/repo/branches/protected/protection"},
+			{
+				Method: "PATCH", Path: "/repos/owner/repo/branches/protected/protection/required_pull_request_reviews",
+				Body: map[string]interface{}{"required_approving_review_count": float64(2)},
+			},
+		}, *requests,
+	)
+}
+
+func TestGithubClient_GetBranchProtection_NoAdminRights(t *testing.T) {
+	server, _ := tests.StartHostingServer(
+		t, map[string]string{"GET /repos/owner/repo/branches/main": `{"protected": true}`},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@github.com:owner/repo.git")
+	assert.NoError(t, err)
+
+	_, err = client.GetBranchProtection(context.Background(), "main")
+	assert.EqualError(t, err, "branch main is protected, but its protection can't be read without admin rights")
+}
@@ -0,0 +1,360 @@
+package hosting
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+type gitlabClient struct {
+	api        *apiClient
+	repository Repository
+}
+
+type gitlabMergeRequest struct {
+	Iid          int    `json:"iid"`
+	Title        string `json:"title"`
+	State        string `json:"state"`
+	WebUrl       string `json:"web_url"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	Author       struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+func (r *gitlabMergeRequest) toPullRequest() PullRequest {
+	var state PullRequestState
+	switch r.State {
+	case "opened":
+		state = PullRequestOpen
+	case "merged":
+		state = PullRequestMerged
+	default:
+		state = PullRequestClosed
+	}
+
+	return PullRequest{
+		Id:     r.Iid,
+		Title:  r.Title,
+		State:  state,
+		Source: r.SourceBranch,
+		Target: r.TargetBranch,
+		Author: r.Author.Username,
+		Url:    r.WebUrl,
+	}
+}
+
+// projectPath refers to the project by its full path, that works with nested groups as well
+func (c *gitlabClient) projectPath() string {
+	return "/projects/" + url.PathEscape(c.repository.Path)
+}
+
+func (c *gitlabClient) getUserId(ctx context.Context, username string) (int, error) {
+	var response []struct {
+		Id int `json:"id"`
+	}
+	err := c.api.do(ctx, http.MethodGet, "/users", url.Values{"username": {username}}, nil, &response)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user %v: %w", username, err)
+	}
+
+	if len(response) == 0 {
+		return 0, fmt.Errorf("user %v: %w", username, ErrNotFound)
+	}
+
+	return response[0].Id, nil
+}
+
+func (c *gitlabClient) CreatePullRequest(ctx context.Context, options CreatePullRequestOptions) (*PullRequest, error) {
+	reviewerIds := []int{}
+	for _, reviewer := range options.Reviewers {
+		userId, err := c.getUserId(ctx, reviewer)
+		if err != nil {
+			return nil, err
+		}
+
+		reviewerIds = append(reviewerIds, userId)
+	}
+
+	request := map[string]interface{}{
+		"title":         options.Title,
+		"description":   options.Body,
+		"source_branch": options.Source,
+		"target_branch": options.Target,
+		"reviewer_ids":  reviewerIds,
+	}
+	var response gitlabMergeRequest
+	err := c.api.do(ctx, http.MethodPost, c.projectPath()+"/merge_requests", nil, request, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	result := response.toPullRequest()
+	return &result, nil
+}
+
+func (c *gitlabClient) GetPullRequests(ctx context.Context, filter PullRequestFilter) ([]PullRequest, error) {
+	query := url.Values{"per_page": {"100"}}
+	switch filter.State {
+	case PullRequestOpen:
+		query.Set("state", "opened")
+	case PullRequestMerged, PullRequestClosed:
+		query.Set("state", string(filter.State))
+	}
+	if filter.Source != "" {
+		query.Set("source_branch", filter.Source)
+	}
+
+	pages, err := getPages(
+		ctx, c.api, c.projectPath()+"/merge_requests", query, func(header http.Header, _ []gitlabMergeRequest) string {
+			return linkNextPage(header)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge requests: %w", err)
+	}
+
+	var result []PullRequest
+	for _, item := range slices.Concat(pages...) {
+		pullRequest := item.toPullRequest()
+		if filter.matches(pullRequest) {
+			result = append(result, pullRequest)
+		}
+	}
+
+	return result, nil
+}
+
+type gitlabPipeline struct {
+	Status string `json:"status"`
+	WebUrl string `json:"web_url"`
+	// Duration is the number of seconds the pipeline took, it's empty until the pipeline is finished
+	Duration  *int       `json:"duration"`
+	StartedAt *time.Time `json:"started_at"`
+}
+
+func (p *gitlabPipeline) state() BuildState {
+	switch p.Status {
+	case "success":
+		return BuildSuccess
+	case "failed":
+		return BuildFailed
+	case "canceled", "skipped":
+		return BuildCanceled
+	case "running":
+		return BuildRunning
+	default:
+		return BuildPending
+	}
+}
+
+// GetBuild gets the latest pipeline of a branch or a tag. The latest pipeline endpoint doesn't accept commits,
+// so the pipeline of a commit is found in the list of pipelines and then requested by its id
+func (c *gitlabClient) GetBuild(ctx context.Context, ref string) (*Build, error) {
+	path := c.projectPath() + "/pipelines/latest"
+	query := url.Values{"ref": {ref}}
+	if isCommitHash(ref) {
+		var pipelines []struct {
+			Id int `json:"id"`
+		}
+		query = url.Values{"sha": {ref}, "order_by": {"id"}, "sort": {"desc"}, "per_page": {"1"}}
+		err := c.api.do(ctx, http.MethodGet, c.projectPath()+"/pipelines", query, nil, &pipelines)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pipelines: %w", err)
+		}
+
+		if len(pipelines) == 0 {
+			return &Build{State: BuildNone}, nil
+		}
+
+		path = fmt.Sprintf("%v/pipelines/%v", c.projectPath(), pipelines[0].Id)
+		query = nil
+	}
+
+	var response gitlabPipeline
+	err := c.api.do(ctx, http.MethodGet, path, query, nil, &response)
+	if errors.Is(err, ErrNotFound) {
+		return &Build{State: BuildNone}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline: %w", err)
+	}
+
+	duration := durationSince(response.StartedAt, nil)
+	if response.Duration != nil {
+		duration = time.Duration(*response.Duration) * time.Second
+	}
+
+	return &Build{State: response.state(), Url: response.WebUrl, Duration: duration}, nil
+}
+
+func (c *gitlabClient) GetDefaultBranch(ctx context.Context) (string, error) {
+	var response struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	err := c.api.do(ctx, http.MethodGet, c.projectPath(), nil, nil, &response)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return response.DefaultBranch, nil
+}
+
+func (c *gitlabClient) SetDefaultBranch(ctx context.Context, branch string) error {
+	err := c.api.do(ctx, http.MethodPut, c.projectPath(), nil, map[string]string{"default_branch": branch}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return nil
+}
+
+// gitlabApprovalRuleName is the name of the approval rule that bulker creates
+const gitlabApprovalRuleName = "Required reviews"
+
+type gitlabApprovalRule struct {
+	Id                            int    `json:"id"`
+	Name                          string `json:"name"`
+	ApprovalsRequired             int    `json:"approvals_required"`
+	AppliesToAllProtectedBranches bool   `json:"applies_to_all_protected_branches"`
+	ProtectedBranches             []struct {
+		Name string `json:"name"`
+	} `json:"protected_branches"`
+}
+
+func (r *gitlabApprovalRule) appliesTo(branch string) bool {
+	if r.AppliesToAllProtectedBranches || len(r.ProtectedBranches) == 0 {
+		return true
+	}
+
+	for _, protectedBranch := range r.ProtectedBranches {
+		if protectedBranch.Name == branch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getApprovalRules returns the merge request approval rules of the project that apply to the branch
+func (c *gitlabClient) getApprovalRules(ctx context.Context, branch string) ([]gitlabApprovalRule, error) {
+	var response []gitlabApprovalRule
+	err := c.api.do(ctx, http.MethodGet, c.projectPath()+"/approval_rules", nil, nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval rules: %w", err)
+	}
+
+	var result []gitlabApprovalRule
+	for _, rule := range response {
+		if rule.appliesTo(branch) {
+			result = append(result, rule)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *gitlabClient) GetBranchProtection(ctx context.Context, branch string) (*BranchProtection, error) {
+	path := c.projectPath() + "/protected_branches/" + url.PathEscape(branch)
+	err := c.api.do(ctx, http.MethodGet, path, nil, nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get protected branch: %w", err)
+	}
+
+	rules, err := c.getApprovalRules(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BranchProtection{}
+	for _, rule := range rules {
+		result.RequiredReviews = max(result.RequiredReviews, rule.ApprovalsRequired)
+	}
+
+	return result, nil
+}
+
+// SetBranchProtection creates an approval rule if none applies to the branch.
+// The required reviews are the maximum of the rules, so to lower them every rule that requires more is updated,
+// and to raise them the rule bulker created is updated, or the one that requires the most
+func (c *gitlabClient) SetBranchProtection(ctx context.Context, branch string, protection BranchProtection) error {
+	current, err := c.GetBranchProtection(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		err = c.api.do(
+			ctx, http.MethodPost, c.projectPath()+"/protected_branches", nil, map[string]string{"name": branch}, nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to protect branch: %w", err)
+		}
+		current = &BranchProtection{}
+	}
+
+	if current.RequiredReviews == protection.RequiredReviews {
+		return nil
+	}
+
+	rules, err := c.getApprovalRules(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		request := map[string]interface{}{
+			"name":               gitlabApprovalRuleName,
+			"approvals_required": protection.RequiredReviews,
+		}
+		err = c.api.do(ctx, http.MethodPost, c.projectPath()+"/approval_rules", nil, request, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create approval rule: %w", err)
+		}
+		return nil
+	}
+
+	var updatedRules []gitlabApprovalRule
+	if protection.RequiredReviews < current.RequiredReviews {
+		for _, rule := range rules {
+			if rule.ApprovalsRequired > protection.RequiredReviews {
+				updatedRules = append(updatedRules, rule)
+			}
+		}
+	} else {
+		updatedRule := slices.MaxFunc(
+			rules, func(a gitlabApprovalRule, b gitlabApprovalRule) int {
+				return cmp.Compare(a.ApprovalsRequired, b.ApprovalsRequired)
+			},
+		)
+		index := slices.IndexFunc(
+			rules, func(rule gitlabApprovalRule) bool {
+				return rule.Name == gitlabApprovalRuleName
+			},
+		)
+		if index >= 0 {
+			updatedRule = rules[index]
+		}
+		updatedRules = append(updatedRules, updatedRule)
+	}
+
+	for _, rule := range updatedRules {
+		path := fmt.Sprintf("%v/approval_rules/%v", c.projectPath(), rule.Id)
+		request := map[string]int{"approvals_required": protection.RequiredReviews}
+		err = c.api.do(ctx, http.MethodPut, path, nil, request, nil)
+		if err != nil {
+			return fmt.Errorf("failed to update approval rule: %w", err)
+		}
+	}
+
+	return nil
+}
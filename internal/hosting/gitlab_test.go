@@ -0,0 +1,213 @@
+package hosting
+
+import (
+	"context"
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGitlabClient_CreatePullRequest(t *testing.T) {
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"GET /users": `[{"id": 42}]`,
+			"POST /projects/group%2Fsub%2Frepo/merge_requests": `{"iid": 5, "title": "Title", "state": "opened",
+				"web_url": "https://gitlab.com/group/sub/repo/-/merge_requests/5",
+				"source_branch": "feature", "target_branch": "main", "author": {"username": "alice"}}`,
+		},
+	)
+	client, err := NewClient(
+		&config.Config{HostingApiUrl: server.URL, HostingToken: "secret"}, "git@gitlab.com:group/sub/repo.git",
+	)
+	assert.NoError(t, err)
+
+	pullRequest, err := client.CreatePullRequest(
+		context.Background(), CreatePullRequestOptions{
+			Source: "feature", Target: "main", Title: "Title", Body: "Body", Reviewers: []string{"bob"},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &PullRequest{
+			Id: 5, Title: "Title", State: PullRequestOpen, Source: "feature", Target: "main", Author: "alice",
+			Url: "https://gitlab.com/group/sub/repo/-/merge_requests/5",
+		}, pullRequest,
+	)
+	assert.Equal(
+		t, []tests.HostingRequest{
+			{Method: "GET", Path: "/users", Query: "username=bob", Authorization: "secret"},
+			{
+				Method: "POST", Path: "/projects/group%2Fsub%2Frepo/merge_requests", Authorization: "secret",
+				Body: map[string]interface{}{
+					"title": "Title", "description": "Body", "source_branch": "feature", "target_branch": "main",
+					"reviewer_ids": []interface{}{float64(42)},
+				},
+			},
+		}, *requests,
+	)
+}
+
+func TestGitlabClient_GetPullRequests(t *testing.T) {
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"GET /projects/group%2Frepo/merge_requests": `[
+				{"iid": 2, "title": "Feature", "state": "opened", "web_url": "https://gitlab.com/group/repo/-/merge_requests/2",
+					"source_branch": "feature", "target_branch": "main", "author": {"username": "alice"}},
+				{"iid": 1, "title": "Fix", "state": "closed", "web_url": "https://gitlab.com/group/repo/-/merge_requests/1",
+					"source_branch": "fix", "target_branch": "main", "author": {"username": "bob"}}
+			]`,
+		},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "https://gitlab.com/group/repo.git")
+	assert.NoError(t, err)
+
+	pullRequests, err := client.GetPullRequests(context.Background(), PullRequestFilter{State: PullRequestAll})
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []PullRequest{
+			{
+				Id: 2, Title: "Feature", State: PullRequestOpen, Source: "feature", Target: "main", Author: "alice",
+				Url: "https://gitlab.com/group/repo/-/merge_requests/2",
+			},
+			{
+				Id: 1, Title: "Fix", State: PullRequestClosed, Source: "fix", Target: "main", Author: "bob",
+				Url: "https://gitlab.com/group/repo/-/merge_requests/1",
+			},
+		}, pullRequests,
+	)
+	assert.Equal(t, "per_page=100", (*requests)[0].Query)
+
+	_, err = client.GetPullRequests(context.Background(), PullRequestFilter{Source: "feature", State: PullRequestOpen})
+	assert.NoError(t, err)
+	assert.Equal(t, "per_page=100&source_branch=feature&state=opened", (*requests)[1].Query)
+}
+
+func TestGitlabClient_GetBuild(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"GET /projects/group%2Frepo/pipelines/latest": `{"status": "running",
+				"web_url": "https://gitlab.com/group/repo/-/pipelines/5", "started_at": "2024-01-01T10:00:00Z"}`,
+			"GET /projects/group%2Frepo/pipelines": `[{"id": 7}]`,
+			"GET /projects/group%2Frepo/pipelines/7": `{"status": "failed", "duration": 90,
+				"web_url": "https://gitlab.com/group/repo/-/pipelines/7"}`,
+		},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "https://gitlab.com/group/repo.git")
+	assert.NoError(t, err)
+
+	build, err := client.GetBuild(context.Background(), "main")
+	assert.NoError(t, err)
+	assert.Equal(t, BuildRunning, build.State)
+	assert.Equal(t, "https://gitlab.com/group/repo/-/pipelines/5", build.Url)
+	assert.Greater(t, build.Duration, time.Hour)
+	assert.Equal(t, "ref=main", (*requests)[0].Query)
+
+	build, err = client.GetBuild(context.Background(), hash)
+	assert.NoError(t, err)
+	assert.Equal(t, &Build{BuildFailed, "https://gitlab.com/group/repo/-/pipelines/7", 90 * time.Second}, build)
+	assert.Equal(t, "order_by=id&per_page=1&sha="+hash+"&sort=desc", (*requests)[1].Query)
+	assert.Equal(t, "/projects/group%2Frepo/pipelines/7", (*requests)[2].Path)
+}
+
+func TestGitlabClient_GetBuild_None(t *testing.T) {
+	server, _ := tests.StartHostingServer(t, map[string]string{"GET /projects/group%2Frepo/pipelines": `[]`})
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "https://gitlab.com/group/repo.git")
+	assert.NoError(t, err)
+
+	build, err := client.GetBuild(context.Background(), "main")
+	assert.NoError(t, err)
+	assert.Equal(t, &Build{State: BuildNone}, build)
+
+	build, err = client.GetBuild(context.Background(), "0123456789abcdef0123456789abcdef01234567")
+	assert.NoError(t, err)
+	assert.Equal(t, &Build{State: BuildNone}, build)
+}
+
+func TestGitlabClient_SetBranchProtection(t *testing.T) {
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"GET /projects/group%2Frepo/protected_branches/main": `{"name": "main"}`,
+			"GET /projects/group%2Frepo/approval_rules": `[
+				{"id": 1, "approvals_required": 1, "protected_branches": [{"name": "develop"}]},
+				{"id": 2, "approvals_required": 1, "applies_to_all_protected_branches": true}
+			]`,
+			"PUT /projects/group%2Frepo/approval_rules/2": `{}`,
+		},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@gitlab.com:group/repo.git")
+	assert.NoError(t, err)
+
+	protection, err := client.GetBranchProtection(context.Background(), "main")
+	assert.NoError(t, err)
+	assert.Equal(t, &BranchProtection{RequiredReviews: 1}, protection)
+
+	err = client.SetBranchProtection(context.Background(), "main", BranchProtection{RequiredReviews: 2})
+	assert.NoError(t, err)
+	assert.Equal(
+		t, tests.HostingRequest{
+			Method: "PUT", Path: "/projects/group%2Frepo/approval_rules/2",
+			Body: map[string]interface{}{"approvals_required": float64(2)},
+		}, (*requests)[len(*requests)-1],
+	)
+}
+
+func TestGitlabClient_SetBranchProtection_SeveralRules(t *testing.T) {
+	server, requests := tests.StartHostingServer(
+		t, map[string]string{
+			"GET /projects/group%2Frepo/protected_branches/main": `{"name": "main"}`,
+			"GET /projects/group%2Frepo/approval_rules": `[
+				{"id": 1, "name": "Team", "approvals_required": 1},
+				{"id": 2, "name": "Security", "approvals_required": 2},
+				{"id": 3, "name": "Owners", "approvals_required": 2}
+			]`,
+			"PUT /projects/group%2Frepo/approval_rules/1": `{}`,
+			"PUT /projects/group%2Frepo/approval_rules/2": `{}`,
+			"PUT /projects/group%2Frepo/approval_rules/3": `{}`,
+		},
+	)
+	client, err := NewClient(&config.Config{HostingApiUrl: server.URL}, "git@gitlab.com:group/repo.git")
+	assert.NoError(t, err)
+
+	updatedRules := func() []string {
+		var result []string
+		for _, request := range *requests {
+			if request.Method == http.MethodPut {
+				result = append(result, request.Path)
+			}
+		}
+		*requests = nil
+		return result
+	}
+
+	err = client.SetBranchProtection(context.Background(), "main", BranchProtection{RequiredReviews: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/projects/group%2Frepo/approval_rules/2"}, updatedRules())
+
+	err = client.SetBranchProtection(context.Background(), "main", BranchProtection{RequiredReviews: 1})
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []string{"/projects/group%2Frepo/approval_rules/2", "/projects/group%2Frepo/approval_rules/3"},
+		updatedRules(),
+	)
+
+	server, requests = tests.StartHostingServer(
+		t, map[string]string{
+			"GET /projects/group%2Frepo/protected_branches/main": `{"name": "main"}`,
+			"GET /projects/group%2Frepo/approval_rules": `[
+				{"id": 1, "name": "Team", "approvals_required": 2},
+				{"id": 4, "name": "Required reviews", "approvals_required": 1}
+			]`,
+			"PUT /projects/group%2Frepo/approval_rules/4": `{}`,
+		},
+	)
+	client, err = NewClient(&config.Config{HostingApiUrl: server.URL}, "git@gitlab.com:group/repo.git")
+	assert.NoError(t, err)
+
+	err = client.SetBranchProtection(context.Background(), "main", BranchProtection{RequiredReviews: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/projects/group%2Frepo/approval_rules/4"}, updatedRules())
+}
@@ -0,0 +1,48 @@
+package hosting
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is the expected settings of repositories on the hosting. Empty settings are not checked
+type Policy struct {
+	DefaultBranch string `yaml:"defaultBranch"`
+	// BranchProtection requires the default branch to be protected
+	BranchProtection bool `yaml:"branchProtection"`
+	// RequiredReviews is the minimal number of approvals to merge a pull request to the default branch.
+	// It requires the default branch to be protected as well
+	RequiredReviews int `yaml:"requiredReviews"`
+}
+
+// BranchProtection is a protection of a branch against force pushes and merges without reviews
+type BranchProtection struct {
+	RequiredReviews int
+}
+
+// ReadPolicy reads a policy from a YAML file. Unknown keys are reported to catch typos
+func ReadPolicy(fileName string) (*Policy, error) {
+	fileContent, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(fileContent))
+	decoder.KnownFields(true)
+
+	result := &Policy{}
+	err = decoder.Decode(result)
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("policy file %v is empty", fileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %v: %w", fileName, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package hosting
+
+import "fmt"
+
+// PullRequestState implements Value in spf13/pflag for custom flag type
+type PullRequestState string
+
+const (
+	PullRequestOpen   PullRequestState = "open"
+	PullRequestMerged PullRequestState = "merged"
+	PullRequestClosed PullRequestState = "closed"
+	// PullRequestAll matches pull requests in any state
+	PullRequestAll PullRequestState = "all"
+)
+
+func (s *PullRequestState) String() string {
+	return string(*s)
+}
+
+func (s *PullRequestState) Set(v string) error {
+	switch v {
+	case string(PullRequestOpen), string(PullRequestMerged), string(PullRequestClosed), string(PullRequestAll):
+		*s = PullRequestState(v)
+		return nil
+	default:
+		return fmt.Errorf(
+			"must be one of '%s' '%s' '%s' '%s'", PullRequestOpen, PullRequestMerged, PullRequestClosed, PullRequestAll,
+		)
+	}
+}
+
+func (s *PullRequestState) Type() string {
+	return "PullRequestState"
+}
+
+// PullRequest is a pull request of GitHub and Bitbucket, or a merge request of GitLab
+type PullRequest struct {
+	// Id is the number of the pull request in the repository
+	Id     int
+	Title  string
+	State  PullRequestState
+	Source string
+	Target string
+	Author string
+	// Url is the web page of the pull request
+	Url string
+}
+
+type CreatePullRequestOptions struct {
+	Source string
+	Target string
+	Title  string
+	Body   string
+	// Reviewers are user names for GitHub and GitLab, and account ids or UUIDs for Bitbucket
+	Reviewers []string
+}
+
+type PullRequestFilter struct {
+	// Source is the branch pull requests are created from. Any branch matches if empty
+	Source string
+	State  PullRequestState
+}
+
+// matches checks the state of the pull request, for the providers that can't filter by every state
+func (f *PullRequestFilter) matches(pullRequest PullRequest) bool {
+	return f.State == PullRequestAll || f.State == pullRequest.State
+}
@@ -0,0 +1,12 @@
+package hosting
+
+type PullRequestResult string
+
+const (
+	PullRequestCreated PullRequestResult = "created"
+	PullRequestExists  PullRequestResult = "already exists"
+)
+
+func (r *PullRequestResult) String() string {
+	return string(*r)
+}
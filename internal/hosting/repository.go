@@ -0,0 +1,46 @@
+package hosting
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Repository identifies a repository on the hosting
+type Repository struct {
+	Host string
+	// Path is the full path of the repository, like "owner/name", or "group/subgroup/name" for GitLab
+	Path string
+}
+
+// Owner returns the first segment of the repository path, that is a user, an organisation or a workspace
+func (r *Repository) Owner() string {
+	owner, _, _ := strings.Cut(r.Path, "/")
+	return owner
+}
+
+var scpLikeUrlRegexp = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// ParseRepository gets the host and the path of the repository from its HTTP, SSH or scp-like git URL
+func ParseRepository(repoUrl string) (Repository, error) {
+	var host, path string
+	if strings.Contains(repoUrl, "://") {
+		parsedUrl, err := url.Parse(repoUrl)
+		if err != nil {
+			return Repository{}, fmt.Errorf("failed to parse repository URL %v: %w", repoUrl, err)
+		}
+		host = parsedUrl.Hostname()
+		path = parsedUrl.Path
+	} else if submatch := scpLikeUrlRegexp.FindStringSubmatch(repoUrl); submatch != nil {
+		host = submatch[1]
+		path = submatch[2]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return Repository{}, fmt.Errorf("unsupported repository URL: %v", repoUrl)
+	}
+
+	return Repository{Host: host, Path: path}, nil
+}
@@ -0,0 +1,33 @@
+package hosting
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseRepository(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected Repository
+	}{
+		{"https://github.com/owner/repo.git", Repository{"github.com", "owner/repo"}},
+		{"https://github.com/owner/repo", Repository{"github.com", "owner/repo"}},
+		{"git@github.com:owner/repo.git", Repository{"github.com", "owner/repo"}},
+		{"ssh://git@gitlab.example.com:2222/group/sub/repo.git", Repository{"gitlab.example.com", "group/sub/repo"}},
+		{"https://user@bitbucket.org/workspace/repo.git", Repository{"bitbucket.org", "workspace/repo"}},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.url, func(t *testing.T) {
+				actual, err := ParseRepository(test.url)
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, actual)
+			},
+		)
+	}
+}
+
+func TestParseRepository_Unsupported(t *testing.T) {
+	_, err := ParseRepository("/local/path/repo")
+	assert.ErrorContains(t, err, "unsupported repository URL")
+}
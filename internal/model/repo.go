@@ -7,4 +7,6 @@ type Repo struct {
 	Path string
 	// Url git address of the repository
 	Url string
+	// Tags of the repository in the settings
+	Tags []string
 }
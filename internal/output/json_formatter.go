@@ -2,8 +2,6 @@ package output
 
 import (
 	"encoding/json"
-	"maps"
-	"slices"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,10 +15,8 @@ func (w JsonFormatter) FormatMessage(value map[string]EntityInfo) string {
 	//goland:noinspection GoPreferNilSlice
 	valueToLog := []any{}
 
-	keys := slices.Sorted(maps.Keys(value))
-
-	for _, key := range keys {
-		entry := createValueToLogEntry(w.entityName, key, value[key])
+	for _, row := range entityRows(value) {
+		entry := createValueToLogEntry(w.entityName, row.key, row.info)
 		valueToLog = append(valueToLog, entry)
 	}
 
@@ -3,8 +3,6 @@ package output
 import (
 	"bytes"
 	"fmt"
-	"maps"
-	"slices"
 	"strings"
 )
 
@@ -14,14 +12,12 @@ type LineFormatter struct {
 func (w LineFormatter) FormatMessage(value map[string]EntityInfo) string {
 	buffer := &bytes.Buffer{}
 
-	keys := slices.Sorted(maps.Keys(value))
-
-	for _, key := range keys {
-		infoString := infoToString(value[key])
+	for _, row := range entityRows(value) {
+		infoString := infoToString(row.info)
 		if infoString == "" {
-			buffer.WriteString(fmt.Sprintln(key))
+			buffer.WriteString(fmt.Sprintln(row.key))
 		} else {
-			buffer.WriteString(fmt.Sprintf("%v: %v\n", key, infoString))
+			buffer.WriteString(fmt.Sprintf("%v: %v\n", row.key, infoString))
 		}
 	}
 
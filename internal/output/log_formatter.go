@@ -2,8 +2,6 @@ package output
 
 import (
 	"bytes"
-	"maps"
-	"slices"
 
 	"github.com/sirupsen/logrus"
 )
@@ -16,14 +14,12 @@ type LogFormatter struct {
 func (w LogFormatter) FormatMessage(value map[string]EntityInfo) string {
 	buffer := &bytes.Buffer{}
 
-	keys := slices.Sorted(maps.Keys(value))
-
-	for _, key := range keys {
+	for _, row := range entityRows(value) {
 		logger := logrus.New()
 		logger.SetOutput(buffer)
-		loggerEntry := logger.WithField(w.entityName, key)
+		loggerEntry := logger.WithField(w.entityName, row.key)
 
-		info := value[key]
+		info := row.info
 		loggerEntry = addLoggerEntries(loggerEntry, info.Result)
 		if info.Error != nil {
 			loggerEntry.WithError(info.Error).Errorln()
@@ -5,7 +5,9 @@ import (
 	"github.com/mih-kopylov/bulker/internal/config"
 	"github.com/spf13/viper"
 	"io"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -54,6 +56,57 @@ func createFormatter(entityName string) (Formatter, error) {
 	return nil, fmt.Errorf("unsupported output format: %v", outputFormat)
 }
 
+type entityRow struct {
+	key  string
+	info EntityInfo
+}
+
+// entityRows returns the entities sorted by their keys.
+//
+// A result that is a slice of structures is expanded to a row per element in the slice order,
+// all of them having the same key. That way, a repository can report a number of items, like branches.
+func entityRows(value map[string]EntityInfo) []entityRow {
+	var result []entityRow
+
+	for _, key := range slices.Sorted(maps.Keys(value)) {
+		info := value[key]
+		if !isStructSlice(info.Result) {
+			result = append(result, entityRow{key: key, info: info})
+			continue
+		}
+
+		items := reflect.ValueOf(info.Result)
+		if items.Len() == 0 {
+			result = append(result, entityRow{key: key, info: EntityInfo{Error: info.Error}})
+			continue
+		}
+
+		for i := 0; i < items.Len(); i++ {
+			result = append(result, entityRow{key: key, info: EntityInfo{Result: items.Index(i).Interface(), Error: info.Error}})
+		}
+	}
+
+	return result
+}
+
+func isStructSlice(value any) bool {
+	if value == nil {
+		return false
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType.Kind() != reflect.Slice {
+		return false
+	}
+
+	elementType := valueType.Elem()
+	if elementType.Kind() == reflect.Pointer {
+		elementType = elementType.Elem()
+	}
+
+	return elementType.Kind() == reflect.Struct
+}
+
 type keyValue struct {
 	key   string
 	value any
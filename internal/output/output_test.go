@@ -79,3 +79,27 @@ func TestValueKeys_KeysInSourceOrder(t *testing.T) {
 		keys,
 	)
 }
+
+func TestEntityRows_StructSliceExpanded(t *testing.T) {
+	type a struct {
+		Message string
+	}
+	rows := entityRows(
+		map[string]EntityInfo{
+			"b": {Result: []a{{"second"}, {"first"}}},
+			"a": {Result: "single"},
+			"c": {Result: []a{}},
+			"d": {Result: []string{"strings", "are", "kept"}},
+		},
+	)
+
+	assert.Equal(
+		t, []entityRow{
+			{key: "a", info: EntityInfo{Result: "single"}},
+			{key: "b", info: EntityInfo{Result: a{"second"}}},
+			{key: "b", info: EntityInfo{Result: a{"first"}}},
+			{key: "c", info: EntityInfo{}},
+			{key: "d", info: EntityInfo{Result: []string{"strings", "are", "kept"}}},
+		}, rows,
+	)
+}
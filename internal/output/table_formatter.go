@@ -3,8 +3,6 @@ package output
 import (
 	"bytes"
 	"fmt"
-	"maps"
-	"slices"
 
 	"github.com/aquasecurity/table"
 )
@@ -18,7 +16,7 @@ func (w TableFormatter) FormatMessage(value map[string]EntityInfo) string {
 		return ""
 	}
 
-	keys := slices.Sorted(maps.Keys(value))
+	rows := entityRows(value)
 
 	buffer := &bytes.Buffer{}
 
@@ -27,19 +25,19 @@ func (w TableFormatter) FormatMessage(value map[string]EntityInfo) string {
 
 	var headerRow []string
 	headerRow = append(headerRow, w.entityName)
-	hasError := anyLineHasError(value)
+	hasError := anyLineHasError(rows)
 	if hasError {
 		headerRow = append(headerRow, "error")
 	}
-	entityKeys := getEntityKeys(value)
+	entityKeys := getEntityKeys(rows)
 	headerRow = append(headerRow, entityKeys...)
 	t.SetHeaders(headerRow...)
 
-	for _, key := range keys {
-		entryValue := value[key]
+	for _, entityRow := range rows {
+		entryValue := entityRow.info
 		var row []string
 
-		row = append(row, key)
+		row = append(row, entityRow.key)
 
 		if hasError {
 			errorValue := ""
@@ -73,18 +71,18 @@ func (w TableFormatter) FormatMessage(value map[string]EntityInfo) string {
 	return buffer.String()
 }
 
-func getEntityKeys(value map[string]EntityInfo) []string {
-	for _, entryValue := range value {
-		if entryValue.Result != nil {
-			return valueKeys(entryValue.Result)
+func getEntityKeys(rows []entityRow) []string {
+	for _, row := range rows {
+		if row.info.Result != nil {
+			return valueKeys(row.info.Result)
 		}
 	}
 	return nil
 }
 
-func anyLineHasError(value map[string]EntityInfo) bool {
-	for _, entryValue := range value {
-		if entryValue.Error != nil {
+func anyLineHasError(rows []entityRow) bool {
+	for _, row := range rows {
+		if row.info.Error != nil {
 			return true
 		}
 	}
@@ -13,6 +13,8 @@ type Filter struct {
 	Names  []string
 	Tags   []string
 	Groups []string
+	// Worktree makes the commands run in the named worktree of each repository instead of the repository directory
+	Worktree string
 }
 
 func (f *Filter) MatchesRepo(repo settings.Repo, groups []settings.Group) bool {
@@ -33,6 +35,15 @@ func (f *Filter) FilterMatchingRepos(repos []settings.Repo, groups []settings.Gr
 }
 
 func (f *Filter) AddCommandFlags(command *cobra.Command) {
+	f.AddRepoFlags(command)
+	command.Flags().StringVar(
+		&f.Worktree, "worktree", "", "Name of the worktree to process instead of the repository directory",
+	)
+}
+
+// AddRepoFlags adds the flags that select repositories, but not their worktrees.
+// It's used by the commands that work with the repository directory only
+func (f *Filter) AddRepoFlags(command *cobra.Command) {
 	command.Flags().StringSliceVarP(
 		&f.Names, "name", "n", []string{},
 		"Names of the repositories to process. Can be regexp",
@@ -35,7 +35,7 @@ func (r *ParallelRunner) Run(
 		WithField("workers", r.config.MaxWorkers).
 		Debug("processing repositories")
 	for _, repo := range repos {
-		runContext := newRunContext(r.manager, r.config, r.args, repo)
+		runContext := newRunContext(r.manager, r.config, r.filter, r.args, repo)
 		pool.Submit(
 			func() {
 				select {
@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
@@ -48,6 +49,31 @@ func NewRunner(conf *config.Config, sh shell.Shell, filter *Filter, progress Pro
 func NewCommandRunner(filter *Filter, sh shell.Shell, handler RepoHandler) func(
 	cmd *cobra.Command,
 	args []string,
+) error {
+	return newCommandRunner(filter, sh, handler, nil)
+}
+
+func NewCommandRunnerForExistingRepos(filter *Filter, sh shell.Shell, handler RepoHandler) func(
+	cmd *cobra.Command,
+	args []string,
+) error {
+	return NewCommandRunner(filter, sh, verifyRepoExistence(handler))
+}
+
+// NewGroupingCommandRunnerForExistingRepos works as NewCommandRunnerForExistingRepos,
+// but the results of all repositories are regrouped by `grouper` before they are printed
+func NewGroupingCommandRunnerForExistingRepos(
+	filter *Filter, sh shell.Shell, handler RepoHandler, grouper ResultGrouper,
+) func(
+	cmd *cobra.Command,
+	args []string,
+) error {
+	return newCommandRunner(filter, sh, verifyRepoExistence(handler), grouper)
+}
+
+func newCommandRunner(filter *Filter, sh shell.Shell, handler RepoHandler, grouper ResultGrouper) func(
+	cmd *cobra.Command,
+	args []string,
 ) error {
 	return func(cmd *cobra.Command, args []string) error {
 		conf := config.ReadConfig()
@@ -87,7 +113,12 @@ func NewCommandRunner(filter *Filter, sh shell.Shell, handler RepoHandler) func(
 			return err
 		}
 
-		err = logOutput(cmd.OutOrStdout(), allReposResult)
+		entityName := "repo"
+		if grouper != nil {
+			entityName, allReposResult = grouper(allReposResult)
+		}
+
+		err = logOutput(cmd.OutOrStdout(), entityName, allReposResult)
 		if err != nil {
 			return err
 		}
@@ -96,77 +127,81 @@ func NewCommandRunner(filter *Filter, sh shell.Shell, handler RepoHandler) func(
 	}
 }
 
-func NewCommandRunnerForExistingRepos(filter *Filter, sh shell.Shell, handler RepoHandler) func(
-	cmd *cobra.Command,
-	args []string,
-) error {
-	handlerWithRepoExistenceVerifier := func(ctx context.Context, runContext *RunContext) (interface{}, error) {
+func verifyRepoExistence(handler RepoHandler) RepoHandler {
+	return func(ctx context.Context, runContext *RunContext) (interface{}, error) {
 		err := fileops.CheckRepoExists(runContext.Repo)
 		if err != nil {
+			if runContext.Worktree != "" && errors.Is(err, fileops.ErrRepositoryNotCloned) {
+				return nil, fileops.ErrWorktreeNotFound
+			}
 			return nil, err
 		}
 
 		return handler(ctx, runContext)
 	}
-
-	return NewCommandRunner(filter, sh, handlerWithRepoExistenceVerifier)
 }
 
 type RunContext struct {
 	Manager *settings.Manager
 	Config  *config.Config
-	Repo    *model.Repo
-	Args    []string
+	// Repo is the repository to process. If a worktree is targeted, its path is the worktree directory
+	Repo *model.Repo
+	// Worktree is the name of the targeted worktree, or empty if the repository directory is processed
+	Worktree string
+	Args     []string
 }
 
-func newRunContext(manager *settings.Manager, conf *config.Config, args []string, repo settings.Repo) *RunContext {
+func newRunContext(
+	manager *settings.Manager, conf *config.Config, filter *Filter, args []string, repo settings.Repo,
+) *RunContext {
+	path := filepath.Join(conf.ReposDirectory, repo.Name)
+	if filter.Worktree != "" {
+		path = conf.WorktreePath(repo.Name, filter.Worktree)
+	}
+
 	return &RunContext{
 		Manager: manager,
 		Config:  conf,
 		Repo: &model.Repo{
 			Name: repo.Name,
-			Path: filepath.Join(conf.ReposDirectory, repo.Name),
+			Path: path,
 			Url:  repo.Url,
+			Tags: repo.Tags,
 		},
-		Args: args,
+		Worktree: filter.Worktree,
+		Args:     args,
 	}
 }
 
 // savePreviousGroup saves a group with a constant name `previous`. If such one exists, it gets recreated
 func savePreviousGroup(manager *settings.Manager, repos []string) error {
-	sets, err := manager.Read()
-	if err != nil {
-		return err
-	}
-
-	if sets.GroupExists(settings.PreviousGroupName) {
-		err := sets.RemoveGroup(settings.PreviousGroupName)
-		if err != nil {
-			return err
-		}
-	}
-
-	group, err := sets.AddGroup(settings.PreviousGroupName)
-	if err != nil {
-		return err
-	}
+	return manager.Update(
+		func(sets *settings.Settings) error {
+			if sets.GroupExists(settings.PreviousGroupName) {
+				err := sets.RemoveGroup(settings.PreviousGroupName)
+				if err != nil {
+					return err
+				}
+			}
 
-	for _, repoName := range repos {
-		err := sets.AddRepoToGroup(group, repoName)
-		if err != nil {
-			return err
-		}
-	}
+			group, err := sets.AddGroup(settings.PreviousGroupName)
+			if err != nil {
+				return err
+			}
 
-	err = manager.Write(sets)
-	if err != nil {
-		return err
-	}
+			for _, repoName := range repos {
+				err := sets.AddRepoToGroup(group, repoName)
+				if err != nil {
+					return err
+				}
+			}
 
-	return nil
+			return nil
+		},
+	)
 }
 
-func logOutput(writer io.Writer, result map[string]ProcessResult) error {
+func logOutput(writer io.Writer, entityName string, result map[string]ProcessResult) error {
 	logrus.WithField("count", len(result)).Debug("processed repos")
 
 	valueToLog := map[string]output.EntityInfo{}
@@ -180,7 +215,7 @@ func logOutput(writer io.Writer, result map[string]ProcessResult) error {
 		}
 	}
 
-	err := output.Write(writer, "repo", valueToLog)
+	err := output.Write(writer, entityName, valueToLog)
 	if err != nil {
 		return err
 	}
@@ -194,3 +229,7 @@ type ProcessResult struct {
 }
 
 type RepoHandler func(ctx context.Context, runContext *RunContext) (interface{}, error)
+
+// ResultGrouper converts results of the repositories to results of other entities, like authors.
+// It returns the name of the entities and their results
+type ResultGrouper func(reposResult map[string]ProcessResult) (string, map[string]ProcessResult)
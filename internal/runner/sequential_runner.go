@@ -22,7 +22,7 @@ func (r *SequentialRunner) Run(
 	allReposResult := map[string]ProcessResult{}
 	logrus.WithField("mode", r.config.RunMode).Debug("processing repositories")
 	for _, repo := range repos {
-		runContext := newRunContext(r.manager, r.config, r.args, repo)
+		runContext := newRunContext(r.manager, r.config, r.filter, r.args, repo)
 		select {
 		case <-ctx.Done():
 			logrus.WithField("repo", runContext.Repo.Name).Debug("processing skipped")
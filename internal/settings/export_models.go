@@ -10,11 +10,39 @@ const (
 	ExportImportStatusUpToDate ExportImportStatus = iota
 	ExportImportStatusAdded
 	ExportImportStatusRemoved
+	// ExportImportStatusUpdated the repository exists in both lists with different values, and the target one is updated
+	ExportImportStatusUpdated
+	// ExportImportStatusConflict the repository exists in both lists with different values, and the local one is kept
+	ExportImportStatusConflict
 )
 
+// ExportImportResult describes how a repository or a group differs between two lists and what was done with it
+type ExportImportResult struct {
+	Status ExportImportStatus
+	// Fields lists the fields that differ between the local and the other list
+	Fields []string
+}
+
+const (
+	repoFieldUrl  = "url"
+	repoFieldTags = "tags"
+
+	groupFieldRepos = "repos"
+)
+
+// GroupResultKeyPrefix distinguishes groups from repositories in export and import results
+const GroupResultKeyPrefix = "group:"
+
+// GroupResultKey returns a key of the group in export and import results
+func GroupResultKey(groupName string) string {
+	return GroupResultKeyPrefix + groupName
+}
+
+// exportModel is the latest version of the export file model.
+// Older versions are upgraded to it on read
 type exportModel struct {
 	Version int         `yaml:"version"`
-	Data    modelDataV1 `yaml:"data"`
+	Data    modelDataV2 `yaml:"data"`
 }
 
 type modelDataV1 struct {
@@ -26,6 +54,43 @@ type modelDataV1Repo struct {
 	Tags []string `yaml:"tags"`
 }
 
-func (r modelDataV1Repo) Equals(other modelDataV1Repo) bool {
-	return r.Url == other.Url && slices.Equal(r.Tags, other.Tags)
+type modelDataV2 struct {
+	Repos  map[string]modelDataV2Repo  `yaml:"repos"`
+	Groups map[string]modelDataV2Group `yaml:"groups"`
+}
+
+type modelDataV2Repo struct {
+	Url  string   `yaml:"url"`
+	Tags []string `yaml:"tags"`
+}
+
+type modelDataV2Group struct {
+	Repos []string `yaml:"repos"`
+}
+
+func (g modelDataV2Group) Equals(other modelDataV2Group) bool {
+	return slices.Equal(slices.Sorted(slices.Values(g.Repos)), slices.Sorted(slices.Values(other.Repos)))
+}
+
+// upgradeV1ToV2 keeps the repositories as is. V1 didn't support groups, so there are none
+func upgradeV1ToV2(data modelDataV1) modelDataV2 {
+	result := modelDataV2{
+		Repos:  map[string]modelDataV2Repo{},
+		Groups: map[string]modelDataV2Group{},
+	}
+	for repoName, repo := range data.Repos {
+		result.Repos[repoName] = modelDataV2Repo{Url: repo.Url, Tags: repo.Tags}
+	}
+
+	return result
+}
+
+func newEmptyExportModel() *exportModel {
+	return &exportModel{
+		Version: currentVersion,
+		Data: modelDataV2{
+			Repos:  map[string]modelDataV2Repo{},
+			Groups: map[string]modelDataV2Group{},
+		},
+	}
 }
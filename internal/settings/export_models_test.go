@@ -0,0 +1,75 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExportModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    *exportModel
+	}{
+		{
+			name:    "v1 is upgraded",
+			fixture: "export_v1.yaml",
+			want: &exportModel{
+				Version: currentVersion,
+				Data: modelDataV2{
+					Repos: map[string]modelDataV2Repo{
+						"api": {Url: "https://example.com/tenant/api.git", Tags: []string{"backend"}},
+					},
+					Groups: map[string]modelDataV2Group{},
+				},
+			},
+		},
+		{
+			name:    "v2",
+			fixture: "export_v2.yaml",
+			want: &exportModel{
+				Version: currentVersion,
+				Data: modelDataV2{
+					Repos: map[string]modelDataV2Repo{
+						"api": {Url: "https://example.com/tenant/api.git", Tags: []string{"backend"}},
+					},
+					Groups: map[string]modelDataV2Group{
+						"services": {Repos: []string{"api"}},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				fileBytes, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				got, err := parseExportModel(fileBytes)
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.want, got)
+				}
+			},
+		)
+	}
+}
+
+func TestParseExportModel_UnsupportedVersion(t *testing.T) {
+	_, err := parseExportModel([]byte("version: 999\ndata: {}\n"))
+	if assert.Error(t, err) {
+		assert.Equal(t, "version 999 is not supported", err.Error())
+	}
+}
+
+func TestParseExportModel_MalformedVersion(t *testing.T) {
+	_, err := parseExportModel([]byte("data: {}\n"))
+	if assert.Error(t, err) {
+		assert.Equal(t, "malformed version: <nil>", err.Error())
+	}
+}
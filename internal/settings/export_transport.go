@@ -0,0 +1,257 @@
+package settings
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/mih-kopylov/bulker/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Transport is a storage the repositories are exported to and imported from.
+// The content is an exported model of any supported version, the transport doesn't interpret it
+type Transport interface {
+	// Read returns the currently stored content, or nil if nothing is stored yet
+	Read() ([]byte, error)
+	// Write stores the content. It returns false if the stored content is the same and nothing was changed
+	Write(content []byte) (bool, error)
+	// String describes the transport for logging and errors
+	String() string
+}
+
+const exportImportFileName = "repos.yaml"
+
+// StdStreamFileName is a file name that makes FileTransport read from stdin and write to stdout
+const StdStreamFileName = "-"
+
+// GitTransportOptions configures where in the remote git repository the exported repositories are stored
+type GitTransportOptions struct {
+	// Branch to read the file from and to commit to. The remote default branch is used if empty
+	Branch string
+	// Path of the file relative to the repository root. Defaults to "repos.yaml"
+	Path string
+	// CommitMessage of the export commit
+	CommitMessage string
+	// PushBranch is a new branch the export commit is pushed to instead of Branch,
+	// so that the change can be reviewed before it's merged
+	PushBranch string
+}
+
+// GitTransport stores the exported repositories in a remote git repository.
+// The repository is cloned to a temporary directory, that is deleted on Close
+type GitTransport struct {
+	sh            shell.Shell
+	remoteRepoUrl string
+	options       GitTransportOptions
+	repoDir       string
+	cleanupFunc   func()
+}
+
+// NewGitTransport clones the remote repository. Even if an error is returned, the transport should be closed
+func NewGitTransport(sh shell.Shell, remoteRepoUrl string, options GitTransportOptions) (*GitTransport, error) {
+	if options.Path == "" {
+		options.Path = exportImportFileName
+	}
+	if !filepath.IsLocal(options.Path) {
+		return nil, fmt.Errorf("path %v must be relative to the repository root", options.Path)
+	}
+	if options.CommitMessage == "" {
+		options.CommitMessage = "Export bulker repositories"
+	}
+
+	repoDirectory, err := os.MkdirTemp("", "bulker_remote_repo_*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	logrus.WithField("repo", remoteRepoUrl).WithField("directory", repoDirectory).Debug("temporary directory created")
+
+	transport := &GitTransport{
+		sh:            sh,
+		remoteRepoUrl: remoteRepoUrl,
+		options:       options,
+		repoDir:       repoDirectory,
+		cleanupFunc: func() {
+			logrus.WithField("directory", repoDirectory).Debug("temporary directory deleted")
+			err := os.RemoveAll(repoDirectory)
+			if err != nil {
+				logrus.Warnf("can't remove temp directory %v: %v", repoDirectory, err)
+			}
+		},
+	}
+
+	output, err := sh.RunCommand(repoDirectory, "git", "clone", remoteRepoUrl, ".")
+	if err != nil {
+		logrus.WithField("repo", remoteRepoUrl).WithField("output", output).Debug("clone failed")
+		return transport, fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	if options.Branch != "" {
+		output, err := sh.RunCommand(repoDirectory, "git", "checkout", options.Branch)
+		if err != nil {
+			return transport, fmt.Errorf("failed to checkout branch %v: %v, %w", options.Branch, output, err)
+		}
+	}
+
+	return transport, nil
+}
+
+func (t *GitTransport) Read() ([]byte, error) {
+	return readFileIfExists(t.fileName())
+}
+
+func (t *GitTransport) Write(content []byte) (bool, error) {
+	err := os.MkdirAll(filepath.Dir(t.fileName()), os.ModePerm)
+	if err != nil {
+		return false, err
+	}
+
+	err = os.WriteFile(t.fileName(), content, os.ModePerm)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = t.sh.RunCommand(t.repoDir, "git", "add", t.options.Path)
+	if err != nil {
+		return false, err
+	}
+
+	// exits with 1 if there are staged changes
+	output, err := t.sh.RunCommand(t.repoDir, "git", "diff", "--cached", "--quiet")
+	if err == nil {
+		return false, nil
+	}
+	if shell.ExitCode(err) != 1 {
+		return false, fmt.Errorf("failed to check staged changes: %v, %w", output, err)
+	}
+
+	if t.options.PushBranch != "" {
+		output, err := t.sh.RunCommand(t.repoDir, "git", "checkout", "-b", t.options.PushBranch)
+		if err != nil {
+			return false, fmt.Errorf("failed to create branch %v: %v, %w", t.options.PushBranch, output, err)
+		}
+	}
+
+	_, err = t.sh.RunCommand(t.repoDir, "git", "commit", "-m", t.options.CommitMessage)
+	if err != nil {
+		return false, err
+	}
+
+	pushArguments := []string{"push"}
+	if t.options.PushBranch != "" {
+		pushArguments = append(pushArguments, "--set-upstream", "origin", t.options.PushBranch)
+	}
+	output, err = t.sh.RunCommand(t.repoDir, "git", pushArguments...)
+	if err != nil {
+		logrus.Errorf("push failed: %v, %v", output, err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (t *GitTransport) String() string {
+	if t.options.Branch != "" {
+		return fmt.Sprintf("%v in %v branch of %v", t.options.Path, t.options.Branch, t.remoteRepoUrl)
+	}
+	return fmt.Sprintf("%v in %v", t.options.Path, t.remoteRepoUrl)
+}
+
+// Close deletes the cloned repository
+func (t *GitTransport) Close() {
+	t.cleanupFunc()
+}
+
+func (t *GitTransport) fileName() string {
+	return filepath.Join(t.repoDir, t.options.Path)
+}
+
+// FileTransport stores the exported repositories in a local file.
+// If the file name is StdStreamFileName, the content is read from stdin and written to stdout
+type FileTransport struct {
+	fileName string
+	stdin    io.Reader
+	stdout   io.Writer
+}
+
+// NewFileTransport creates a transport for the file. The standard streams are only used
+// when the file name is StdStreamFileName, either of them may be nil if it is not needed
+func NewFileTransport(fileName string, stdin io.Reader, stdout io.Writer) *FileTransport {
+	return &FileTransport{
+		fileName: fileName,
+		stdin:    stdin,
+		stdout:   stdout,
+	}
+}
+
+func (t *FileTransport) Read() ([]byte, error) {
+	if t.isStdStream() {
+		if t.stdin == nil {
+			return nil, nil
+		}
+
+		content, err := io.ReadAll(t.stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return content, nil
+	}
+
+	return readFileIfExists(t.fileName)
+}
+
+func (t *FileTransport) Write(content []byte) (bool, error) {
+	if t.isStdStream() {
+		if t.stdout == nil {
+			return false, errors.New("writing to the standard stream is not supported")
+		}
+
+		_, err := t.stdout.Write(content)
+		if err != nil {
+			return false, fmt.Errorf("failed to write stdout: %w", err)
+		}
+		return true, nil
+	}
+
+	existingContent, err := readFileIfExists(t.fileName)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(existingContent, content) {
+		return false, nil
+	}
+
+	err = utils.WriteFileAtomic(t.fileName, content)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (t *FileTransport) String() string {
+	if t.isStdStream() {
+		return "standard stream"
+	}
+	return t.fileName
+}
+
+func (t *FileTransport) isStdStream() bool {
+	return t.fileName == StdStreamFileName
+}
+
+func readFileIfExists(fileName string) ([]byte, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,271 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// MergeStrategy defines how imported repositories are merged with the local ones.
+// It implements Value in spf13/pflag for custom flag type
+type MergeStrategy string
+
+const (
+	// MergeTheirs makes the imported list the only source of truth.
+	// Conflicting repositories take imported values, local-only repositories are removed
+	MergeTheirs MergeStrategy = "theirs"
+	// MergeOurs only adds new repositories. Conflicting repositories keep local values
+	MergeOurs MergeStrategy = "ours"
+	// MergeUnion adds new repositories and merges tags of the existing ones.
+	// Repositories with different URLs keep local values
+	MergeUnion MergeStrategy = "union"
+)
+
+func (s *MergeStrategy) String() string {
+	return string(*s)
+}
+
+func (s *MergeStrategy) Set(v string) error {
+	switch v {
+	case string(MergeTheirs), string(MergeOurs), string(MergeUnion):
+		*s = MergeStrategy(v)
+		return nil
+	default:
+		return fmt.Errorf("must be one of '%s' '%s' '%s'", MergeTheirs, MergeOurs, MergeUnion)
+	}
+}
+
+func (s *MergeStrategy) Type() string {
+	return "MergeStrategy"
+}
+
+// mergeImported merges the imported model into the settings according to the strategy
+func mergeImported(settings *Settings, imported *exportModel, strategy MergeStrategy) (
+	map[string]ExportImportResult, error,
+) {
+	result := map[string]ExportImportResult{}
+	local := fromSettings(settings)
+
+	for repoName, importedRepo := range imported.Data.Repos {
+		localRepo, exists := local.Data.Repos[repoName]
+		if !exists {
+			err := settings.AddRepo(repoName, importedRepo.Url, slices.Clone(importedRepo.Tags))
+			if err != nil {
+				return nil, err
+			}
+			result[repoName] = ExportImportResult{Status: ExportImportStatusAdded}
+			continue
+		}
+
+		fields := differentFields(localRepo, importedRepo)
+		if len(fields) == 0 {
+			result[repoName] = ExportImportResult{Status: ExportImportStatusUpToDate}
+			continue
+		}
+
+		repoResult, err := mergeRepo(settings, repoName, localRepo, importedRepo, fields, strategy)
+		if err != nil {
+			return nil, err
+		}
+		result[repoName] = repoResult
+	}
+
+	if strategy == MergeTheirs {
+		err := removeLocalOnlyRepos(settings, local, imported, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := mergeImportedGroups(settings, imported, strategy, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func removeLocalOnlyRepos(
+	settings *Settings, local *exportModel, imported *exportModel, result map[string]ExportImportResult,
+) error {
+	for repoName := range local.Data.Repos {
+		if _, exists := imported.Data.Repos[repoName]; exists {
+			continue
+		}
+
+		for i := range settings.Groups {
+			group := &settings.Groups[i]
+			if slices.Contains(group.Repos, repoName) {
+				err := settings.RemoveRepoFromGroup(group, repoName)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		err := settings.RemoveRepo(repoName)
+		if err != nil {
+			return err
+		}
+		result[repoName] = ExportImportResult{Status: ExportImportStatusRemoved}
+	}
+
+	return nil
+}
+
+// mergeImportedGroups merges imported groups into the settings. Local-only groups are always kept.
+// Imported groups with repositories that are missing in the merged settings are reported as conflicts
+// and not changed. Such groups come from lists exported after the repositories were removed
+func mergeImportedGroups(
+	settings *Settings, imported *exportModel, strategy MergeStrategy, result map[string]ExportImportResult,
+) error {
+	for groupName, importedGroup := range imported.Data.Groups {
+		if groupName == PreviousGroupName {
+			continue
+		}
+
+		key := GroupResultKey(groupName)
+		hasMissingRepos := slices.ContainsFunc(
+			importedGroup.Repos, func(repoName string) bool {
+				return !settings.RepoExists(repoName)
+			},
+		)
+		group, err := settings.GetGroup(groupName)
+		if errors.Is(err, ErrGroupNotFound) {
+			if hasMissingRepos {
+				result[key] = ExportImportResult{Status: ExportImportStatusConflict, Fields: []string{groupFieldRepos}}
+				continue
+			}
+
+			group, err = settings.AddGroup(groupName)
+			if err != nil {
+				return err
+			}
+
+			err = addReposToGroup(settings, group, importedGroup.Repos)
+			if err != nil {
+				return err
+			}
+
+			result[key] = ExportImportResult{Status: ExportImportStatusAdded}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if importedGroup.Equals(modelDataV2Group{Repos: group.Repos}) {
+			result[key] = ExportImportResult{Status: ExportImportStatusUpToDate}
+			continue
+		}
+
+		fields := []string{groupFieldRepos}
+		if hasMissingRepos {
+			result[key] = ExportImportResult{Status: ExportImportStatusConflict, Fields: fields}
+			continue
+		}
+
+		switch strategy {
+		case MergeTheirs:
+			group.Repos = []string{}
+			err := addReposToGroup(settings, group, importedGroup.Repos)
+			if err != nil {
+				return err
+			}
+			result[key] = ExportImportResult{Status: ExportImportStatusUpdated, Fields: fields}
+		case MergeOurs:
+			result[key] = ExportImportResult{Status: ExportImportStatusConflict, Fields: fields}
+		case MergeUnion:
+			missingRepos := slices.DeleteFunc(
+				slices.Clone(importedGroup.Repos), func(repoName string) bool {
+					return slices.Contains(group.Repos, repoName)
+				},
+			)
+			if len(missingRepos) == 0 {
+				result[key] = ExportImportResult{Status: ExportImportStatusUpToDate}
+				continue
+			}
+
+			err := addReposToGroup(settings, group, missingRepos)
+			if err != nil {
+				return err
+			}
+			result[key] = ExportImportResult{Status: ExportImportStatusUpdated, Fields: fields}
+		default:
+			return fmt.Errorf("merge strategy %v is not supported", strategy)
+		}
+	}
+
+	return nil
+}
+
+func addReposToGroup(settings *Settings, group *Group, repoNames []string) error {
+	for _, repoName := range repoNames {
+		err := settings.AddRepoToGroup(group, repoName)
+		if err != nil {
+			return fmt.Errorf("failed to add repository %v to group %v: %w", repoName, group.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func mergeRepo(
+	settings *Settings, repoName string, localRepo modelDataV2Repo, importedRepo modelDataV2Repo, fields []string,
+	strategy MergeStrategy,
+) (ExportImportResult, error) {
+	switch strategy {
+	case MergeTheirs:
+		err := settings.UpdateRepoUrl(repoName, importedRepo.Url)
+		if err != nil {
+			return ExportImportResult{}, err
+		}
+
+		err = settings.SetRepoTags(repoName, importedRepo.Tags)
+		if err != nil {
+			return ExportImportResult{}, err
+		}
+
+		return ExportImportResult{Status: ExportImportStatusUpdated, Fields: fields}, nil
+	case MergeOurs:
+		return ExportImportResult{Status: ExportImportStatusConflict, Fields: fields}, nil
+	case MergeUnion:
+		if slices.Contains(fields, repoFieldUrl) {
+			return ExportImportResult{Status: ExportImportStatusConflict, Fields: fields}, nil
+		}
+
+		missingTags := slices.DeleteFunc(
+			slices.Clone(importedRepo.Tags), func(tag string) bool {
+				return slices.Contains(localRepo.Tags, tag)
+			},
+		)
+		if len(missingTags) == 0 {
+			return ExportImportResult{Status: ExportImportStatusUpToDate}, nil
+		}
+
+		err := settings.AddRepoTags(repoName, missingTags)
+		if err != nil {
+			return ExportImportResult{}, err
+		}
+
+		return ExportImportResult{Status: ExportImportStatusUpdated, Fields: fields}, nil
+	default:
+		return ExportImportResult{}, fmt.Errorf("merge strategy %v is not supported", strategy)
+	}
+}
+
+// differentFields returns names of the fields that differ. Tags order is not taken into account
+func differentFields(a modelDataV2Repo, b modelDataV2Repo) []string {
+	var result []string
+	if a.Url != b.Url {
+		result = append(result, repoFieldUrl)
+	}
+
+	aTags := slices.Sorted(slices.Values(a.Tags))
+	bTags := slices.Sorted(slices.Values(b.Tags))
+	if !slices.Equal(aTags, bTags) {
+		result = append(result, repoFieldTags)
+	}
+
+	return result
+}
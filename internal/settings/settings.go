@@ -6,8 +6,10 @@ import (
 )
 
 type Settings struct {
-	Repos  []Repo  `yaml:"repos"`
-	Groups []Group `yaml:"groups"`
+	// Version of the settings file schema. Older files are migrated to the current version on read
+	Version int     `yaml:"version"`
+	Repos   []Repo  `yaml:"repos"`
+	Groups  []Group `yaml:"groups"`
 }
 
 type Repo struct {
@@ -62,6 +64,87 @@ func (s *Settings) RemoveRepo(name string) error {
 	return nil
 }
 
+// RenameRepo changes the repository name and updates all the groups the repository belongs to
+func (s *Settings) RenameRepo(name string, newName string) error {
+	repoIndex := s.getRepoIndex(name)
+	if repoIndex < 0 {
+		return ErrRepoNotFound
+	}
+
+	if s.RepoExists(newName) {
+		return ErrRepoAlreadyExists
+	}
+
+	s.Repos[repoIndex].Name = newName
+
+	for i := range s.Groups {
+		group := &s.Groups[i]
+		for j, repoName := range group.Repos {
+			if repoName == name {
+				group.Repos[j] = newName
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Settings) UpdateRepoUrl(name string, url string) error {
+	return s.updateRepo(
+		name, func(repo *Repo) {
+			repo.Url = url
+		},
+	)
+}
+
+func (s *Settings) SetRepoTags(name string, tags []string) error {
+	return s.updateRepo(
+		name, func(repo *Repo) {
+			repo.Tags = slices.Clone(tags)
+		},
+	)
+}
+
+// AddRepoTags adds tags to the repository. Tags that the repository already has are ignored
+func (s *Settings) AddRepoTags(name string, tags []string) error {
+	return s.updateRepo(
+		name, func(repo *Repo) {
+			for _, tag := range tags {
+				if !slices.Contains(repo.Tags, tag) {
+					repo.Tags = append(repo.Tags, tag)
+				}
+			}
+		},
+	)
+}
+
+// RemoveRepoTags removes tags from the repository. Tags that the repository doesn't have are ignored
+func (s *Settings) RemoveRepoTags(name string, tags []string) error {
+	return s.updateRepo(
+		name, func(repo *Repo) {
+			repo.Tags = slices.DeleteFunc(
+				repo.Tags, func(tag string) bool {
+					return slices.Contains(tags, tag)
+				},
+			)
+		},
+	)
+}
+
+func (s *Settings) updateRepo(name string, update func(repo *Repo)) error {
+	repoIndex := s.getRepoIndex(name)
+	if repoIndex < 0 {
+		return ErrRepoNotFound
+	}
+
+	update(&s.Repos[repoIndex])
+	if s.Repos[repoIndex].Tags == nil {
+		s.Repos[repoIndex].Tags = []string{}
+	}
+
+	return nil
+}
+
 func (s *Settings) GetRepo(name string) (*Repo, error) {
 	repoIndex := s.getRepoIndex(name)
 	if repoIndex < 0 {
@@ -1,12 +1,12 @@
 package settings
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/mih-kopylov/bulker/internal/config"
 	"github.com/mih-kopylov/bulker/internal/shell"
@@ -15,7 +15,8 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-const currentVersion = 1
+// currentVersion is the version of the export file model
+const currentVersion = 2
 
 type Manager struct {
 	conf *config.Config
@@ -29,15 +30,72 @@ func NewManager(conf *config.Config, sh shell.Shell) *Manager {
 	}
 }
 
+// Read reads the settings file, creating an empty one if it doesn't exist yet
 func (sm *Manager) Read() (*Settings, error) {
+	lock, err := sm.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer sm.unlock(lock)
+
+	return sm.read()
+}
+
+// Write replaces the settings file content with the provided settings
+func (sm *Manager) Write(settings *Settings) error {
+	lock, err := sm.lock()
+	if err != nil {
+		return err
+	}
+	defer sm.unlock(lock)
+
+	return sm.write(settings)
+}
+
+// Update reads the settings, applies the function to them and writes the result back.
+// The settings file stays locked for the whole cycle, so concurrent bulker processes don't overwrite
+// each other's changes. If the function returns an error, the settings file is left untouched
+func (sm *Manager) Update(update func(settings *Settings) error) error {
+	lock, err := sm.lock()
+	if err != nil {
+		return err
+	}
+	defer sm.unlock(lock)
+
+	settings, err := sm.read()
+	if err != nil {
+		return err
+	}
+
+	err = update(settings)
+	if err != nil {
+		return err
+	}
+
+	return sm.write(settings)
+}
+
+func (sm *Manager) lock() (*utils.FileLock, error) {
 	settingsFileName := sm.conf.SettingsFileName
 
-	settingsDirectory := filepath.Dir(settingsFileName)
-	err := os.MkdirAll(settingsDirectory, os.ModePerm)
+	err := os.MkdirAll(filepath.Dir(settingsFileName), os.ModePerm)
 	if err != nil {
 		return nil, err
 	}
 
+	return utils.LockFile(settingsFileName + ".lock")
+}
+
+func (sm *Manager) unlock(lock *utils.FileLock) {
+	err := lock.Unlock()
+	if err != nil {
+		logrus.WithField("file", sm.conf.SettingsFileName).Warnf("failed to unlock settings: %v", err)
+	}
+}
+
+func (sm *Manager) read() (*Settings, error) {
+	settingsFileName := sm.conf.SettingsFileName
+
 	exists, err := utils.Exists(settingsFileName)
 	if err != nil {
 		return nil, err
@@ -45,7 +103,7 @@ func (sm *Manager) Read() (*Settings, error) {
 
 	if !exists {
 		newInstance := &Settings{}
-		err := sm.Write(newInstance)
+		err := sm.write(newInstance)
 		if err != nil {
 			return nil, err
 		}
@@ -53,24 +111,61 @@ func (sm *Manager) Read() (*Settings, error) {
 		return newInstance, nil
 	}
 
-	result := &Settings{}
-
 	fileContent, err := os.ReadFile(settingsFileName)
 	if err != nil {
 		return nil, err
 	}
 
+	fileContent, migrated, err := migrateSettings(fileContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate settings file %v: %w", settingsFileName, err)
+	}
+
+	result := &Settings{}
 	err = yaml.Unmarshal(fileContent, result)
 	if err != nil {
 		return nil, err
 	}
 
+	if migrated {
+		err = sm.backup()
+		if err != nil {
+			return nil, err
+		}
+
+		err = sm.write(result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
 
-func (sm *Manager) Write(settings *Settings) error {
+// backup copies the settings file as is, so that a user can restore it if a migration goes wrong
+func (sm *Manager) backup() error {
+	settingsFileName := sm.conf.SettingsFileName
+
+	fileContent, err := os.ReadFile(settingsFileName)
+	if err != nil {
+		return err
+	}
+
+	backupFileName := fmt.Sprintf("%v.%v.bak", settingsFileName, time.Now().Format("20060102150405"))
+	err = utils.WriteFileAtomic(backupFileName, fileContent)
+	if err != nil {
+		return fmt.Errorf("failed to backup settings file: %w", err)
+	}
+
+	logrus.WithField("file", backupFileName).Info("settings file migrated, the previous version is backed up")
+	return nil
+}
+
+func (sm *Manager) write(settings *Settings) error {
 	settingsFileName := sm.conf.SettingsFileName
 
+	settings.Version = currentSettingsVersion
+
 	// make sure all data is sorted alphabetically
 	slices.SortStableFunc(
 		settings.Repos, func(a Repo, b Repo) int {
@@ -96,183 +191,205 @@ func (sm *Manager) Write(settings *Settings) error {
 		return err
 	}
 
-	err = os.WriteFile(settingsFileName, fileContent, os.ModePerm)
-	return err
+	return utils.WriteFileAtomic(settingsFileName, fileContent)
 }
 
-func (sm *Manager) Export(remoteRepoUrl string) (map[string]ExportImportStatus, error) {
-	logrus.WithField("remote", remoteRepoUrl).Debug("exporting repositories")
-	settings, err := sm.Read()
+// Export writes the supported repositories to the remote git repository
+func (sm *Manager) Export(remoteRepoUrl string, options GitTransportOptions) (map[string]ExportImportResult, error) {
+	transport, err := NewGitTransport(sm.sh, remoteRepoUrl, options)
+	if transport != nil {
+		defer transport.Close()
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	repoDir, cleanupFunc, err := sm.cloneRepo(remoteRepoUrl)
-	if cleanupFunc != nil {
-		defer cleanupFunc()
-	}
+	return sm.ExportTo(transport)
+}
+
+// ExportTo writes the supported repositories with the transport.
+// The result describes how the exported content changed comparing to the previous one
+func (sm *Manager) ExportTo(transport Transport) (map[string]ExportImportResult, error) {
+	logrus.WithField("transport", transport.String()).Debug("exporting repositories")
+	settings, err := sm.Read()
 	if err != nil {
 		return nil, err
 	}
 
 	settingsModel := fromSettings(settings)
-	jsonBytes, err := yaml.Marshal(settingsModel)
+	exportBytes, err := yaml.Marshal(settingsModel)
 	if err != nil {
 		return nil, err
 	}
 
-	exportFileName := filepath.Join(repoDir, exportImportFileName)
-	var fileModel *exportModel
-	fileExists, err := utils.Exists(exportFileName)
+	existingBytes, err := transport.Read()
 	if err != nil {
 		return nil, err
 	}
 
-	if fileExists {
-		fileModel, err = readExistingModel(exportFileName)
+	var fileModel *exportModel
+	if existingBytes != nil {
+		fileModel, err = parseExportModel(existingBytes)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		fileModel = &exportModel{
-			Version: currentVersion,
-			Data:    modelDataV1{map[string]modelDataV1Repo{}},
-		}
+		fileModel = newEmptyExportModel()
 	}
 
-	err = os.WriteFile(exportFileName, jsonBytes, os.ModePerm)
+	// no change means the previous content is the same, so the result reports all the entries as up-to-date
+	_, err = transport.Write(exportBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = sm.sh.RunCommand(repoDir, "git", "add", ".")
+	result := prepareResult(fileModel, settingsModel)
+
+	return result, nil
+}
+
+// Import merges the repositories from the remote git repository into the supported ones
+func (sm *Manager) Import(remoteRepoUrl string, options GitTransportOptions, strategy MergeStrategy) (
+	map[string]ExportImportResult, error,
+) {
+	transport, err := NewGitTransport(sm.sh, remoteRepoUrl, options)
+	if transport != nil {
+		defer transport.Close()
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	statusOutput, err := sm.sh.RunCommand(repoDir, "git", "status")
+	return sm.ImportFrom(transport, strategy)
+}
+
+// ImportFrom merges the repositories read with the transport into the supported ones
+func (sm *Manager) ImportFrom(transport Transport, strategy MergeStrategy) (map[string]ExportImportResult, error) {
+	logrus.WithField("transport", transport.String()).
+		WithField("strategy", strategy).
+		Debug("importing repositories")
+	importBytes, err := transport.Read()
 	if err != nil {
 		return nil, err
 	}
-
-	if strings.Contains(statusOutput, "nothing to commit") {
-		result := map[string]ExportImportStatus{}
-		for _, repo := range settings.Repos {
-			result[repo.Name] = ExportImportStatusUpToDate
-		}
-		return result, nil
+	if importBytes == nil {
+		return nil, fmt.Errorf("%v not found", transport.String())
 	}
 
-	_, err = sm.sh.RunCommand(repoDir, "git", "commit", "-m", "Export bulker repositories")
+	fileModel, err := parseExportModel(importBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	output, err := sm.sh.RunCommand(repoDir, "git", "push")
+	var result map[string]ExportImportResult
+	err = sm.Update(
+		func(settings *Settings) error {
+			result, err = mergeImported(settings, fileModel, strategy)
+			return err
+		},
+	)
 	if err != nil {
-		logrus.Errorf("push failed: %v, %v", output, err)
 		return nil, err
 	}
 
-	result := prepareResult(fileModel, settingsModel)
-
 	return result, nil
 }
 
-func (sm *Manager) Import(remoteRepoUrl string) (map[string]ExportImportStatus, error) {
-	logrus.WithField("remote", remoteRepoUrl).Debug("importing repositories")
-	settings, err := sm.Read()
-	if err != nil {
-		return nil, err
-	}
-
-	repoDir, cleanupFunc, err := sm.cloneRepo(remoteRepoUrl)
-	if cleanupFunc != nil {
-		defer cleanupFunc()
+// Diff compares the supported repositories with the ones stored in the remote git repository
+func (sm *Manager) Diff(remoteRepoUrl string, options GitTransportOptions) (map[string]ExportImportResult, error) {
+	transport, err := NewGitTransport(sm.sh, remoteRepoUrl, options)
+	if transport != nil {
+		defer transport.Close()
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	settingsModel := fromSettings(settings)
-	importFileName := filepath.Join(repoDir, exportImportFileName)
-	fileModel, err := readExistingModel(importFileName)
+	return sm.DiffWith(transport)
+}
+
+// DiffWith compares the supported repositories with the ones read with the transport.
+// The result describes how the local repositories would change if they were replaced with the read ones
+func (sm *Manager) DiffWith(transport Transport) (map[string]ExportImportResult, error) {
+	logrus.WithField("transport", transport.String()).Debug("comparing repositories")
+	sourceBytes, err := transport.Read()
 	if err != nil {
 		return nil, err
 	}
+	if sourceBytes == nil {
+		return nil, fmt.Errorf("%v not found", transport.String())
+	}
 
-	importedSettings, err := toSettings(fileModel)
+	sourceModel, err := parseExportModel(sourceBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	err = sm.Write(importedSettings)
+	settings, err := sm.Read()
 	if err != nil {
 		return nil, err
 	}
-	result := prepareResult(settingsModel, fileModel)
 
-	return result, nil
+	return prepareResult(fromSettings(settings), sourceModel), nil
 }
 
-const exportImportFileName = "repos.yaml"
-
-func (sm *Manager) cloneRepo(remoteRepoUrl string) (repoDir string, cleanupFunc func(), err error) {
-	repoDirectory, err := os.MkdirTemp("", "bulker_remote_repo_*")
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-	cleanupFunc = func() {
-		logrus.WithField("directory", repoDirectory).Debug("temporary directory deleted")
-		err := os.RemoveAll(repoDirectory)
-		if err != nil {
-			logrus.Warnf("can't remove temp directory %v: %v", repoDirectory, err)
+// prepareResult describes how the new model differs from the previous one
+func prepareResult(previousModel *exportModel, newModel *exportModel) map[string]ExportImportResult {
+	result := map[string]ExportImportResult{}
+	for repoName, newRepo := range newModel.Data.Repos {
+		previousRepo, exists := previousModel.Data.Repos[repoName]
+		if !exists {
+			result[repoName] = ExportImportResult{Status: ExportImportStatusAdded}
+			continue
 		}
-	}
-	logrus.WithField("repo", remoteRepoUrl).WithField("directory", repoDirectory).Debug("temporary directory created")
 
-	output, err := sm.sh.RunCommand(repoDirectory, "git", "clone", remoteRepoUrl, ".")
-	if err != nil {
-		logrus.WithField("repo", remoteRepoUrl).WithField("output", output).Debug("clone failed")
-		return "", cleanupFunc, fmt.Errorf("failed to clone repository: %w", err)
-	}
-	return repoDirectory, cleanupFunc, nil
-}
-
-func prepareResult(previousModel *exportModel, newModel *exportModel) map[string]ExportImportStatus {
-	result := map[string]ExportImportStatus{}
-	for repoName := range newModel.Data.Repos {
-		if _, exists := previousModel.Data.Repos[repoName]; exists {
-			result[repoName] = ExportImportStatusUpToDate
+		fields := differentFields(previousRepo, newRepo)
+		if len(fields) > 0 {
+			result[repoName] = ExportImportResult{Status: ExportImportStatusUpdated, Fields: fields}
 		} else {
-			result[repoName] = ExportImportStatusAdded
+			result[repoName] = ExportImportResult{Status: ExportImportStatusUpToDate}
 		}
 	}
 	for repoName := range previousModel.Data.Repos {
 		if _, exists := newModel.Data.Repos[repoName]; !exists {
-			result[repoName] = ExportImportStatusRemoved
+			result[repoName] = ExportImportResult{Status: ExportImportStatusRemoved}
 		}
 	}
-	return result
-}
-
-func readExistingModel(exportFileName string) (*exportModel, error) {
-	fileBytes, err := os.ReadFile(exportFileName)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("file %s not found", exportFileName)
+	for groupName, newGroup := range newModel.Data.Groups {
+		previousGroup, exists := previousModel.Data.Groups[groupName]
+		if !exists {
+			result[GroupResultKey(groupName)] = ExportImportResult{Status: ExportImportStatusAdded}
+			continue
 		}
 
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		if previousGroup.Equals(newGroup) {
+			result[GroupResultKey(groupName)] = ExportImportResult{Status: ExportImportStatusUpToDate}
+		} else {
+			result[GroupResultKey(groupName)] = ExportImportResult{
+				Status: ExportImportStatusUpdated,
+				Fields: []string{groupFieldRepos},
+			}
+		}
+	}
+	for groupName := range previousModel.Data.Groups {
+		if _, exists := newModel.Data.Groups[groupName]; !exists {
+			result[GroupResultKey(groupName)] = ExportImportResult{Status: ExportImportStatusRemoved}
+		}
 	}
+	return result
+}
 
+func parseExportModel(fileBytes []byte) (*exportModel, error) {
 	var readVersionModel map[string]any
-	err = yaml.Unmarshal(fileBytes, &readVersionModel)
+	err := yaml.Unmarshal(fileBytes, &readVersionModel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshall file content: %w", err)
 	}
 
-	version := readVersionModel["version"].(int)
+	version, ok := readVersionModel["version"].(int)
+	if !ok {
+		return nil, fmt.Errorf("malformed version: %v", readVersionModel["version"])
+	}
 
 	data := readVersionModel["data"]
 	dataBytes, err := yaml.Marshal(data)
@@ -288,6 +405,20 @@ func readExistingModel(exportFileName string) (*exportModel, error) {
 			return nil, err
 		}
 
+		return &exportModel{Version: currentVersion, Data: upgradeV1ToV2(resultData)}, nil
+	case 2:
+		var resultData modelDataV2
+		err := yaml.Unmarshal(dataBytes, &resultData)
+		if err != nil {
+			return nil, err
+		}
+		if resultData.Repos == nil {
+			resultData.Repos = map[string]modelDataV2Repo{}
+		}
+		if resultData.Groups == nil {
+			resultData.Groups = map[string]modelDataV2Group{}
+		}
+
 		return &exportModel{Version: version, Data: resultData}, nil
 	default:
 		return nil, fmt.Errorf("version %v is not supported", version)
@@ -295,26 +426,20 @@ func readExistingModel(exportFileName string) (*exportModel, error) {
 }
 
 func fromSettings(settings *Settings) *exportModel {
-	data := modelDataV1{}
-	data.Repos = map[string]modelDataV1Repo{}
+	result := newEmptyExportModel()
 	for _, repo := range settings.Repos {
-		data.Repos[repo.Name] = modelDataV1Repo{
+		result.Data.Repos[repo.Name] = modelDataV2Repo{
 			Url:  repo.Url,
 			Tags: repo.Tags,
 		}
 	}
-
-	return &exportModel{currentVersion, data}
-}
-
-func toSettings(em *exportModel) (*Settings, error) {
-	result := Settings{[]Repo{}, []Group{}}
-	for repoName, repoData := range em.Data.Repos {
-		err := result.AddRepo(repoName, repoData.Url, repoData.Tags)
-		if err != nil {
-			return nil, err
+	for _, group := range settings.Groups {
+		if group.Name == PreviousGroupName {
+			// the group is recreated by each command, so it makes no sense to share it
+			continue
 		}
+		result.Data.Groups[group.Name] = modelDataV2Group{Repos: group.Repos}
 	}
 
-	return &result, nil
+	return result
 }
@@ -0,0 +1,138 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/shell"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestManager(t *testing.T, settingsFileName string) *Manager {
+	t.Helper()
+	return NewManager(&config.Config{SettingsFileName: settingsFileName}, &shell.NativeShell{})
+}
+
+func TestManager_UpdateConcurrently(t *testing.T) {
+	settingsFileName := filepath.Join(t.TempDir(), "settings.yaml")
+	const count = 50
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each goroutine uses its own manager, the same way separate bulker processes do
+			manager := newTestManager(t, settingsFileName)
+			errs <- manager.Update(
+				func(settings *Settings) error {
+					name := fmt.Sprintf("repo%02d", i)
+					err := settings.AddRepo(name, "https://example.com/"+name, []string{})
+					if err != nil {
+						return err
+					}
+
+					if settings.GroupExists(PreviousGroupName) {
+						err = settings.RemoveGroup(PreviousGroupName)
+						if err != nil {
+							return err
+						}
+					}
+					group, err := settings.AddGroup(PreviousGroupName)
+					if err != nil {
+						return err
+					}
+					return settings.AddRepoToGroup(group, name)
+				},
+			)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	sets, err := newTestManager(t, settingsFileName).Read()
+	if assert.NoError(t, err) {
+		assert.Len(t, sets.Repos, count)
+		for i := 0; i < count; i++ {
+			assert.True(t, sets.RepoExists(fmt.Sprintf("repo%02d", i)))
+		}
+		if assert.Len(t, sets.Groups, 1) {
+			assert.Len(t, sets.Groups[0].Repos, 1)
+		}
+	}
+}
+
+func TestManager_UpdateErrorKeepsFile(t *testing.T) {
+	settingsFileName := filepath.Join(t.TempDir(), "settings.yaml")
+	manager := newTestManager(t, settingsFileName)
+	err := manager.Write(&Settings{Repos: []Repo{{Name: "repo", Url: "https://example.com", Tags: []string{}}}})
+	assert.NoError(t, err)
+
+	err = manager.Update(
+		func(settings *Settings) error {
+			settings.Repos = nil
+			return ErrRepoNotFound
+		},
+	)
+	assert.ErrorIs(t, err, ErrRepoNotFound)
+
+	sets, err := manager.Read()
+	if assert.NoError(t, err) {
+		assert.True(t, sets.RepoExists("repo"))
+	}
+}
+
+func TestManager_WriteLeavesNoTempFiles(t *testing.T) {
+	directory := t.TempDir()
+	settingsFileName := filepath.Join(directory, "settings.yaml")
+	manager := newTestManager(t, settingsFileName)
+
+	for i := 0; i < 3; i++ {
+		err := manager.Write(&Settings{})
+		assert.NoError(t, err)
+	}
+
+	entries, err := os.ReadDir(directory)
+	if assert.NoError(t, err) {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		assert.ElementsMatch(t, []string{"settings.yaml", "settings.yaml.lock"}, names)
+	}
+}
+
+func TestManager_ExportToUnchanged(t *testing.T) {
+	directory := t.TempDir()
+	manager := newTestManager(t, filepath.Join(directory, "settings.yaml"))
+	err := manager.Write(
+		&Settings{
+			Repos:  []Repo{{Name: "repo", Url: "https://example.com", Tags: []string{}}},
+			Groups: []Group{{Name: "group", Repos: []string{"repo"}}},
+		},
+	)
+	assert.NoError(t, err)
+
+	exportFileName := filepath.Join(directory, "export.yaml")
+	_, err = manager.ExportTo(NewFileTransport(exportFileName, nil, nil))
+	assert.NoError(t, err)
+
+	result, err := manager.ExportTo(NewFileTransport(exportFileName, nil, nil))
+	if assert.NoError(t, err) {
+		assert.Equal(
+			t, map[string]ExportImportResult{
+				"repo":                  {Status: ExportImportStatusUpToDate},
+				GroupResultKey("group"): {Status: ExportImportStatusUpToDate},
+			}, result,
+		)
+	}
+}
@@ -0,0 +1,86 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// currentSettingsVersion is the version of the settings file schema that bulker reads and writes
+const currentSettingsVersion = 1
+
+// settingsMigration upgrades a raw settings file content from one version to the next one
+type settingsMigration func(content map[string]any) error
+
+// settingsMigrations are indexed by the version they upgrade from.
+// Each new schema version needs a new migration appended to the list
+var settingsMigrations = []settingsMigration{
+	migrateSettingsV0ToV1,
+}
+
+// migrateSettingsV0ToV1 upgrades files written before the version field was introduced.
+// The structure of repositories and groups stays the same
+func migrateSettingsV0ToV1(_ map[string]any) error {
+	return nil
+}
+
+// migrateSettings upgrades the settings file content to the current version.
+// It returns the upgraded content and a flag whether any migration was applied
+func migrateSettings(fileContent []byte) ([]byte, bool, error) {
+	content := map[string]any{}
+	err := yaml.Unmarshal(fileContent, &content)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshall file content: %w", err)
+	}
+	if content == nil {
+		// an empty file is considered as an empty settings of the current version
+		return fileContent, false, nil
+	}
+
+	version, err := readSettingsVersion(content)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if version > currentSettingsVersion {
+		return nil, false, fmt.Errorf(
+			"version %v is not supported, the latest supported version is %v", version, currentSettingsVersion,
+		)
+	}
+
+	if version == currentSettingsVersion {
+		return fileContent, false, nil
+	}
+
+	for ; version < currentSettingsVersion; version++ {
+		logrus.WithField("from", version).WithField("to", version+1).Debug("migrating settings")
+		err := settingsMigrations[version](content)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to migrate from version %v: %w", version, err)
+		}
+		content["version"] = version + 1
+	}
+
+	result, err := yaml.Marshal(content)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshall migrated content: %w", err)
+	}
+
+	return result, true, nil
+}
+
+func readSettingsVersion(content map[string]any) (int, error) {
+	rawVersion, exists := content["version"]
+	if !exists {
+		// files without a version were created before the versioning was introduced
+		return 0, nil
+	}
+
+	version, ok := rawVersion.(int)
+	if !ok || version < 0 {
+		return 0, fmt.Errorf("malformed version: %v", rawVersion)
+	}
+
+	return version, nil
+}
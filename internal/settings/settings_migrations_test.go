@@ -0,0 +1,97 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prepareSettingsFile copies a fixture from testdata to a temp directory and returns the copy file name
+func prepareSettingsFile(t *testing.T, fixtureName string) string {
+	t.Helper()
+	fixture, err := os.ReadFile(filepath.Join("testdata", fixtureName))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	settingsFileName := filepath.Join(t.TempDir(), "settings.yaml")
+	err = os.WriteFile(settingsFileName, fixture, 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return settingsFileName
+}
+
+func findBackups(t *testing.T, settingsFileName string) []string {
+	t.Helper()
+	backups, err := filepath.Glob(settingsFileName + ".*.bak")
+	assert.NoError(t, err)
+	return backups
+}
+
+func expectedSettings() *Settings {
+	return &Settings{
+		Version: currentSettingsVersion,
+		Repos: []Repo{
+			{Name: "api", Url: "https://example.com/tenant/api.git", Tags: []string{"backend"}},
+			{Name: "web", Url: "https://example.com/tenant/web.git", Tags: []string{}},
+		},
+		Groups: []Group{
+			{Name: "previous", Repos: []string{"api", "web"}},
+		},
+	}
+}
+
+func TestManager_Read_MigratesV0(t *testing.T) {
+	settingsFileName := prepareSettingsFile(t, "settings_v0.yaml")
+	originalContent, err := os.ReadFile(settingsFileName)
+	assert.NoError(t, err)
+
+	sets, err := newTestManager(t, settingsFileName).Read()
+	if assert.NoError(t, err) {
+		assert.Equal(t, expectedSettings(), sets)
+	}
+
+	migratedContent, err := os.ReadFile(settingsFileName)
+	if assert.NoError(t, err) {
+		assert.True(t, strings.HasPrefix(string(migratedContent), "version: 1\n"))
+	}
+
+	backups := findBackups(t, settingsFileName)
+	if assert.Len(t, backups, 1) {
+		backupContent, err := os.ReadFile(backups[0])
+		if assert.NoError(t, err) {
+			assert.Equal(t, string(originalContent), string(backupContent))
+		}
+	}
+}
+
+func TestManager_Read_CurrentVersion(t *testing.T) {
+	settingsFileName := prepareSettingsFile(t, "settings_v1.yaml")
+
+	sets, err := newTestManager(t, settingsFileName).Read()
+	if assert.NoError(t, err) {
+		assert.Equal(t, expectedSettings(), sets)
+	}
+
+	assert.Empty(t, findBackups(t, settingsFileName))
+}
+
+func TestManager_Read_UnsupportedVersion(t *testing.T) {
+	settingsFileName := prepareSettingsFile(t, "settings_unsupported.yaml")
+
+	_, err := newTestManager(t, settingsFileName).Read()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "version 999 is not supported")
+	}
+
+	assert.Empty(t, findBackups(t, settingsFileName))
+}
+
+func TestMigrateSettings_AllVersionsHaveMigration(t *testing.T) {
+	assert.Len(t, settingsMigrations, currentSettingsVersion)
+}
@@ -1,8 +1,21 @@
 package shell
 
+import "errors"
+
 type Shell interface {
 	RunCommand(commandRootDirectory string, command string, arguments ...string) (string, error)
 }
 
 type NativeShell struct {
 }
+
+// ExitCode returns the exit code of a failed command.
+// Returns -1 if the error is not caused by a non-zero exit code
+func ExitCode(err error) int {
+	var exitError interface{ ExitCode() int }
+	if errors.As(err, &exitError) {
+		return exitError.ExitCode()
+	}
+
+	return -1
+}
@@ -0,0 +1,20 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	sh := &NativeShell{}
+
+	_, err := sh.RunCommand("", "git", "diff", "--no-index", "--quiet", "shell.go", "shell_test.go")
+	if got := ExitCode(err); got != 1 {
+		t.Errorf("ExitCode() of a failed command = %v, want 1", got)
+	}
+
+	if got := ExitCode(fmt.Errorf("wrapped: %w", errors.New("not an exit error"))); got != -1 {
+		t.Errorf("ExitCode() of an arbitrary error = %v, want -1", got)
+	}
+}
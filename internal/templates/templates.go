@@ -0,0 +1,75 @@
+package templates
+
+import (
+	"fmt"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/pkg/props"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Help describes the template syntax for command descriptions
+const Help = `Go text/template syntax is supported, see https://pkg.go.dev/text/template. Available values:
+* {{.Name}} - name of the repository
+* {{.Url}} - URL of the repository
+* {{.Tags}} - tags of the repository, use {{join .Tags ","}} to print them
+* {{.Ref}} - current branch of the repository, or commit if the HEAD is detached
+* {{.Prop "pom.xml" "$.parent.version"}} - property value from a file in the repository`
+
+var functions = template.FuncMap{
+	"join": strings.Join,
+}
+
+// RepoData is the value that templates are rendered with.
+// Values that require reading the repository are calculated only when the template uses them
+type RepoData struct {
+	gitService *gitops.GitService
+	repo       *model.Repo
+}
+
+func (d *RepoData) Name() string {
+	return d.repo.Name
+}
+
+func (d *RepoData) Url() string {
+	return d.repo.Url
+}
+
+func (d *RepoData) Tags() []string {
+	return d.repo.Tags
+}
+
+func (d *RepoData) Ref() (string, error) {
+	status, err := d.gitService.Status(d.repo)
+	if err != nil {
+		return "", err
+	}
+
+	return status.Ref, nil
+}
+
+func (d *RepoData) Prop(fileName string, propertyPath string) (string, error) {
+	return props.GetPropertyFromFile(filepath.Join(d.repo.Path, fileName), propertyPath)
+}
+
+// Render renders the template text for the repository. A text without template actions is returned as is
+func Render(gitService *gitops.GitService, repo *model.Repo, text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("").Funcs(functions).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	result := strings.Builder{}
+	err = tmpl.Execute(&result, &RepoData{gitService: gitService, repo: repo})
+	if err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return result.String(), nil
+}
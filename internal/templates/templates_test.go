@@ -0,0 +1,62 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mih-kopylov/bulker/internal/config"
+	"github.com/mih-kopylov/bulker/internal/gitops"
+	"github.com/mih-kopylov/bulker/internal/model"
+	"github.com/mih-kopylov/bulker/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	repoPath := t.TempDir()
+	require.NoError(
+		t, os.WriteFile(filepath.Join(repoPath, "package.json"), []byte(`{"version": "1.2.3"}`), os.ModePerm),
+	)
+	repo := &model.Repo{Name: "repo", Path: repoPath, Url: "https://example.com/repo.git", Tags: []string{"a", "b"}}
+	sh := tests.MockShellMap(
+		map[string]tests.MockResult{
+			"git status --porcelain=v2 --branch": {Output: "# branch.oid 0123456789abcdef\n# branch.head feature"},
+		},
+	)
+
+	gitService := gitops.NewGitService(sh, config.GitBackendExec)
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+		wantErr  string
+	}{
+		{name: "plain text", text: "fix: {not a template}", expected: "fix: {not a template}"},
+		{name: "name", text: "chore({{.Name}}): update", expected: "chore(repo): update"},
+		{name: "url", text: "{{.Url}}", expected: "https://example.com/repo.git"},
+		{name: "tags", text: `{{join .Tags ","}}`, expected: "a,b"},
+		{name: "ref", text: "{{.Ref}}-next", expected: "feature-next"},
+		{name: "prop", text: `bump to {{.Prop "package.json" "$.version"}}`, expected: "bump to 1.2.3"},
+		{name: "malformed", text: "{{.Name", wantErr: "failed to parse template"},
+		{name: "unknown value", text: "{{.Version}}", wantErr: "failed to render template"},
+		{name: "missing prop", text: `{{.Prop "package.json" "$.missing"}}`, wantErr: "property not found"},
+	}
+	for _, test := range testCases {
+		t.Run(
+			test.name, func(t *testing.T) {
+				result, err := Render(&gitService, repo, test.text)
+				if test.wantErr != "" {
+					if assert.Error(t, err) {
+						assert.Contains(t, err.Error(), test.wantErr)
+					}
+					return
+				}
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.expected, result)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// GitEnvironment is a set of environment variables that change git console output
+type GitEnvironment struct {
+	Name      string
+	Variables map[string]string
+	// Localized environments make git print translated messages
+	Localized bool
+}
+
+// GitEnvironments lists environments bulker is expected to work in.
+// Translated messages are printed only if the locale and git translations are installed,
+// localized environments are skipped otherwise
+var GitEnvironments = []GitEnvironment{
+	{
+		Name:      "english",
+		Variables: map[string]string{"LC_ALL": "C", "LANG": "C", "LANGUAGE": ""},
+	},
+	{
+		Name:      "german",
+		Variables: map[string]string{"LC_ALL": "de_DE.UTF-8", "LANG": "de_DE.UTF-8", "LANGUAGE": "de"},
+		Localized: true,
+	},
+	{
+		Name:      "russian",
+		Variables: map[string]string{"LC_ALL": "ru_RU.UTF-8", "LANG": "ru_RU.UTF-8", "LANGUAGE": "ru"},
+		Localized: true,
+	},
+	{
+		Name:      "chinese",
+		Variables: map[string]string{"LC_ALL": "zh_CN.UTF-8", "LANG": "zh_CN.UTF-8", "LANGUAGE": "zh_CN"},
+		Localized: true,
+	},
+	{
+		Name: "short status",
+		Variables: map[string]string{
+			"GIT_CONFIG_COUNT":   "2",
+			"GIT_CONFIG_KEY_0":   "status.short",
+			"GIT_CONFIG_VALUE_0": "true",
+			"GIT_CONFIG_KEY_1":   "status.branch",
+			"GIT_CONFIG_VALUE_1": "true",
+		},
+	},
+	{
+		Name: "colors",
+		Variables: map[string]string{
+			"GIT_CONFIG_COUNT":   "1",
+			"GIT_CONFIG_KEY_0":   "color.ui",
+			"GIT_CONFIG_VALUE_0": "always",
+		},
+	},
+}
+
+// Set sets the environment variables and the git identity for the duration of the test.
+// The test is skipped if the environment is localized, but git prints English messages in it
+func (e GitEnvironment) Set(t *testing.T) {
+	for name, value := range e.Variables {
+		t.Setenv(name, value)
+	}
+	if e.Localized {
+		// a directory outside of any repository makes git fail with a translated message
+		command := exec.Command("git", "status")
+		command.Dir = t.TempDir()
+		output, _ := command.CombinedOutput()
+		if strings.Contains(string(output), "not a git repository") {
+			t.Skipf("git messages are not translated in %v environment, the locale is not installed", e.Name)
+		}
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// HostingRequest is a request received by a fake hosting server
+type HostingRequest struct {
+	Method        string
+	Path          string
+	Query         string
+	Authorization string
+	Body          map[string]interface{}
+}
+
+// StartHostingServer starts a fake hosting API that records requests. The server responds with the response
+// of the "METHOD path" key, and with 404 status if there is no such key
+func StartHostingServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]HostingRequest) {
+	var requests []HostingRequest
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				received := HostingRequest{
+					Method:        request.Method,
+					Path:          request.URL.EscapedPath(),
+					Query:         request.URL.RawQuery,
+					Authorization: request.Header.Get("Authorization") + request.Header.Get("PRIVATE-TOKEN"),
+				}
+				body, err := io.ReadAll(request.Body)
+				assert.NoError(t, err)
+				if len(body) > 0 {
+					assert.NoError(t, json.Unmarshal(body, &received.Body))
+				}
+				requests = append(requests, received)
+
+				response, found := responses[request.Method+" "+received.Path]
+				if !found {
+					http.NotFound(writer, request)
+					return
+				}
+				_, err = writer.Write([]byte(response))
+				assert.NoError(t, err)
+			},
+		),
+	)
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// PrepareHosting starts a fake hosting API with StartHostingServer
+// and configures bulker to use it for the duration of the test
+func PrepareHosting(t *testing.T, responses map[string]string) {
+	server, _ := StartHostingServer(t, responses)
+	viper.Set("hostingApiUrl", server.URL)
+	t.Cleanup(
+		func() {
+			viper.Set("hostingApiUrl", "")
+		},
+	)
+}
@@ -43,3 +43,20 @@ func MockShellMap(handlerValues map[string]MockResult) shell.Shell {
 func MockShellEmpty() shell.Shell {
 	return MockShellMap(map[string]MockResult{})
 }
+
+type mockExitError struct {
+	code int
+}
+
+func (e *mockExitError) Error() string {
+	return fmt.Sprintf("exit status %v", e.code)
+}
+
+func (e *mockExitError) ExitCode() int {
+	return e.code
+}
+
+// MockExitError returns an error of a command that exited with the code
+func MockExitError(code int) error {
+	return &mockExitError{code: code}
+}
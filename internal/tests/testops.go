@@ -11,6 +11,8 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	config2 "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/mih-kopylov/bulker/internal/config"
 	"github.com/mih-kopylov/bulker/internal/settings"
 	"github.com/mih-kopylov/bulker/internal/shell"
@@ -144,3 +146,25 @@ func GetFileContent(repoPath string, fileName string) (string, error) {
 
 	return fileContent, nil
 }
+
+func GetBranchCommit(repoPath string, branch string) (*object.Commit, error) {
+	repository, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repo: repoPath=%v, err=%w", repoPath, err)
+	}
+
+	branchReference, err := repository.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch: repoPath=%v, branch=%v, err=%w", repoPath, branch, err)
+	}
+
+	commit, err := repository.CommitObject(branchReference.Hash())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to find branch commit: repoPath=%v, branch=%v, commit=%v, err=%w", repoPath, branch,
+			branchReference.Hash().String(), err,
+		)
+	}
+
+	return commit, nil
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FileLock is an exclusive advisory lock held on a file. It synchronizes different bulker processes,
+// as well as different goroutines of the same process, as long as each of them opens the lock file on its own
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile creates the lock file if it doesn't exist and blocks until an exclusive lock on it is acquired
+func LockFile(fileName string) (*FileLock, error) {
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	err = lockFile(file)
+	if err != nil {
+		closeErr := file.Close()
+		if closeErr != nil {
+			logrus.WithField("file", fileName).Warnf("failed to close lock file: %v", closeErr)
+		}
+		return nil, fmt.Errorf("failed to lock file: %w", err)
+	}
+
+	return &FileLock{file: file}, nil
+}
+
+// Unlock releases the lock. The lock file itself is kept in place to be reused by the next lock
+func (l *FileLock) Unlock() error {
+	err := unlockFile(l.file)
+	if err != nil {
+		return fmt.Errorf("failed to unlock file: %w", err)
+	}
+
+	return l.file.Close()
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
 	"os"
@@ -61,3 +62,41 @@ func EmptyDir(path string) (bool, error) {
 	}
 	return false, err
 }
+
+// WriteFileAtomic writes data to a temporary file in the same directory and then renames it to the target name.
+// Readers never observe a partially written file, and the previous content survives a crash in the middle of writing
+func WriteFileAtomic(fileName string, data []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempFileName := tempFile.Name()
+
+	cleanup := func() {
+		err := os.Remove(tempFileName)
+		if err != nil && !os.IsNotExist(err) {
+			logrus.WithField("file", tempFileName).Warnf("failed to remove temp file: %v", err)
+		}
+	}
+
+	_, err = tempFile.Write(data)
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	closeErr := tempFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	err = os.Rename(tempFileName, fileName)
+	if err != nil {
+		cleanup()
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+
+	return nil
+}
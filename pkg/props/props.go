@@ -36,6 +36,51 @@ func GetPropertyFromFile(fileName string, propertyPath string) (string, error) {
 	return prop.Text, nil
 }
 
+// SetPropertyInFile replaces the property value in the file keeping the rest of the file content as is
+func SetPropertyInFile(fileName string, propertyPath string, value string) error {
+	pp, err := ParsePath(propertyPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse path expression: %w", err)
+	}
+
+	parser, err := getFileParser(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create file parser: %w", err)
+	}
+
+	fileInfo, err := os.Stat(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	fileBytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	prop, err := parser.GetProperty(fileBytes, pp)
+	if err != nil {
+		return errors.Wrap(
+			ErrPropertyNotFound, fmt.Sprintf(
+				"failed to get property: fileName=%v path=%v %v", fileName,
+				propertyPath, err.Error(),
+			),
+		)
+	}
+
+	var updatedBytes []byte
+	updatedBytes = append(updatedBytes, fileBytes[:prop.Start]...)
+	updatedBytes = append(updatedBytes, value...)
+	updatedBytes = append(updatedBytes, fileBytes[prop.End:]...)
+
+	err = os.WriteFile(fileName, updatedBytes, fileInfo.Mode())
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
 func getFileParser(fileName string) (FileParser, error) {
 	if strings.HasSuffix(fileName, ".json") {
 		return &JsonFileParser{}, nil
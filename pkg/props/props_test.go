@@ -2,7 +2,11 @@ package props
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -29,6 +33,41 @@ func TestGetPropertyFromFile(t *testing.T) {
 	}
 }
 
+func TestSetPropertyInFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		path     string
+	}{
+		{name: "json", fileName: "data.json", path: "$.nested.string"},
+		{name: "xml", fileName: "data.xml", path: "$.nested.string"},
+		{name: "yaml", fileName: "data.yaml", path: "$.nested.string"},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				original, err := os.ReadFile(filepath.Join("testdata", test.fileName))
+				require.NoError(t, err)
+				fileName := filepath.Join(t.TempDir(), test.fileName)
+				require.NoError(t, os.WriteFile(fileName, original, os.ModePerm))
+
+				err = SetPropertyInFile(fileName, test.path, "updatedValue")
+				require.NoError(t, err)
+
+				prop, err := GetPropertyFromFile(fileName, test.path)
+				if assert.NoError(t, err) {
+					assert.Equal(t, "updatedValue", prop)
+				}
+				updated, err := os.ReadFile(fileName)
+				require.NoError(t, err)
+				assert.Equal(
+					t, strings.Replace(string(original), "nestedStringValue", "updatedValue", 1), string(updated),
+				)
+			},
+		)
+	}
+}
+
 func TestGetFileParser(t *testing.T) {
 	tests := []struct {
 		name          string